@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,14 +19,20 @@ import (
 	"github.com/GustavoLR548/godot-news-bot/internal/news"
 	"github.com/GustavoLR548/godot-news-bot/internal/ratelimit"
 	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/GustavoLR548/godot-news-bot/internal/storage/migrations"
 	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
 )
 
 const (
-	defaultMaxChannels          = 5
+	defaultMaxChannels          = 0 // No global cap; guilds are limited by their quotas
+	defaultGuildMaxChannels     = 5
+	defaultGuildMaxFeeds        = 10
+	defaultGuildMaxRepos        = 5
 	defaultCheckIntervalMinutes = 15
+	defaultMaxArticlesPerCheck  = 5
+	defaultFeedWorkers          = 3
 	rssURL                      = "https://godotengine.org/rss.xml"
 )
 
@@ -38,9 +48,11 @@ func main() {
 		log.Fatal("DISCORD_TOKEN is required")
 	}
 
-	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
-	if geminiAPIKey == "" {
-		log.Fatal("GEMINI_API_KEY is required")
+	// AI providers: Gemini by default, or any OpenAI-compatible endpoint (OpenAI, Ollama, llama.cpp, ...),
+	// optionally chained so summaries fail over to the next backend
+	providerConfigs, err := aiProviderConfigs()
+	if err != nil {
+		log.Fatalf("Failed to configure AI providers: %v", err)
 	}
 
 	githubToken := os.Getenv("GITHUB_TOKEN")
@@ -54,8 +66,15 @@ func main() {
 	redisPassword := os.Getenv("REDIS_PASSWORD")
 
 	maxChannels := getEnvAsInt("MAX_CHANNELS_LIMIT", defaultMaxChannels)
+	guildQuotas := storage.Quotas{
+		Channels: getEnvAsInt("GUILD_MAX_CHANNELS", defaultGuildMaxChannels),
+		Feeds:    getEnvAsInt("GUILD_MAX_FEEDS", defaultGuildMaxFeeds),
+		Repos:    getEnvAsInt("GUILD_MAX_REPOS", defaultGuildMaxRepos),
+	}
 	checkIntervalMinutes := getEnvAsInt("CHECK_INTERVAL_MINUTES", defaultCheckIntervalMinutes)
 	checkInterval := time.Duration(checkIntervalMinutes) * time.Minute
+	maxArticlesPerCheck := getEnvAsInt("MAX_ARTICLES_PER_CHECK", defaultMaxArticlesPerCheck)
+	feedWorkers := getEnvAsInt("FEED_WORKERS", defaultFeedWorkers)
 
 	// Configure rate limiting
 	rateLimitConfig := ratelimit.Config{
@@ -69,11 +88,28 @@ func main() {
 	}
 
 	log.Printf("Starting Guara Bot (Max Channels: %d, Check Interval: %v)", maxChannels, checkInterval)
+	log.Printf("Guild Quotas: %d channels, %d feeds, %d repos (0 = unlimited)", guildQuotas.Channels, guildQuotas.Feeds, guildQuotas.Repos)
 	log.Printf("Rate Limiting: %d RPM, %d TPM, Circuit Breaker: %d failures", 
 		rateLimitConfig.MaxRequestsPerMinute, 
 		rateLimitConfig.MaxTokensPerMinute,
 		rateLimitConfig.CircuitBreakerThreshold)
 
+	// Each backend of the chain has its own rate limiter and circuit breaker. The offline
+	// extractive summarizer needs no API, so it always closes the chain.
+	aiBackends := make([]ai.Backend, 0, len(providerConfigs)+1)
+	hasExtractive := false
+	for i, config := range providerConfigs {
+		backend, err := ai.NewBackend(config, rateLimitConfig)
+		if err != nil {
+			log.Fatalf("Failed to configure AI provider %d: %v", i+1, err)
+		}
+		aiBackends = append(aiBackends, backend)
+		hasExtractive = hasExtractive || config.Provider == ai.ProviderExtractive
+	}
+	if !hasExtractive {
+		aiBackends = append(aiBackends, ai.NewExtractiveSummarizer())
+	}
+
 	// Initialize Redis client
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     redisURL,
@@ -90,15 +126,62 @@ func main() {
 	}
 	log.Println("Connected to Redis successfully")
 
+	// Create Discord session; it isn't connected yet, but migrations use its REST API
+	dg, err := discordgo.New("Bot " + discordToken)
+	if err != nil {
+		log.Fatalf("Failed to create Discord session: %v", err)
+	}
+
+	// Bring the Redis key layout up to date; MIGRATIONS_DRY_RUN=true only prints what would change
+	migrationRunner, err := migrations.NewRunner(redisClient, migrations.All(discordChannelGuild(dg)))
+	if err != nil {
+		log.Fatalf("Invalid migrations: %v", err)
+	}
+	migrationsDryRun := os.Getenv("MIGRATIONS_DRY_RUN") == "true"
+	reports, err := migrationRunner.Run(migrationsDryRun)
+	if err != nil {
+		log.Fatalf("Failed to migrate Redis schema: %v", err)
+	}
+	if migrationsDryRun {
+		printMigrationReports(reports)
+		log.Println("Migrations dry run finished; nothing was changed. Unset MIGRATIONS_DRY_RUN to apply them and start the bot.")
+		return
+	}
+
 	// Initialize storage repositories
 	channelRepo, err := storage.NewRedisChannelRepository(redisClient, maxChannels)
 	if err != nil {
 		log.Fatalf("Failed to create channel repository: %v", err)
 	}
 
+	// Leases keep several bot instances sharing this Redis from processing the same
+	// feed or repository twice; only the elected leader runs each scheduler
+	instanceID := os.Getenv("INSTANCE_ID")
+	if instanceID == "" {
+		instanceID = defaultInstanceID()
+	}
+	leaseManager := storage.NewLeaseManager(redisClient, instanceID)
+	log.Printf("Instance ID: %s", instanceID)
+
 	historyRepo := storage.NewRedisRSSHistoryRepository(redisClient)
 	feedRepo := storage.NewRedisRSSFeedRepository(redisClient)
 	githubRepo := storage.NewRedisGitHubRepository(redisClient)
+	deliveryRepo := storage.NewRedisDeliveryRepository(redisClient)
+	promptRepo := storage.NewRedisPromptRepository(redisClient)
+	quotaRepo := storage.NewRedisQuotaRepository(redisClient, guildQuotas)
+	guildRepo := storage.NewRedisGuildRepository(redisClient)
+
+	// Prompt templates replacing the built-in ones (feeds and guilds can still override them)
+	var promptTemplates map[ai.PromptKind]*ai.PromptTemplate
+	if dir := os.Getenv("PROMPT_TEMPLATES_DIR"); dir != "" {
+		promptTemplates, err = ai.LoadPromptTemplates(dir)
+		if err != nil {
+			log.Fatalf("Failed to load prompt templates: %v", err)
+		}
+		for kind, t := range promptTemplates {
+			log.Printf("Using %s prompt template from %s (version %s)", kind, dir, t.Version)
+		}
+	}
 
 	// Register default feed for backward compatibility
 	defaultFeed := storage.RSSFeed{
@@ -128,8 +211,12 @@ func main() {
 	// Initialize news fetcher
 	newsFetcher := news.NewRSSFetcher(rssURL)
 
-	// Initialize AI summarizer with rate limiting
-	aiSummarizer := ai.NewGeminiSummarizerWithRateLimit(geminiAPIKey, rateLimitConfig)
+	// Initialize AI summarizer (shared by RSS and PR summaries)
+	var aiSummarizer ai.Backend = aiBackends[0]
+	if len(aiBackends) > 1 {
+		aiSummarizer = ai.NewFailoverSummarizer(aiBackends...)
+	}
+	log.Printf("AI backends: %s", aiSummarizer.Name())
 
 	// Initialize GitHub client if token is provided
 	var githubClient *github.Client
@@ -137,32 +224,38 @@ func main() {
 	if githubToken != "" {
 		log.Println("GitHub token provided, enabling GitHub PR monitoring")
 		githubClient = github.NewClient(githubToken)
-		
-		// Create PR summarizer
-		prSummarizer := ai.NewGeminiPRSummarizer(aiSummarizer)
-		
 		// Note: githubMonitor will be initialized after Discord session is created
-		_ = prSummarizer // We'll use this later
 	} else {
 		log.Println("No GitHub token provided, GitHub PR monitoring disabled")
 	}
 
-	// Create Discord session
-	dg, err := discordgo.New("Bot " + discordToken)
-	if err != nil {
-		log.Fatalf("Failed to create Discord session: %v", err)
-	}
-
 	// Set intents
 	dg.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages
 
 	// Create command handler with GitHub repo
 	commandHandler := bot.NewCommandHandler(channelRepo, feedRepo, githubRepo, maxChannels)
+	commandHandler.SetPromptRepository(promptRepo)
+	commandHandler.SetQuotaRepository(quotaRepo)
+	commandHandler.SetGuildRepository(guildRepo)
+	commandHandler.SetDeliveryRepository(deliveryRepo)
+
+	// Bot owners manage the public catalog of feeds and repositories and use /owner
+	ownerUserIDs := bot.ParseOwnerUserIDs(os.Getenv("OWNER_USER_IDS"))
+	ownerGuildID := strings.TrimSpace(os.Getenv("OWNER_GUILD_ID"))
+	if len(ownerUserIDs) == 0 && ownerGuildID == "" {
+		log.Println("WARNING: OWNER_USER_IDS and OWNER_GUILD_ID not set, catalog entries and /owner can't be used from Discord")
+	}
+	commandHandler.SetOwnerUserIDs(ownerUserIDs)
+	commandHandler.SetOwnerGuildID(ownerGuildID)
 
 	// Initialize GitHub monitor if enabled
 	if githubClient != nil {
-		prSummarizer := ai.NewGeminiPRSummarizer(aiSummarizer)
-		githubMonitor = bot.NewGitHubMonitor(dg, githubClient, githubRepo, prSummarizer)
+		githubMonitor = bot.NewGitHubMonitor(dg, githubClient, githubRepo, aiSummarizer)
+		githubMonitor.SetLeaseManager(leaseManager)
+		githubMonitor.SetDeliveryRepository(deliveryRepo)
+		githubMonitor.SetPromptRepository(promptRepo)
+		githubMonitor.SetPromptTemplates(promptTemplates)
+		githubMonitor.SetGuildRepository(guildRepo)
 	}
 
 	// Register commands and handlers
@@ -197,6 +290,14 @@ func main() {
 		feedRepo,
 		checkInterval,
 	)
+	newsBot.SetMaxArticlesPerCheck(maxArticlesPerCheck)
+	newsBot.SetFeedWorkers(feedWorkers)
+	newsBot.SetLeaseManager(leaseManager)
+	newsBot.SetDeliveryRepository(deliveryRepo)
+	newsBot.SetSummaryCache(storage.NewRedisSummaryCache(redisClient))
+	newsBot.SetPromptRepository(promptRepo)
+	newsBot.SetPromptTemplates(promptTemplates)
+	newsBot.SetGuildRepository(guildRepo)
 
 	// Connect bot to command handler
 	commandHandler.SetBot(newsBot)
@@ -210,9 +311,11 @@ func main() {
 	go newsBot.Start()
 
 	// Start GitHub monitoring if enabled
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
 	if githubMonitor != nil {
 		log.Println("Starting GitHub PR monitoring...")
-		go githubMonitor.Start(context.Background())
+		go githubMonitor.Start(monitorCtx)
 	}
 
 	// Wait for interrupt signal
@@ -222,14 +325,73 @@ func main() {
 
 	log.Println("Shutting down...")
 	newsBot.Stop()
+	stopMonitor()
+	if githubMonitor != nil {
+		githubMonitor.Stop()
+	}
+
+	// Close the AI clients
+	if closer, ok := aiSummarizer.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("Error closing AI clients: %v", err)
+		}
+	}
 	
 	// Close Redis connection
 	if err := redisClient.Close(); err != nil {
 		log.Printf("Error closing Redis connection: %v", err)
 	}
 	
-	// Cleanup commands (optional, but good practice)
-	cleanupCommands(dg)
+	// Cleanup commands (optional, but good practice). Disable it when running
+	// several instances, otherwise a stopping instance removes the commands of the others.
+	if os.Getenv("CLEANUP_COMMANDS") != "false" {
+		cleanupCommands(dg)
+	}
+}
+
+// defaultInstanceID identifies this bot instance by hostname and process ID
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// discordChannelGuild resolves the guild of a channel with the Discord API. Channels that no
+// longer exist or the bot can no longer access have no guild.
+func discordChannelGuild(dg *discordgo.Session) migrations.ChannelGuildResolver {
+	return func(ctx context.Context, channelID string) (string, error) {
+		channel, err := dg.Channel(channelID, discordgo.WithContext(ctx))
+		var restErr *discordgo.RESTError
+		if errors.As(err, &restErr) && restErr.Message != nil {
+			switch restErr.Message.Code {
+			case discordgo.ErrCodeUnknownChannel, discordgo.ErrCodeMissingAccess:
+				return "", nil
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+		return channel.GuildID, nil
+	}
+}
+
+// printMigrationReports prints the changes a migrations dry run would make
+func printMigrationReports(reports []migrations.Report) {
+	if len(reports) == 0 {
+		fmt.Println("No pending migrations.")
+		return
+	}
+	for _, report := range reports {
+		fmt.Printf("Migration %d: %s\n", report.Version, report.Description)
+		if len(report.Changes) == 0 {
+			fmt.Println("  (no changes)")
+		}
+		for _, change := range report.Changes {
+			fmt.Printf("  - %s\n", change)
+		}
+	}
 }
 
 // getEnvAsInt retrieves an environment variable as an integer with a default value
@@ -267,3 +429,43 @@ func cleanupCommands(s *discordgo.Session) {
 		}
 	}
 }
+
+// aiProviderConfigs reads the AI backends from the environment: AI_PROVIDER_CHAIN lists
+// "provider[:model]" entries in failover order, otherwise AI_PROVIDER selects a single one.
+// Entries without a model use GEMINI_MODEL or OPENAI_MODEL. Without any configuration
+// and without a Gemini key, articles are summarized offline.
+func aiProviderConfigs() ([]ai.ProviderConfig, error) {
+	spec := os.Getenv("AI_PROVIDER_CHAIN")
+	if spec == "" {
+		spec = os.Getenv("AI_PROVIDER")
+	}
+	if spec == "" {
+		spec = ai.ProviderGemini
+		if os.Getenv("GEMINI_API_KEY") == "" {
+			log.Println("Warning: GEMINI_API_KEY not set, using offline extractive summaries")
+			spec = ai.ProviderExtractive
+		}
+	}
+
+	configs, err := ai.ParseProviderChain(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range configs {
+		switch configs[i].Provider {
+		case ai.ProviderGemini:
+			configs[i].APIKey = os.Getenv("GEMINI_API_KEY")
+			if configs[i].Model == "" {
+				configs[i].Model = os.Getenv("GEMINI_MODEL")
+			}
+		case ai.ProviderOpenAI:
+			configs[i].APIKey = os.Getenv("OPENAI_API_KEY")
+			configs[i].BaseURL = os.Getenv("OPENAI_BASE_URL")
+			if configs[i].Model == "" {
+				configs[i].Model = os.Getenv("OPENAI_MODEL")
+			}
+		}
+	}
+	return configs, nil
+}
@@ -25,13 +25,13 @@ func main() {
 	fmt.Println("🔍 Listing available Gemini models...")
 	fmt.Println()
 
-	// Use default rate limiting for this utility
-	summarizer := ai.NewGeminiSummarizer(geminiAPIKey)
+	provider := ai.NewGeminiProvider(geminiAPIKey, ai.DefaultGeminiModel)
+	defer provider.Close()
 	
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	models, err := summarizer.ListAvailableModels(ctx)
+	models, err := provider.ListAvailableModels(ctx)
 	if err != nil {
 		log.Fatalf("Error listing models: %v", err)
 	}
@@ -0,0 +1,317 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/github"
+)
+
+const (
+	// Extractive summaries pick between 3 and 5 sentences
+	minSummarySentences = 3
+	maxSummarySentences = 5
+	// Sentences shorter than this (in words) are headings, captions or fragments, not summary material
+	minSentenceWords = 5
+	// maxExtractiveSummaryLength keeps summaries within a Discord embed description
+	maxExtractiveSummaryLength = 1500
+
+	textRankDamping    = 0.85
+	textRankIterations = 50
+	textRankTolerance  = 1e-4
+)
+
+// ExtractiveSummarizer summarizes articles without any API by picking their most central
+// sentences with TextRank. Summaries stay in the article's language and the original title
+// is passed through untranslated, so it works offline and as the last fallback of a chain.
+type ExtractiveSummarizer struct{}
+
+// NewExtractiveSummarizer creates a new extractive summarizer
+func NewExtractiveSummarizer() *ExtractiveSummarizer {
+	return &ExtractiveSummarizer{}
+}
+
+// Name identifies the summarizer in logs
+func (s *ExtractiveSummarizer) Name() string {
+	return "extractive (TextRank)"
+}
+
+// Available always reports true: the summarizer has no quota or circuit breaker
+func (s *ExtractiveSummarizer) Available() bool {
+	return true
+}
+
+// Summarize generates an extractive summary
+func (s *ExtractiveSummarizer) Summarize(ctx context.Context, text string, originalTitle string) (*SummaryResponse, error) {
+	return s.SummarizeInLanguage(ctx, text, originalTitle, "en", StyleStandard, nil)
+}
+
+// SummarizeInLanguage generates an extractive summary of 3-5 sentences, or as many as the summary
+// style asks for. The summary is in the article's own language whatever the
+// requested language, and the title is kept as is. No prompt is rendered, so promptTemplate
+// is ignored.
+func (s *ExtractiveSummarizer) SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string, style SummaryStyle, promptTemplate *PromptTemplate) (*SummaryResponse, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("empty text provided")
+	}
+
+	style = style.orStandard()
+	summary := TextRankSummary(text, style.extractiveSentences())
+	if summary == "" {
+		return nil, fmt.Errorf("no sentences to summarize")
+	}
+	if style == StyleBullet {
+		summary = bulletList(splitSentences(summary))
+	}
+
+	return &SummaryResponse{
+		TranslatedTitle: originalTitle,
+		Summary:         summary,
+	}, nil
+}
+
+// SummarizeInLanguages returns the same extractive summary for every language
+func (s *ExtractiveSummarizer) SummarizeInLanguages(ctx context.Context, text string, originalTitle string, languageCodes []string, style SummaryStyle, promptTemplate *PromptTemplate) (map[string]*SummaryResponse, error) {
+	response, err := s.SummarizeInLanguage(ctx, text, originalTitle, "", style, promptTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(map[string]*SummaryResponse, len(languageCodes))
+	for _, code := range languageCodes {
+		copied := *response
+		responses[code] = &copied
+	}
+	return responses, nil
+}
+
+// SummarizePRBatchInLanguages returns the same PR list for every language
+func (s *ExtractiveSummarizer) SummarizePRBatchInLanguages(ctx context.Context, repoName string, prs []github.PullRequest, languageCodes []string, promptTemplate *PromptTemplate) (map[string]string, error) {
+	summary, err := s.SummarizePRBatch(ctx, repoName, prs, "", promptTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]string, len(languageCodes))
+	for _, code := range languageCodes {
+		summaries[code] = summary
+	}
+	return summaries, nil
+}
+
+// SummarizePRBatch lists the PRs grouped by category with their titles and links
+func (s *ExtractiveSummarizer) SummarizePRBatch(ctx context.Context, repoName string, prs []github.PullRequest, languageCode string, promptTemplate *PromptTemplate) (string, error) {
+	if len(prs) == 0 {
+		return "", fmt.Errorf("no PRs to summarize")
+	}
+
+	categorized := make(map[string][]github.PullRequest)
+	var categories []string
+	for _, pr := range prs {
+		category := github.CategorizePR(pr)
+		if _, ok := categorized[category]; !ok {
+			categories = append(categories, category)
+		}
+		categorized[category] = append(categorized[category], pr)
+	}
+	sort.Strings(categories)
+
+	var b strings.Builder
+	for _, category := range categories {
+		fmt.Fprintf(&b, "**%s**\n", category)
+		for _, pr := range categorized[category] {
+			fmt.Fprintf(&b, "• **[PR #%d](%s)**: %s\n", pr.Number, pr.HTMLURL, pr.Title)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// TextRankSummary returns up to maxSentences of the text's most central sentences, in their
+// original order. Sentences are ranked with TextRank: a graph of sentences weighted by shared
+// words, scored with PageRank.
+func TextRankSummary(text string, maxSentences int) string {
+	var sentences []string
+	var words [][]string
+	for _, sentence := range splitSentences(text) {
+		sentenceWords := significantWords(sentence)
+		if len(strings.Fields(sentence)) < minSentenceWords || len(sentenceWords) == 0 {
+			continue
+		}
+		sentences = append(sentences, sentence)
+		words = append(words, sentenceWords)
+	}
+	if len(sentences) == 0 {
+		return ""
+	}
+
+	count := maxSentences
+	if len(sentences) < count {
+		count = len(sentences)
+	} else if len(sentences) < 2*maxSentences && count > minSummarySentences {
+		// Short articles get shorter summaries
+		count = minSummarySentences
+	}
+
+	scores := textRank(words)
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+	// Highest score first; earlier sentences win ties
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	chosen := order[:count]
+	sort.Ints(chosen)
+
+	var b strings.Builder
+	for _, idx := range chosen {
+		sentence := sentences[idx]
+		if b.Len() > 0 && b.Len()+len(sentence)+1 > maxExtractiveSummaryLength {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(sentence)
+	}
+	return TruncateString(b.String(), maxExtractiveSummaryLength)
+}
+
+// textRank scores sentences given their significant words
+func textRank(words [][]string) []float64 {
+	n := len(words)
+	sets := make([]map[string]bool, n)
+	for i, sentenceWords := range words {
+		sets[i] = make(map[string]bool, len(sentenceWords))
+		for _, word := range sentenceWords {
+			sets[i][word] = true
+		}
+	}
+
+	// Edge weights: shared words normalized by sentence lengths (Mihalcea & Tarau, 2004)
+	weights := make([][]float64, n)
+	totals := make([]float64, n)
+	for i := range weights {
+		weights[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			shared := 0
+			for word := range sets[i] {
+				if sets[j][word] {
+					shared++
+				}
+			}
+			if shared == 0 {
+				continue
+			}
+			norm := math.Log(float64(len(sets[i]))) + math.Log(float64(len(sets[j])))
+			if norm <= 0 {
+				norm = 1
+			}
+			weights[i][j] = float64(shared) / norm
+			weights[j][i] = weights[i][j]
+			totals[i] += weights[i][j]
+			totals[j] += weights[i][j]
+		}
+	}
+
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = 1
+	}
+	for iteration := 0; iteration < textRankIterations; iteration++ {
+		next := make([]float64, n)
+		delta := 0.0
+		for i := 0; i < n; i++ {
+			sum := 0.0
+			for j := 0; j < n; j++ {
+				if weights[j][i] > 0 {
+					sum += weights[j][i] / totals[j] * scores[j]
+				}
+			}
+			next[i] = (1 - textRankDamping) + textRankDamping*sum
+			delta += math.Abs(next[i] - scores[i])
+		}
+		scores = next
+		if delta < textRankTolerance {
+			break
+		}
+	}
+	return scores
+}
+
+// bulletList formats sentences as one bullet point per line
+func bulletList(sentences []string) string {
+	lines := make([]string, len(sentences))
+	for i, sentence := range sentences {
+		lines[i] = "• " + sentence
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitSentences splits text into sentences at line breaks and at sentence-ending
+// punctuation followed by a space
+func splitSentences(text string) []string {
+	var sentences []string
+	for _, line := range strings.Split(text, "\n") {
+		start := 0
+		for i, r := range line {
+			if r != '.' && r != '!' && r != '?' {
+				continue
+			}
+			next := i + utf8.RuneLen(r)
+			if next < len(line) && line[next] != ' ' {
+				continue // decimals, versions (4.3), abbreviations inside words
+			}
+			if sentence := strings.TrimSpace(line[start:next]); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+			start = next
+		}
+		if sentence := strings.TrimSpace(line[start:]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+	return sentences
+}
+
+// significantWords returns the lowercased words of a sentence without stopwords and short words
+func significantWords(sentence string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(sentence), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	words := fields[:0]
+	for _, word := range fields {
+		if utf8.RuneCountInString(word) < 3 || stopwords[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// stopwords are common English words that say nothing about a sentence's topic
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "any": true, "can": true, "had": true, "her": true, "was": true, "one": true,
+	"our": true, "out": true, "has": true, "have": true, "his": true, "how": true, "its": true,
+	"now": true, "new": true, "who": true, "did": true, "get": true, "may": true, "him": true,
+	"this": true, "that": true, "with": true, "from": true, "they": true, "will": true,
+	"would": true, "there": true, "their": true, "what": true, "about": true, "which": true,
+	"when": true, "make": true, "like": true, "been": true, "were": true, "into": true,
+	"than": true, "them": true, "then": true, "these": true, "some": true, "also": true,
+	"more": true, "other": true, "such": true, "only": true, "over": true, "most": true,
+	"very": true, "just": true, "your": true, "well": true, "where": true, "while": true,
+	"each": true, "both": true, "being": true, "because": true, "should": true, "could": true,
+	"does": true, "here": true, "those": true, "through": true, "after": true, "before": true,
+}
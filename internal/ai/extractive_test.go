@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const extractiveArticle = `Godot 4.4 is out now with typed dictionaries, Jolt physics and faster editor startup.
+
+Typed dictionaries let GDScript check the key and value types of dictionaries.
+The editor shows typed dictionaries with their key and value types in the inspector.
+Jolt physics is now available as an alternative 3D physics engine for Godot projects.
+Games using Jolt physics get more stable stacking and faster collision detection in 3D.
+Thanks to all contributors!
+The editor startup is faster because Godot 4.4 caches imported resources more aggressively.
+Our community made this release possible with more than 500 contributors.
+Download Godot 4.4 from the website or update through Steam and itch.io today.
+We look forward to seeing the games you make with this release of Godot 4.4.
+Share your projects with the community on social media and the forums.
+The next release will focus on rendering improvements and platform support.`
+
+func TestTextRankSummary(t *testing.T) {
+	summary := TextRankSummary(extractiveArticle, 5)
+	sentences := splitSentences(summary)
+
+	assert.GreaterOrEqual(t, len(sentences), minSummarySentences)
+	assert.LessOrEqual(t, len(sentences), maxSummarySentences)
+
+	// Every sentence comes from the article, in the article's order
+	last := -1
+	for _, sentence := range sentences {
+		idx := strings.Index(extractiveArticle, sentence)
+		require.GreaterOrEqual(t, idx, 0, "sentence not in the article: %s", sentence)
+		assert.Greater(t, idx, last)
+		last = idx
+	}
+
+	// Short fragments are not summary material
+	assert.NotContains(t, summary, "Thanks to all contributors!")
+
+	// Deterministic
+	assert.Equal(t, summary, TextRankSummary(extractiveArticle, 5))
+}
+
+func TestTextRankSummary_ShortText(t *testing.T) {
+	text := "Godot 4.4 brings typed dictionaries to GDScript. Typed dictionaries make scripts safer to write."
+	assert.Equal(t, text, TextRankSummary(text, 5))
+
+	assert.Empty(t, TextRankSummary("Read more. Thanks!", 5))
+}
+
+func TestSplitSentences(t *testing.T) {
+	sentences := splitSentences("Godot 4.4 is out! Is it stable? Yes, see v4.4.stable.\nRelease notes")
+	assert.Equal(t, []string{
+		"Godot 4.4 is out!",
+		"Is it stable?",
+		"Yes, see v4.4.stable.",
+		"Release notes",
+	}, sentences)
+}
+
+func TestExtractiveSummarizer_SummarizeInLanguage(t *testing.T) {
+	summarizer := NewExtractiveSummarizer()
+
+	response, err := summarizer.SummarizeInLanguage(context.Background(), extractiveArticle, "Godot 4.4 released", "pt-BR", StyleStandard, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Godot 4.4 released", response.TranslatedTitle)
+	assert.NotEmpty(t, response.Summary)
+
+	_, err = summarizer.SummarizeInLanguage(context.Background(), "  ", "Title", "en", StyleStandard, nil)
+	assert.Error(t, err)
+
+	_, err = summarizer.SummarizeInLanguage(context.Background(), "Read more.", "Title", "en", StyleStandard, nil)
+	assert.Error(t, err)
+
+	assert.True(t, summarizer.Available())
+}
+
+func TestExtractiveSummarizer_SummaryStyles(t *testing.T) {
+	summarizer := NewExtractiveSummarizer()
+
+	tldr, err := summarizer.SummarizeInLanguage(context.Background(), extractiveArticle, "Title", "en", StyleTLDR, nil)
+	require.NoError(t, err)
+	assert.Len(t, splitSentences(tldr.Summary), 1)
+
+	bullet, err := summarizer.SummarizeInLanguage(context.Background(), extractiveArticle, "Title", "en", StyleBullet, nil)
+	require.NoError(t, err)
+	lines := strings.Split(bullet.Summary, "\n")
+	assert.GreaterOrEqual(t, len(lines), minSummarySentences)
+	for _, line := range lines {
+		assert.True(t, strings.HasPrefix(line, "• "), line)
+	}
+}
+
+func TestExtractiveSummarizer_SummarizePRBatch(t *testing.T) {
+	prs := []github.PullRequest{
+		{Number: 101, Title: "Fix crash when closing the editor", HTMLURL: "https://github.com/godotengine/godot/pull/101"},
+		{Number: 102, Title: "Add typed dictionaries", HTMLURL: "https://github.com/godotengine/godot/pull/102"},
+	}
+
+	summary, err := NewExtractiveSummarizer().SummarizePRBatch(context.Background(), "godotengine/godot", prs, "en", nil)
+	require.NoError(t, err)
+	assert.Contains(t, summary, "• **[PR #101](https://github.com/godotengine/godot/pull/101)**: Fix crash when closing the editor")
+	assert.Contains(t, summary, "• **[PR #102](https://github.com/godotengine/godot/pull/102)**: Add typed dictionaries")
+	assert.Contains(t, summary, "**"+github.CategorizePR(prs[0])+"**")
+
+	_, err = NewExtractiveSummarizer().SummarizePRBatch(context.Background(), "godotengine/godot", nil, "en", nil)
+	assert.Error(t, err)
+}
+
+func TestNewBackend(t *testing.T) {
+	backend, err := NewBackend(ProviderConfig{Provider: ProviderExtractive}, testRateLimitConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "extractive (TextRank)", backend.Name())
+
+	backend, err = NewBackend(ProviderConfig{Provider: ProviderGemini, APIKey: "key"}, testRateLimitConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "Gemini (gemini-2.5-flash)", backend.Name())
+
+	_, err = NewBackend(ProviderConfig{Provider: ProviderOpenAI}, testRateLimitConfig())
+	assert.Error(t, err)
+}
@@ -0,0 +1,190 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/github"
+)
+
+// Backend is a summarizer that can take part in a failover chain
+type Backend interface {
+	AISummarizer
+	PRSummarizer
+	// Name identifies the backend in logs
+	Name() string
+	// Available reports whether the backend currently accepts requests (its circuit breaker is closed)
+	Available() bool
+}
+
+// FailoverSummarizer tries an ordered chain of backends (e.g. Gemini flash, Gemini pro,
+// a local model) and moves down the chain when a backend's circuit breaker is open or
+// its request fails after retries
+type FailoverSummarizer struct {
+	backends []Backend
+}
+
+// NewFailoverSummarizer creates a summarizer trying the backends in order
+func NewFailoverSummarizer(backends ...Backend) *FailoverSummarizer {
+	return &FailoverSummarizer{
+		backends: backends,
+	}
+}
+
+// Name lists the backends of the chain in order
+func (f *FailoverSummarizer) Name() string {
+	names := make([]string, len(f.backends))
+	for i, backend := range f.backends {
+		names[i] = backend.Name()
+	}
+	return strings.Join(names, " → ")
+}
+
+// Available reports whether any backend of the chain accepts requests
+func (f *FailoverSummarizer) Available() bool {
+	for _, backend := range f.backends {
+		if backend.Available() {
+			return true
+		}
+	}
+	return false
+}
+
+// Close releases the resources of every backend that holds any
+func (f *FailoverSummarizer) Close() error {
+	var errs []error
+	for _, backend := range f.backends {
+		if closer, ok := backend.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", backend.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RateLimitStatistics returns the rate limiting statistics of every backend of the chain
+// that has rate limits
+func (f *FailoverSummarizer) RateLimitStatistics() []BackendStatistics {
+	var stats []BackendStatistics
+	for _, backend := range f.backends {
+		stats = append(stats, RateLimitStatistics(backend)...)
+	}
+	return stats
+}
+
+// Summarize generates a TL;DR summary in English (default language)
+func (f *FailoverSummarizer) Summarize(ctx context.Context, text string, originalTitle string) (*SummaryResponse, error) {
+	return f.SummarizeInLanguage(ctx, text, originalTitle, "en", StyleStandard, nil)
+}
+
+// SummarizeInLanguage generates the summary with the first backend that succeeds
+func (f *FailoverSummarizer) SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string, style SummaryStyle, promptTemplate *PromptTemplate) (*SummaryResponse, error) {
+	var response *SummaryResponse
+	err := f.try(ctx, "summary in "+languageCode, func(backend Backend) error {
+		var err error
+		response, err = backend.SummarizeInLanguage(ctx, text, originalTitle, languageCode, style, promptTemplate)
+		return err
+	})
+	return response, err
+}
+
+// SummarizeInLanguages generates the summaries in several languages with the first backend
+// that succeeds. Backends without multi-language support get one request per language.
+func (f *FailoverSummarizer) SummarizeInLanguages(ctx context.Context, text string, originalTitle string, languageCodes []string, style SummaryStyle, promptTemplate *PromptTemplate) (map[string]*SummaryResponse, error) {
+	var responses map[string]*SummaryResponse
+	err := f.try(ctx, fmt.Sprintf("summary in %v", languageCodes), func(backend Backend) error {
+		var err error
+		if multi, ok := backend.(MultiLanguageSummarizer); ok {
+			responses, err = multi.SummarizeInLanguages(ctx, text, originalTitle, languageCodes, style, promptTemplate)
+			return err
+		}
+		responses, err = summarizeEach(languageCodes, func(languageCode string) (*SummaryResponse, error) {
+			return backend.SummarizeInLanguage(ctx, text, originalTitle, languageCode, style, promptTemplate)
+		})
+		return err
+	})
+	return responses, err
+}
+
+// SummarizePRBatch generates the PR summary with the first backend that succeeds
+func (f *FailoverSummarizer) SummarizePRBatch(ctx context.Context, repoName string, prs []github.PullRequest, languageCode string, promptTemplate *PromptTemplate) (string, error) {
+	var summary string
+	err := f.try(ctx, "PR summary in "+languageCode, func(backend Backend) error {
+		var err error
+		summary, err = backend.SummarizePRBatch(ctx, repoName, prs, languageCode, promptTemplate)
+		return err
+	})
+	return summary, err
+}
+
+// SummarizePRBatchInLanguages generates the PR summaries in several languages with the first
+// backend that succeeds. Backends without multi-language support get one request per language.
+func (f *FailoverSummarizer) SummarizePRBatchInLanguages(ctx context.Context, repoName string, prs []github.PullRequest, languageCodes []string, promptTemplate *PromptTemplate) (map[string]string, error) {
+	var summaries map[string]string
+	err := f.try(ctx, fmt.Sprintf("PR summary in %v", languageCodes), func(backend Backend) error {
+		var err error
+		if multi, ok := backend.(MultiLanguagePRSummarizer); ok {
+			summaries, err = multi.SummarizePRBatchInLanguages(ctx, repoName, prs, languageCodes, promptTemplate)
+			return err
+		}
+		summaries, err = summarizeEach(languageCodes, func(languageCode string) (string, error) {
+			return backend.SummarizePRBatch(ctx, repoName, prs, languageCode, promptTemplate)
+		})
+		return err
+	})
+	return summaries, err
+}
+
+// summarizeEach summarizes every language with its own request. Failed languages are left
+// out; it only fails when no language succeeds.
+func summarizeEach[T any](languageCodes []string, summarize func(languageCode string) (T, error)) (map[string]T, error) {
+	results := make(map[string]T, len(languageCodes))
+	var lastErr error
+	for _, code := range languageCodes {
+		result, err := summarize(code)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		results[code] = result
+	}
+	if len(results) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return results, nil
+}
+
+// try calls the backends in order until one succeeds, skipping backends whose circuit is open
+func (f *FailoverSummarizer) try(ctx context.Context, task string, call func(backend Backend) error) error {
+	var errs []string
+	for i, backend := range f.backends {
+		if !backend.Available() {
+			log.Printf("[AI-FAILOVER] Skipping %s for %s: circuit breaker open", backend.Name(), task)
+			errs = append(errs, backend.Name()+": circuit breaker open")
+			continue
+		}
+
+		err := call(backend)
+		if err == nil {
+			if i > 0 {
+				log.Printf("[AI-FAILOVER] %s generated by fallback backend %s", task, backend.Name())
+			}
+			return nil
+		}
+
+		// Don't move down the chain for work that was cancelled
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		log.Printf("[AI-FAILOVER] %s failed on %s: %v", task, backend.Name(), err)
+		errs = append(errs, fmt.Sprintf("%s: %v", backend.Name(), err))
+	}
+
+	return fmt.Errorf("all AI backends failed for %s: %s", task, strings.Join(errs, "; "))
+}
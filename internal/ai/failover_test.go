@@ -0,0 +1,232 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend answers with its name, or fails with err, and records calls
+type fakeBackend struct {
+	name        string
+	err         error
+	unavailable bool
+	calls       int
+	closed      bool
+}
+
+func (b *fakeBackend) Name() string    { return b.name }
+func (b *fakeBackend) Available() bool { return !b.unavailable }
+func (b *fakeBackend) Close() error    { b.closed = true; return nil }
+
+func (b *fakeBackend) Summarize(ctx context.Context, text string, originalTitle string) (*SummaryResponse, error) {
+	return b.SummarizeInLanguage(ctx, text, originalTitle, "en", StyleStandard, nil)
+}
+
+func (b *fakeBackend) SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string, style SummaryStyle, promptTemplate *PromptTemplate) (*SummaryResponse, error) {
+	b.calls++
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &SummaryResponse{TranslatedTitle: originalTitle, Summary: b.name}, nil
+}
+
+func (b *fakeBackend) SummarizePRBatch(ctx context.Context, repoName string, prs []github.PullRequest, languageCode string, promptTemplate *PromptTemplate) (string, error) {
+	b.calls++
+	if b.err != nil {
+		return "", b.err
+	}
+	return b.name, nil
+}
+
+func TestFailoverSummarizer(t *testing.T) {
+	tests := []struct {
+		name          string
+		backends      []*fakeBackend
+		expectError   bool
+		expectedBy    string
+		expectedCalls []int
+	}{
+		{
+			name: "first backend answers",
+			backends: []*fakeBackend{
+				{name: "flash"},
+				{name: "pro"},
+			},
+			expectedBy:    "flash",
+			expectedCalls: []int{1, 0},
+		},
+		{
+			name: "failed backend moves down the chain",
+			backends: []*fakeBackend{
+				{name: "flash", err: fmt.Errorf("HTTP 503: overloaded")},
+				{name: "pro"},
+				{name: "local"},
+			},
+			expectedBy:    "pro",
+			expectedCalls: []int{1, 1, 0},
+		},
+		{
+			name: "open circuits are skipped without a request",
+			backends: []*fakeBackend{
+				{name: "flash", unavailable: true},
+				{name: "pro", unavailable: true},
+				{name: "local"},
+			},
+			expectedBy:    "local",
+			expectedCalls: []int{0, 0, 1},
+		},
+		{
+			name: "every backend fails",
+			backends: []*fakeBackend{
+				{name: "flash", unavailable: true},
+				{name: "local", err: fmt.Errorf("connection refused")},
+			},
+			expectError:   true,
+			expectedCalls: []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backends := make([]Backend, len(tt.backends))
+			for i, backend := range tt.backends {
+				backends[i] = backend
+			}
+			failover := NewFailoverSummarizer(backends...)
+
+			response, err := failover.SummarizeInLanguage(context.Background(), "text", "Title", "es", StyleStandard, nil)
+			calls := make([]int, len(tt.backends))
+			for i, backend := range tt.backends {
+				calls[i] = backend.calls
+			}
+			assert.Equal(t, tt.expectedCalls, calls)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "circuit breaker open")
+				assert.Contains(t, err.Error(), "connection refused")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBy, response.Summary)
+
+			summary, err := failover.SummarizePRBatch(context.Background(), "godotengine/godot", nil, "es", nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBy, summary)
+		})
+	}
+}
+
+func TestFailoverSummarizer_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	first := &fakeBackend{name: "flash", err: errors.New("request cancelled")}
+	second := &fakeBackend{name: "pro"}
+	cancel()
+
+	_, err := NewFailoverSummarizer(first, second).SummarizeInLanguage(ctx, "text", "Title", "en", StyleStandard, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, second.calls)
+}
+
+func TestFailoverSummarizer_NameAndAvailability(t *testing.T) {
+	flash := &fakeBackend{name: "flash", unavailable: true}
+	local := &fakeBackend{name: "local"}
+	failover := NewFailoverSummarizer(flash, local)
+
+	assert.Equal(t, "flash → local", failover.Name())
+	assert.True(t, failover.Available())
+
+	local.unavailable = true
+	assert.False(t, failover.Available())
+}
+
+func TestFailoverSummarizer_SummarizeInLanguages(t *testing.T) {
+	flash := &fakeBackend{name: "flash", err: fmt.Errorf("HTTP 503: overloaded")}
+	local := &fakeBackend{name: "local"}
+	failover := NewFailoverSummarizer(flash, local)
+
+	// Backends without multi-language support get one request per language
+	responses, err := failover.SummarizeInLanguages(context.Background(), "text", "Title", []string{"es", "ja"}, StyleStandard, nil)
+	require.NoError(t, err)
+	assert.Len(t, responses, 2)
+	assert.Equal(t, "local", responses["ja"].Summary)
+	assert.Equal(t, 2, flash.calls)
+	assert.Equal(t, 2, local.calls)
+
+	// The extractive summarizer answers every language at once
+	failover = NewFailoverSummarizer(flash, NewExtractiveSummarizer())
+	summaries, err := failover.SummarizePRBatchInLanguages(context.Background(), "godotengine/godot", []github.PullRequest{
+		{Number: 101, Title: "Add typed dictionaries", HTMLURL: "https://github.com/godotengine/godot/pull/101"},
+	}, []string{"es", "ja"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, summaries["es"], summaries["ja"])
+	assert.Contains(t, summaries["es"], "PR #101")
+}
+
+func TestFailoverSummarizer_Close(t *testing.T) {
+	flash := &fakeBackend{name: "flash"}
+	local := &fakeBackend{name: "local"}
+
+	require.NoError(t, NewFailoverSummarizer(flash, NewExtractiveSummarizer(), local).Close())
+	assert.True(t, flash.closed)
+	assert.True(t, local.closed)
+}
+
+func TestRateLimitStatistics(t *testing.T) {
+	summarizer := NewSummarizer(&fakeProvider{}, testRateLimitConfig())
+
+	// Backends without rate limits are skipped
+	stats := RateLimitStatistics(NewFailoverSummarizer(summarizer, &fakeBackend{name: "local"}, NewExtractiveSummarizer()))
+	require.Len(t, stats, 1)
+	assert.Equal(t, "fake", stats[0].Name)
+	assert.False(t, stats[0].Statistics.CircuitOpen)
+
+	assert.Empty(t, RateLimitStatistics(NewExtractiveSummarizer()))
+}
+
+func TestSummarizer_FailsFastWhenCircuitOpen(t *testing.T) {
+	config := testRateLimitConfig()
+	config.CircuitBreakerThreshold = 2
+	provider := &fakeProvider{errs: []error{
+		fmt.Errorf("HTTP 500: internal error"),
+		fmt.Errorf("HTTP 500: internal error"),
+		fmt.Errorf("HTTP 500: internal error"),
+	}}
+	summarizer := NewSummarizer(provider, config)
+
+	// The second failure opens the circuit, so the last retry is not sent
+	_, err := summarizer.SummarizeInLanguage(context.Background(), "text", "Title", "en", StyleStandard, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Len(t, provider.prompts, 2)
+	assert.False(t, summarizer.Available())
+
+	// Later requests fail immediately
+	_, err = summarizer.SummarizeInLanguage(context.Background(), "text", "Title", "en", StyleStandard, nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Len(t, provider.prompts, 2)
+}
+
+func TestParseProviderChain(t *testing.T) {
+	configs, err := ParseProviderChain("gemini:gemini-2.5-flash, Gemini:gemini-2.5-pro ,openai:llama3.1:8b,openai,extractive")
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderConfig{
+		{Provider: ProviderGemini, Model: "gemini-2.5-flash"},
+		{Provider: ProviderGemini, Model: "gemini-2.5-pro"},
+		{Provider: ProviderOpenAI, Model: "llama3.1:8b"},
+		{Provider: ProviderOpenAI},
+		{Provider: ProviderExtractive},
+	}, configs)
+
+	_, err = ParseProviderChain("gemini,claude:opus")
+	assert.Error(t, err)
+
+	_, err = ParseProviderChain(" , ")
+	assert.Error(t, err)
+}
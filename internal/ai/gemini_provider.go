@@ -0,0 +1,211 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// DefaultGeminiModel is the Gemini model used when none is configured
+const DefaultGeminiModel = "gemini-2.5-flash" // Using latest flash model (free tier)
+
+// GeminiProvider implements Provider using Google's Gemini API. It owns a single
+// long-lived client, created on first use and shared by concurrent requests.
+type GeminiProvider struct {
+	apiKey string
+
+	mu     sync.Mutex // guards model and client
+	model  string
+	client *genai.Client
+}
+
+// NewGeminiProvider creates a new Gemini provider
+func NewGeminiProvider(apiKey, model string) *GeminiProvider {
+	return &GeminiProvider{
+		apiKey: apiKey,
+		model:  model,
+	}
+}
+
+// Name identifies the provider and model in logs
+func (p *GeminiProvider) Name() string {
+	return "Gemini (" + p.modelName() + ")"
+}
+
+// SetModel allows changing the Gemini model; requests already sent keep their model
+func (p *GeminiProvider) SetModel(model string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.model = model
+}
+
+// modelName returns the Gemini model requests are sent to
+func (p *GeminiProvider) modelName() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.model
+}
+
+// getClient returns the provider's client, creating it on first use
+func (p *GeminiProvider) getClient() (*genai.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		// The client outlives any single request, so it isn't tied to a request's context
+		client, err := genai.NewClient(context.Background(), option.WithAPIKey(p.apiKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		}
+		p.client = client
+	}
+	return p.client, nil
+}
+
+// Close releases the provider's client. A later request creates a new one.
+func (p *GeminiProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return nil
+	}
+	err := p.client.Close()
+	p.client = nil
+	return err
+}
+
+// CountTokens counts the tokens of a prompt with the Gemini API
+func (p *GeminiProvider) CountTokens(ctx context.Context, prompt string) (int, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.GenerativeModel(p.modelName()).CountTokens(ctx, genai.Text(prompt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tokens: %w", err)
+	}
+	return int(resp.TotalTokens), nil
+}
+
+// Generate sends a single request to Gemini and returns the generated text
+func (p *GeminiProvider) Generate(ctx context.Context, prompt string, options GenerateOptions) (*Completion, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	modelName := p.modelName()
+	model := client.GenerativeModel(modelName)
+	model.SetTemperature(options.Temperature)
+	model.SetMaxOutputTokens(int32(options.MaxOutputTokens))
+	model.SetTopP(options.TopP)
+	if options.TopK > 0 {
+		model.SetTopK(int32(options.TopK))
+	}
+	if options.ResponseSchema != nil {
+		model.ResponseMIMEType = "application/json"
+		model.ResponseSchema = geminiSchema(options.ResponseSchema)
+	}
+
+	log.Printf("Sending request to Gemini API (model: %s)", modelName)
+
+	startTime := time.Now()
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	duration := time.Since(startTime)
+
+	if err != nil {
+		log.Printf("Gemini API error after %v: %v", duration, err)
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+
+	log.Printf("Gemini API responded in %v", duration)
+
+	if len(resp.Candidates) == 0 {
+		log.Printf("WARNING: No candidates in response")
+		return nil, fmt.Errorf("no summary generated")
+	}
+
+	candidate := resp.Candidates[0]
+	log.Printf("Response finish reason: %v", candidate.FinishReason)
+
+	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
+		log.Printf("WARNING: Empty content in response")
+		return nil, fmt.Errorf("empty response from Gemini")
+	}
+
+	return &Completion{
+		Text:      strings.TrimSpace(CollectAllParts(candidate)),
+		Truncated: candidate.FinishReason == genai.FinishReasonMaxTokens,
+	}, nil
+}
+
+// ListAvailableModels returns available Gemini models (for debugging)
+func (p *GeminiProvider) ListAvailableModels(ctx context.Context) ([]string, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []string
+	iter := client.ListModels(ctx)
+	for {
+		model, err := iter.Next()
+		if err != nil {
+			break
+		}
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
+
+// geminiSchema converts a structured output schema to Gemini's schema type
+func geminiSchema(schema *Schema) *genai.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	converted := &genai.Schema{
+		Description: schema.Description,
+		Items:       geminiSchema(schema.Items),
+		Required:    schema.Required,
+	}
+	switch schema.Type {
+	case SchemaObject:
+		converted.Type = genai.TypeObject
+	case SchemaArray:
+		converted.Type = genai.TypeArray
+	case SchemaString:
+		converted.Type = genai.TypeString
+	case SchemaBoolean:
+		converted.Type = genai.TypeBoolean
+	}
+	if len(schema.Properties) > 0 {
+		converted.Properties = make(map[string]*genai.Schema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			converted.Properties[name] = geminiSchema(property)
+		}
+	}
+	return converted
+}
+
+// CollectAllParts collects all parts from a Gemini response
+func CollectAllParts(candidate *genai.Candidate) string {
+	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
+		return ""
+	}
+	var result strings.Builder
+	for _, part := range candidate.Content.Parts {
+		result.WriteString(fmt.Sprintf("%v", part))
+	}
+	return result.String()
+}
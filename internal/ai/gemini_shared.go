@@ -1,8 +1,6 @@
 package ai
 
 import (
-	"encoding/json"
-	"fmt"
 	"log"
 	"strings"
 	"time"
@@ -78,44 +76,6 @@ func GetLanguageName(code string) string {
 	return GetLanguageInfo(code).NativeName
 }
 
-// ParseJSONResponse parses the JSON response from Gemini and validates it
-func ParseJSONResponse(rawResponse string, originalTitle string, languageCode string) (*SummaryResponse, error) {
-	// Clean up response - remove markdown code blocks if present
-	cleanedResponse := strings.TrimSpace(rawResponse)
-	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```json")
-	cleanedResponse = strings.TrimPrefix(cleanedResponse, "```")
-	cleanedResponse = strings.TrimSuffix(cleanedResponse, "```")
-	cleanedResponse = strings.TrimSpace(cleanedResponse)
-	
-	log.Printf("Parsing JSON response for %s (length: %d)", languageCode, len(cleanedResponse))
-	
-	var response SummaryResponse
-	if err := json.Unmarshal([]byte(cleanedResponse), &response); err != nil {
-		log.Printf("ERROR: Failed to parse JSON for %s: %v", languageCode, err)
-		log.Printf("Raw response (first 200 chars): %s", TruncateString(cleanedResponse, 200))
-		return nil, fmt.Errorf("JSON parse error: %w", err)
-	}
-	
-	// Validate response
-	if response.TranslatedTitle == "" {
-		log.Printf("WARNING: Empty translated title for %s, using original", languageCode)
-		response.TranslatedTitle = originalTitle
-	}
-	
-	if response.Summary == "" {
-		log.Printf("ERROR: Empty summary for %s", languageCode)
-		return nil, fmt.Errorf("empty summary in response")
-	}
-	
-	// Truncate title if too long (max 256 chars for Discord embed)
-	if len(response.TranslatedTitle) > 256 {
-		log.Printf("WARNING: Title too long for %s (%d chars), truncating", languageCode, len(response.TranslatedTitle))
-		response.TranslatedTitle = response.TranslatedTitle[:253] + "..."
-	}
-	
-	return &response, nil
-}
-
 // TruncateString truncates a string to maxLength characters
 func TruncateString(s string, maxLength int) string {
 	if len(s) <= maxLength {
@@ -124,51 +84,6 @@ func TruncateString(s string, maxLength int) string {
 	return s[:maxLength] + "..."
 }
 
-// ExtractSummaryFromBrokenJSON attempts to extract summary text from malformed JSON
-// This is a fallback when proper JSON parsing fails
-func ExtractSummaryFromBrokenJSON(rawResponse string) string {
-	// Try to extract the summary field value even if JSON is incomplete/malformed
-	// Look for "summary": "..." pattern
-	summaryStart := strings.Index(rawResponse, `"summary"`)
-	if summaryStart == -1 {
-		// No summary field found, return cleaned response
-		return strings.TrimSpace(rawResponse)
-	}
-	
-	// Find the opening quote after "summary":
-	valueStart := strings.Index(rawResponse[summaryStart:], `":`)
-	if valueStart == -1 {
-		return strings.TrimSpace(rawResponse)
-	}
-	valueStart += summaryStart + 2 // Move past ":"
-	
-	// Skip whitespace and opening quote
-	for valueStart < len(rawResponse) && (rawResponse[valueStart] == ' ' || rawResponse[valueStart] == '"') {
-		valueStart++
-	}
-	
-	// Find the closing quote (handle escaped quotes)
-	valueEnd := valueStart
-	for valueEnd < len(rawResponse) {
-		if rawResponse[valueEnd] == '"' && (valueEnd == 0 || rawResponse[valueEnd-1] != '\\') {
-			break
-		}
-		valueEnd++
-	}
-	
-	if valueEnd > valueStart && valueEnd < len(rawResponse) {
-		summary := rawResponse[valueStart:valueEnd]
-		// Unescape common JSON escapes
-		summary = strings.ReplaceAll(summary, `\"`, `"`)
-		summary = strings.ReplaceAll(summary, `\\`, `\`)
-		summary = strings.ReplaceAll(summary, `\n`, "\n")
-		return strings.TrimSpace(summary)
-	}
-	
-	// Fallback: return cleaned response
-	return strings.TrimSpace(rawResponse)
-}
-
 // ShouldRetry determines if an error is retryable
 func ShouldRetry(err error) bool {
 	if err == nil {
@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -24,7 +25,7 @@ func (m *MockAISummarizer) Summarize(ctx context.Context, text string, originalT
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (m *MockAISummarizer) SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string) (*SummaryResponse, error) {
+func (m *MockAISummarizer) SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string, style SummaryStyle, promptTemplate *PromptTemplate) (*SummaryResponse, error) {
 	if m.SummarizeInLanguageFunc != nil {
 		return m.SummarizeInLanguageFunc(ctx, text, originalTitle, languageCode)
 	}
@@ -103,10 +104,15 @@ func TestMockAISummarizer_Summarize(t *testing.T) {
 	}
 }
 
+// newGeminiSummarizer creates a summarizer backed by a Gemini provider using the default model
+func newGeminiSummarizer(apiKey string, config ratelimit.Config) *Summarizer {
+	return NewSummarizer(NewGeminiProvider(apiKey, DefaultGeminiModel), config)
+}
+
 // TestGeminiSummarizer_Summarize_EmptyInput tests validation
 func TestGeminiSummarizer_Summarize_EmptyInput(t *testing.T) {
 	// We don't need a real API key for this test
-	summarizer := NewGeminiSummarizer("fake-api-key")
+	summarizer := newGeminiSummarizer("fake-api-key", ratelimit.DefaultConfig())
 
 	ctx := context.Background()
 	_, err := summarizer.Summarize(ctx, "", "Test Title")
@@ -115,41 +121,62 @@ func TestGeminiSummarizer_Summarize_EmptyInput(t *testing.T) {
 	assert.Contains(t, err.Error(), "empty text")
 }
 
-// TestGeminiSummarizer_SetPrompt tests custom prompt setting
-func TestGeminiSummarizer_SetPrompt(t *testing.T) {
-	summarizer := NewGeminiSummarizer("fake-api-key")
+// TestGeminiSummarizer_SetModel tests model configuration
+func TestGeminiSummarizer_SetModel(t *testing.T) {
+	provider := NewGeminiProvider("fake-api-key", DefaultGeminiModel)
 
-	customPrompt := "Custom prompt for testing"
-	summarizer.SetPrompt(customPrompt)
+	customModel := "gemini-pro"
+	provider.SetModel(customModel)
 
-	assert.Equal(t, customPrompt, summarizer.prompt)
+	assert.Equal(t, customModel, provider.modelName())
 }
 
-// TestGeminiSummarizer_SetModel tests model configuration
-func TestGeminiSummarizer_SetModel(t *testing.T) {
-	summarizer := NewGeminiSummarizer("fake-api-key")
+// TestGeminiSummarizer_SetModelConcurrently tests that the model can change while requests read it
+func TestGeminiSummarizer_SetModelConcurrently(t *testing.T) {
+	provider := NewGeminiProvider("fake-api-key", DefaultGeminiModel)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			provider.SetModel("gemini-pro")
+			_ = provider.Name()
+		}()
+	}
+	wg.Wait()
 
-	customModel := "gemini-pro"
-	summarizer.SetModel(customModel)
+	assert.Equal(t, "gemini-pro", provider.modelName())
+}
+
+// TestGeminiSummarizer_ReusesClient tests that one client is shared until Close
+func TestGeminiSummarizer_ReusesClient(t *testing.T) {
+	provider := NewGeminiProvider("fake-api-key", DefaultGeminiModel)
+
+	first, err := provider.getClient()
+	require.NoError(t, err)
+	second, err := provider.getClient()
+	require.NoError(t, err)
+	assert.Same(t, first, second)
 
-	assert.Equal(t, customModel, summarizer.model)
+	require.NoError(t, provider.Close())
+	assert.Nil(t, provider.client)
+	// Closing twice is harmless
+	require.NoError(t, provider.Close())
 }
 
 // TestGeminiSummarizer_DefaultConfiguration tests default settings
 func TestGeminiSummarizer_DefaultConfiguration(t *testing.T) {
 	apiKey := "test-api-key-123"
-	summarizer := NewGeminiSummarizer(apiKey)
+	provider := NewGeminiProvider(apiKey, DefaultGeminiModel)
 
-	assert.Equal(t, apiKey, summarizer.apiKey)
-	assert.Equal(t, "gemini-2.5-flash", summarizer.model)
-	assert.Contains(t, summarizer.prompt, "resumo")
-	assert.Contains(t, summarizer.prompt, "PT-BR")
-	assert.Contains(t, summarizer.prompt, "desenvolvedores")
+	assert.Equal(t, apiKey, provider.apiKey)
+	assert.Equal(t, "gemini-2.5-flash", provider.modelName())
 }
 
 // TestGeminiSummarizer_ContextCancellation tests context handling
 func TestGeminiSummarizer_ContextCancellation(t *testing.T) {
-	summarizer := NewGeminiSummarizer("fake-api-key")
+	summarizer := newGeminiSummarizer("fake-api-key", ratelimit.DefaultConfig())
 
 	// Create a cancelled context
 	ctx, cancel := context.WithCancel(context.Background())
@@ -169,9 +196,9 @@ func TestAISummarizerInterface_Compliance(t *testing.T) {
 		description string
 	}{
 		{
-			name: "GeminiSummarizer complies",
-			summarizer: NewGeminiSummarizer("test-key"),
-			description: "GeminiSummarizer should implement AISummarizer",
+			name: "Gemini-backed Summarizer complies",
+			summarizer: newGeminiSummarizer("test-key", ratelimit.DefaultConfig()),
+			description: "A Gemini-backed Summarizer should implement AISummarizer",
 		},
 		{
 			name: "MockAISummarizer complies",
@@ -319,7 +346,7 @@ func ExampleMockAISummarizer() {
 
 // TestGeminiSummarizer_RateLimiting tests rate limiting integration
 func TestGeminiSummarizer_RateLimiting(t *testing.T) {
-	summarizer := NewGeminiSummarizer("fake-api-key")
+	summarizer := newGeminiSummarizer("fake-api-key", ratelimit.DefaultConfig())
 	
 	// Get initial statistics
 	stats := summarizer.GetRateLimitStatistics()
@@ -339,14 +366,14 @@ func TestGeminiSummarizer_WithCustomRateLimit(t *testing.T) {
 		RetryBackoffBase:        500 * time.Millisecond,
 	}
 	
-	summarizer := NewGeminiSummarizerWithRateLimit("fake-api-key", config)
+	summarizer := newGeminiSummarizer("fake-api-key", config)
 	assert.NotNil(t, summarizer)
-	assert.Equal(t, "gemini-2.5-flash", summarizer.model)
+	assert.Equal(t, "Gemini (gemini-2.5-flash)", summarizer.Name())
 }
 
 // TestGeminiSummarizer_ResetRateLimits tests rate limit reset
 func TestGeminiSummarizer_ResetRateLimits(t *testing.T) {
-	summarizer := NewGeminiSummarizer("fake-api-key")
+	summarizer := newGeminiSummarizer("fake-api-key", ratelimit.DefaultConfig())
 	
 	// Simulate some usage (via recording directly for testing)
 	summarizer.rateLimiter.RecordRequest(1000)
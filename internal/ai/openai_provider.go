@@ -0,0 +1,161 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultOpenAIBaseURL is the OpenAI API endpoint used when no base URL is configured
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// openAIRequestTimeout bounds a single chat completion request (local models can be slow)
+const openAIRequestTimeout = 5 * time.Minute
+
+// OpenAIProvider implements Provider using an OpenAI-compatible chat completions endpoint.
+// Besides OpenAI this covers self-hosted servers such as Ollama (http://localhost:11434/v1),
+// llama.cpp's llama-server, vLLM or LM Studio.
+type OpenAIProvider struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// NewOpenAIProvider creates a new provider for an OpenAI-compatible endpoint.
+// The API key may be empty for servers without authentication.
+func NewOpenAIProvider(baseURL, apiKey, model string) *OpenAIProvider {
+	return &OpenAIProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		httpClient: &http.Client{Timeout: openAIRequestTimeout},
+	}
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	Temperature    float32               `json:"temperature"`
+	TopP           float32               `json:"top_p,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// openAIResponseFormat requests structured output matching a JSON schema
+type openAIResponseFormat struct {
+	Type       string `json:"type"` // "json_schema"
+	JSONSchema struct {
+		Name   string  `json:"name"`
+		Schema *Schema `json:"schema"`
+	} `json:"json_schema"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Name identifies the provider and model in logs
+func (p *OpenAIProvider) Name() string {
+	return "OpenAI-compatible (" + p.model + " at " + p.baseURL + ")"
+}
+
+// CountTokens estimates the tokens of a prompt; OpenAI-compatible servers have no common counting endpoint
+func (p *OpenAIProvider) CountTokens(ctx context.Context, prompt string) (int, error) {
+	return estimateTokens(prompt), nil
+}
+
+// Generate sends a single chat completion request and returns the generated text
+func (p *OpenAIProvider) Generate(ctx context.Context, prompt string, options GenerateOptions) (*Completion, error) {
+	request := openAIChatRequest{
+		Model:       p.model,
+		Messages:    []openAIMessage{{Role: "user", Content: prompt}},
+		MaxTokens:   options.MaxOutputTokens,
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+	}
+	if options.ResponseSchema != nil {
+		request.ResponseFormat = &openAIResponseFormat{Type: "json_schema"}
+		request.ResponseFormat.JSONSchema.Name = "response"
+		request.ResponseFormat.JSONSchema.Schema = options.ResponseSchema
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	log.Printf("Sending request to %s", p.Name())
+
+	startTime := time.Now()
+	resp, err := p.httpClient.Do(req)
+	duration := time.Since(startTime)
+	if err != nil {
+		log.Printf("OpenAI-compatible API error after %v: %v", duration, err)
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	log.Printf("OpenAI-compatible API responded in %v (status %d)", duration, resp.StatusCode)
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var parsed openAIChatResponse
+	parseErr := json.Unmarshal(data, &parsed)
+
+	if resp.StatusCode != http.StatusOK {
+		// Keep the status code in the message so ShouldRetry can classify the error
+		message := TruncateString(strings.TrimSpace(string(data)), 200)
+		if parseErr == nil && parsed.Error != nil {
+			message = parsed.Error.Message
+		}
+		return nil, fmt.Errorf("API request failed: HTTP %d: %s", resp.StatusCode, message)
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", parseErr)
+	}
+
+	if len(parsed.Choices) == 0 {
+		return nil, fmt.Errorf("no summary generated")
+	}
+
+	choice := parsed.Choices[0]
+	text := strings.TrimSpace(choice.Message.Content)
+	if text == "" {
+		return nil, fmt.Errorf("empty response from %s", p.Name())
+	}
+
+	return &Completion{
+		Text:      text,
+		Truncated: choice.FinishReason == "length",
+	}, nil
+}
@@ -2,14 +2,12 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
-	"time"
 
 	"github.com/GustavoLR548/godot-news-bot/internal/github"
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
 )
 
 // EstimatePRBatchTokens estimates the total tokens needed for a batch of PRs
@@ -85,18 +83,6 @@ func FitPRsWithinTokenLimit(prs []github.PullRequest, languageCode string, maxTo
 	return result
 }
 
-// CollectAllParts collects all parts from a Gemini response
-func CollectAllParts(candidate *genai.Candidate) string {
-	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
-		return ""
-	}
-	var result strings.Builder
-	for _, part := range candidate.Content.Parts {
-		result.WriteString(fmt.Sprintf("%v", part))
-	}
-	return result.String()
-}
-
 // StripPreamble removes common AI preambles from responses
 func StripPreamble(text string) string {
 	// Common preamble patterns to remove (only at the very start)
@@ -148,24 +134,13 @@ func StripPreamble(text string) string {
 
 // PRSummarizer defines the interface for summarizing GitHub PRs
 type PRSummarizer interface {
-	// SummarizePRBatch generates a summary for a batch of PRs
-	SummarizePRBatch(ctx context.Context, repoName string, prs []github.PullRequest, languageCode string) (string, error)
-}
-
-// GeminiPRSummarizer implements PRSummarizer using the existing Gemini client
-type GeminiPRSummarizer struct {
-	summarizer *GeminiSummarizer
-}
-
-// NewGeminiPRSummarizer creates a new Gemini-based PR summarizer
-func NewGeminiPRSummarizer(summarizer *GeminiSummarizer) *GeminiPRSummarizer {
-	return &GeminiPRSummarizer{
-		summarizer: summarizer,
-	}
+	// SummarizePRBatch generates a summary for a batch of PRs. The prompt is rendered from
+	// promptTemplate, or from the built-in template when it is nil.
+	SummarizePRBatch(ctx context.Context, repoName string, prs []github.PullRequest, languageCode string, promptTemplate *PromptTemplate) (string, error)
 }
 
 // SummarizePRBatch generates a categorized summary for a batch of PRs
-func (s *GeminiPRSummarizer) SummarizePRBatch(ctx context.Context, repoName string, prs []github.PullRequest, languageCode string) (string, error) {
+func (s *Summarizer) SummarizePRBatch(ctx context.Context, repoName string, prs []github.PullRequest, languageCode string, promptTemplate *PromptTemplate) (string, error) {
 	if len(prs) == 0 {
 		return "", fmt.Errorf("no PRs to summarize")
 	}
@@ -180,185 +155,122 @@ func (s *GeminiPRSummarizer) SummarizePRBatch(ctx context.Context, repoName stri
 	
 	log.Printf("Generating summary for %d PRs from %s in language %s (estimated: %d tokens)", len(prs), repoName, languageCode, estimatedTokens)
 	
-	// Get language info
-	langInfo := GetLanguageInfo(languageCode)
-	
-	// Build PR list with categorization
-	var prList strings.Builder
-	categorized := make(map[string][]github.PullRequest)
-	
-	for _, pr := range prs {
-		category := github.CategorizePR(pr)
-		categorized[category] = append(categorized[category], pr)
+	// Build prompt
+	prompt, err := templateOrDefault(promptTemplate, PromptPRBatch).Execute(prBatchPromptData(repoName, prs, []string{languageCode}))
+	if err != nil {
+		return "", err
 	}
 	
-	// Format PRs by category
-	for category, categoryPRs := range categorized {
-		prList.WriteString(fmt.Sprintf("\n## %s:\n", category))
-		for _, pr := range categoryPRs {
-			// Get label names
-			labelNames := make([]string, len(pr.Labels))
-			for i, label := range pr.Labels {
-				labelNames[i] = label.Name
-			}
-			
-			prList.WriteString(fmt.Sprintf("- PR #%d: %s\n", pr.Number, pr.Title))
-			prList.WriteString(fmt.Sprintf("  Author: %s\n", pr.Author))
-			prList.WriteString(fmt.Sprintf("  Labels: %s\n", strings.Join(labelNames, ", ")))
-			prList.WriteString(fmt.Sprintf("  URL: %s\n", pr.HTMLURL))
-			if pr.Body != "" {
-				// Truncate body to first 200 chars
-				body := pr.Body
-				if len(body) > 200 {
-					body = body[:200] + "..."
-				}
-				prList.WriteString(fmt.Sprintf("  Description: %s\n", body))
-			}
-			prList.WriteString("\n")
-		}
+	// Generate summary with rate limiting and retries (reuse the estimated tokens from earlier)
+	completion, err := s.generate(ctx, prompt, estimatedTokens, estimatedTokens, GenerateOptions{
+		MaxOutputTokens: 8000, // Increased for large PR batches (was 2000)
+		Temperature:     0.7,
+		TopP:            0.95,
+		TopK:            40,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate PR summary: %w", err)
 	}
-	
-	// Build prompt for Gemini
-	prompt := fmt.Sprintf(`You are a technical news summarizer for the repository "%s". Analyze the following %d merged pull requests and create a concise, developer-focused summary in %s.
 
-%s
+	if completion.Truncated {
+		log.Printf("ERROR: PR summary truncated due to max tokens limit - this batch is too large")
+		return "", fmt.Errorf("response truncated: batch too large for token limit (try reducing batch size)")
+	}
 
-CRITICAL: Start DIRECTLY with the categorized content. Do NOT include any preamble, introduction, or phrases like "Here is a summary" or "Voici un résumé". Begin immediately with the first category header.
+	log.Printf("Collected summary: %d characters (before preamble strip)", len(completion.Text))
 
-REQUIREMENTS:
-1. Group changes by category (Features, Bugfixes, Performance, UI/UX, Security, etc.)
-2. For each significant PR, provide:
-   - A brief "Why it matters" explanation (1 sentence)
-   - The direct link to the PR
-3. Keep it concise and scannable - developers should understand the key changes in under 2 minutes
-4. Maintain a professional, technical tone
-5. Format for Discord embed (use markdown)
+	// Strip common preambles
+	summary := StripPreamble(completion.Text)
+	log.Printf("After preamble strip: %d characters", len(summary))
+	summary = strings.TrimSpace(summary)
 
-OUTPUT FORMAT (example):
-**🚀 Features**
-• **[PR #123](url)**: Added new caching system - Improves performance by 40%% for repeated queries
-• **[PR #456](url)**: Implemented dark mode - Enhances user experience with system theme support
+	if summary == "" {
+		return "", fmt.Errorf("no text content in response")
+	}
+
+	// Sanity check: summary should be reasonable length for the number of PRs
+	if len(summary) < 100 {
+		log.Printf("WARNING: Summary suspiciously short (%d chars) for this batch", len(summary))
+	}
 
-**🐛 Bugfixes**
-• **[PR #789](url)**: Fixed memory leak in worker pool - Prevents crashes during high load
+	log.Printf("PR summary generated successfully (%d chars)", len(summary))
+	return summary, nil
+}
 
-**⚡ Performance**
-• **[PR #234](url)**: Optimized database queries - Reduces API response time by 30%%
+// MultiLanguagePRSummarizer is implemented by PR summarizers that can generate the summary of
+// a batch in several languages with a single request
+type MultiLanguagePRSummarizer interface {
+	// SummarizePRBatchInLanguages returns the summaries by language code. Languages the
+	// response omits are left out, so callers can fall back to SummarizePRBatch.
+	SummarizePRBatchInLanguages(ctx context.Context, repoName string, prs []github.PullRequest, languageCodes []string, promptTemplate *PromptTemplate) (map[string]string, error)
+}
 
-Repository: %s
-Pull Requests:
-%s`,
-		repoName,
-		len(prs),
-		langInfo.Name,
-		langInfo.Instructions,
-		repoName,
-		prList.String(),
-	)
-	
-	// Check rate limits (reuse the estimated tokens from earlier)
-	can, err := s.summarizer.rateLimiter.CanMakeRequest(estimatedTokens)
-	if !can {
-		log.Printf("Rate limit check failed: %v", err)
-		waitErr := s.summarizer.rateLimiter.WaitForCapacity(ctx, estimatedTokens)
-		if waitErr != nil {
-			return "", fmt.Errorf("rate limit exceeded: %w", err)
-		}
+// SummarizePRBatchInLanguages generates the summaries of a batch of PRs in several languages
+// with a single request, reserved once against the rate limits
+func (s *Summarizer) SummarizePRBatchInLanguages(ctx context.Context, repoName string, prs []github.PullRequest, languageCodes []string, promptTemplate *PromptTemplate) (map[string]string, error) {
+	if len(prs) == 0 {
+		return nil, fmt.Errorf("no PRs to summarize")
 	}
-	
-	// Generate summary with retry logic
-	var summary string
-	var lastErr error
-	maxRetries := s.summarizer.rateLimiter.GetConfig().RetryAttempts
-	
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			backoff := CalculateBackoff(s.summarizer.rateLimiter, attempt-1)
-			log.Printf("Retry attempt %d/%d after %v", attempt, maxRetries, backoff)
-			select {
-			case <-time.After(backoff):
-			case <-ctx.Done():
-				return "", ctx.Err()
-			}
-		}
-		
-		// Call Gemini API
-		summary, lastErr = s.callGemini(ctx, prompt)
-		if lastErr == nil {
-			// Success
-			actualTokens := estimatedTokens + (len(summary) / 4)
-			s.summarizer.rateLimiter.RecordRequest(actualTokens)
-			log.Printf("PR summary generated successfully (%d chars)", len(summary))
-			return summary, nil
-		}
-		
-		s.summarizer.rateLimiter.RecordFailure()
-		log.Printf("Attempt %d failed: %v", attempt, lastErr)
+	if len(languageCodes) == 0 {
+		return nil, fmt.Errorf("no languages requested")
 	}
-	
-	return "", fmt.Errorf("failed to generate PR summary after %d attempts: %w", maxRetries+1, lastErr)
-}
 
-// callGemini makes the actual API call to Gemini
-func (s *GeminiPRSummarizer) callGemini(ctx context.Context, prompt string) (string, error) {
-	client, err := genai.NewClient(ctx, option.WithAPIKey(s.summarizer.apiKey))
-	if err != nil {
-		return "", fmt.Errorf("failed to create client: %w", err)
+	// The PRs are sent once; only the output grows with each extra language
+	maxTokens := 30000 // Conservative limit for Gemini 2.5 Flash
+	outputTokensPerLanguage := len(prs)*100 + 200
+	estimatedTokens := EstimatePRBatchTokens(prs, languageCodes[0]) + outputTokensPerLanguage*(len(languageCodes)-1)
+	if estimatedTokens > maxTokens {
+		return nil, fmt.Errorf("batch too large: estimated %d tokens for %d languages exceeds limit of %d", estimatedTokens, len(languageCodes), maxTokens)
 	}
-	defer client.Close()
-	
-	model := client.GenerativeModel(s.summarizer.model)
-	model.SetTemperature(0.7)
-	model.SetMaxOutputTokens(8000) // Increased for large PR batches (was 2000)
-	model.SetTopP(0.95)
-	model.SetTopK(40)
-	
-	log.Printf("Sending PR summary request to Gemini API")
-	startTime := time.Now()
-	
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	duration := time.Since(startTime)
-	
+
+	log.Printf("Generating summary for %d PRs from %s in languages %v (estimated: %d tokens)", len(prs), repoName, languageCodes, estimatedTokens)
+
+	prompt, err := templateOrDefault(promptTemplate, PromptPRBatch).Execute(prBatchPromptData(repoName, prs, languageCodes))
 	if err != nil {
-		log.Printf("Gemini API error after %v: %v", duration, err)
-		return "", fmt.Errorf("API request failed: %w", err)
+		return nil, err
 	}
-	
-	log.Printf("Gemini API responded in %v", duration)
-	
-	if len(resp.Candidates) == 0 {
-		return "", fmt.Errorf("no summary generated")
+	prompt += respondPerLanguage("one summary", languageCodes)
+
+	languageSchema := &Schema{
+		Type:        SchemaString,
+		Description: "The categorized markdown summary of the pull requests in this language",
 	}
-	
-	candidate := resp.Candidates[0]
-	
-	// Check finish reason
-	if candidate.FinishReason == genai.FinishReasonMaxTokens {
-		log.Printf("ERROR: PR summary truncated due to max tokens limit - this batch is too large")
-		return "", fmt.Errorf("response truncated: batch too large for token limit (try reducing batch size)")
+	completion, err := s.generate(ctx, prompt, estimatedTokens, estimatedTokens, GenerateOptions{
+		MaxOutputTokens: 8000 * len(languageCodes),
+		Temperature:     0.7,
+		TopP:            0.95,
+		TopK:            40,
+		ResponseSchema:  languagesSchema(languageCodes, languageSchema),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PR summary: %w", err)
 	}
-	
-	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
-		return "", fmt.Errorf("empty response from Gemini")
+
+	byLanguage, err := decodeLanguages(completion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PR summary: %w", err)
 	}
-	
-	// Collect all parts (wait for complete response)
-	summary := CollectAllParts(candidate)
-	log.Printf("Collected summary: %d characters (before preamble strip)", len(summary))
-	
-	// Strip common preambles
-	summary = StripPreamble(summary)
-	log.Printf("After preamble strip: %d characters", len(summary))
-	summary = strings.TrimSpace(summary)
-	
-	if summary == "" {
-		return "", fmt.Errorf("no text content in response")
+
+	summaries := make(map[string]string, len(languageCodes))
+	for _, code := range languageCodes {
+		var summary string
+		if raw, ok := byLanguage[code]; ok {
+			if err := json.Unmarshal(raw, &summary); err != nil {
+				log.Printf("WARNING: Invalid %s PR summary in response: %v", code, err)
+			}
+		}
+		summary = StripPreamble(summary)
+		if summary == "" {
+			log.Printf("WARNING: PR summary response has no %s summary", code)
+			continue
+		}
+		summaries[code] = summary
 	}
-	
-	// Sanity check: summary should be reasonable length for the number of PRs
-	if len(summary) < 100 {
-		log.Printf("WARNING: Summary suspiciously short (%d chars) for this batch", len(summary))
+
+	if len(summaries) == 0 {
+		return nil, &SummaryValidationError{Reason: "no PR summary for any requested language"}
 	}
-	
-	return summary, nil
+
+	log.Printf("PR summaries generated in %d/%d languages with one request", len(summaries), len(languageCodes))
+	return summaries, nil
 }
@@ -0,0 +1,275 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/github"
+)
+
+// PromptKind identifies what a prompt template is used for
+type PromptKind string
+
+// Prompt kinds; each one is also the name of its template file (e.g. article.tmpl)
+const (
+	PromptArticle PromptKind = "article"
+	PromptPRBatch PromptKind = "pr-batch"
+)
+
+// PromptKinds returns every prompt kind
+func PromptKinds() []PromptKind {
+	return []PromptKind{PromptArticle, PromptPRBatch}
+}
+
+// ParsePromptKind validates a prompt kind name
+func ParsePromptKind(name string) (PromptKind, error) {
+	for _, kind := range PromptKinds() {
+		if string(kind) == name {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("unknown prompt kind %q (expected article or pr-batch)", name)
+}
+
+// maxPromptTemplateLength bounds custom templates (Discord string options are limited to 6000 characters)
+const maxPromptTemplateLength = 6000
+
+//go:embed prompts/*.tmpl
+var defaultPromptFiles embed.FS
+
+// ArticlePromptData is the data available to article prompt templates
+type ArticlePromptData struct {
+	Title        string         // Original article title
+	Content      string         // Article text (scraped page, feed content or description)
+	Language     string         // Target language name, or "each requested language" for several
+	Instructions string         // Style instructions of the target language(s)
+	Languages    []LanguageInfo // Target languages (Code, Name, NativeName, Instructions)
+	Style        SummaryStyle   // Summary style of the channel (tldr, standard, detailed or bullet)
+	// StyleInstructions describes the summary the style asks for, e.g. "A 3-5 sentence technical summary"
+	StyleInstructions string
+}
+
+// PRBatchPromptData is the data available to PR batch prompt templates
+type PRBatchPromptData struct {
+	Repo         string       // Repository as owner/name
+	PRs          []PromptPR   // Merged pull requests of the batch
+	Categories   []PRCategory // The same pull requests grouped by category, sorted by name
+	Language     string       // Target language name, or "each requested language" for several
+	Instructions string       // Style instructions of the target language(s)
+	Languages    []LanguageInfo
+}
+
+// PromptPR is a pull request as seen by prompt templates
+type PromptPR struct {
+	Number   int
+	Title    string
+	Author   string
+	URL      string
+	Body     string
+	Labels   []string
+	Category string
+}
+
+// PRCategory is a group of pull requests of the same category
+type PRCategory struct {
+	Name string
+	PRs  []PromptPR
+}
+
+// PromptTemplate is a text/template prompt. Its version identifies the generated summaries:
+// built-in templates use PromptVersion, custom ones a hash of their text.
+type PromptTemplate struct {
+	Kind    PromptKind
+	Version string
+	Text    string
+	tmpl    *template.Template
+}
+
+var promptFuncs = template.FuncMap{
+	"join":     strings.Join,
+	"truncate": func(s string, n int) string { return TruncateString(s, n) },
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+}
+
+var defaultPromptTemplates = loadDefaultPromptTemplates()
+
+// loadDefaultPromptTemplates parses the built-in templates embedded in the binary
+func loadDefaultPromptTemplates() map[PromptKind]*PromptTemplate {
+	templates := make(map[PromptKind]*PromptTemplate)
+	for _, kind := range PromptKinds() {
+		text, err := defaultPromptFiles.ReadFile("prompts/" + string(kind) + ".tmpl")
+		if err != nil {
+			panic(fmt.Sprintf("missing built-in %s prompt template: %v", kind, err))
+		}
+		t, err := parsePromptTemplate(kind, string(text), PromptVersion)
+		if err != nil {
+			panic(fmt.Sprintf("invalid built-in %s prompt template: %v", kind, err))
+		}
+		templates[kind] = t
+	}
+	return templates
+}
+
+// DefaultPromptTemplate returns the built-in template of a prompt kind
+func DefaultPromptTemplate(kind PromptKind) *PromptTemplate {
+	return defaultPromptTemplates[kind]
+}
+
+// ParsePromptTemplate parses a custom prompt template. It fails when the template doesn't
+// parse or uses variables its kind doesn't provide.
+func ParsePromptTemplate(kind PromptKind, text string) (*PromptTemplate, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("empty %s prompt template", kind)
+	}
+	if len(text) > maxPromptTemplateLength {
+		return nil, fmt.Errorf("%s prompt template too long (%d > %d characters)", kind, len(text), maxPromptTemplateLength)
+	}
+
+	hash := sha256.Sum256([]byte(text))
+	return parsePromptTemplate(kind, text, "custom-"+hex.EncodeToString(hash[:])[:12])
+}
+
+func parsePromptTemplate(kind PromptKind, text, version string) (*PromptTemplate, error) {
+	tmpl, err := template.New(string(kind)).Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s prompt template: %w", kind, err)
+	}
+	t := &PromptTemplate{Kind: kind, Version: version, Text: text, tmpl: tmpl}
+
+	// Render sample data so unknown variables are reported now instead of when summarizing
+	if _, err := t.Execute(samplePromptData(kind)); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Execute renders the template; data must be ArticlePromptData or PRBatchPromptData
+func (t *PromptTemplate) Execute(data any) (string, error) {
+	var prompt strings.Builder
+	if err := t.tmpl.Execute(&prompt, data); err != nil {
+		return "", fmt.Errorf("failed to render %s prompt template: %w", t.Kind, err)
+	}
+	return strings.TrimSpace(prompt.String()), nil
+}
+
+// LoadPromptTemplates reads custom templates named after their kind (article.tmpl,
+// pr-batch.tmpl) from a directory; kinds without a file are left out
+func LoadPromptTemplates(dir string) (map[PromptKind]*PromptTemplate, error) {
+	templates := make(map[PromptKind]*PromptTemplate)
+	for _, kind := range PromptKinds() {
+		text, err := os.ReadFile(filepath.Join(dir, string(kind)+".tmpl"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s prompt template: %w", kind, err)
+		}
+		t, err := ParsePromptTemplate(kind, string(text))
+		if err != nil {
+			return nil, err
+		}
+		templates[kind] = t
+	}
+	return templates, nil
+}
+
+// templateOrDefault returns the template, or the built-in one of the kind when it is nil
+func templateOrDefault(t *PromptTemplate, kind PromptKind) *PromptTemplate {
+	if t != nil {
+		return t
+	}
+	return DefaultPromptTemplate(kind)
+}
+
+// articlePromptData builds the article prompt data for the target language(s) and summary style
+func articlePromptData(languageCodes []string, style SummaryStyle, originalTitle, text string) ArticlePromptData {
+	language, instructions, languages := describeLanguages(languageCodes)
+	return ArticlePromptData{
+		Title:             originalTitle,
+		Content:           text,
+		Language:          language,
+		Instructions:      instructions,
+		Languages:         languages,
+		Style:             style,
+		StyleInstructions: style.Instructions(),
+	}
+}
+
+// prBatchPromptData builds the PR batch prompt data for the target language(s)
+func prBatchPromptData(repoName string, prs []github.PullRequest, languageCodes []string) PRBatchPromptData {
+	language, instructions, languages := describeLanguages(languageCodes)
+	data := PRBatchPromptData{
+		Repo:         repoName,
+		PRs:          make([]PromptPR, len(prs)),
+		Language:     language,
+		Instructions: instructions,
+		Languages:    languages,
+	}
+
+	byCategory := make(map[string][]PromptPR)
+	for i, pr := range prs {
+		labels := make([]string, len(pr.Labels))
+		for j, label := range pr.Labels {
+			labels[j] = label.Name
+		}
+		data.PRs[i] = PromptPR{
+			Number:   pr.Number,
+			Title:    pr.Title,
+			Author:   pr.Author,
+			URL:      pr.HTMLURL,
+			Body:     pr.Body,
+			Labels:   labels,
+			Category: github.CategorizePR(pr),
+		}
+		byCategory[data.PRs[i].Category] = append(byCategory[data.PRs[i].Category], data.PRs[i])
+	}
+
+	for name, categoryPRs := range byCategory {
+		data.Categories = append(data.Categories, PRCategory{Name: name, PRs: categoryPRs})
+	}
+	sort.Slice(data.Categories, func(i, j int) bool {
+		return data.Categories[i].Name < data.Categories[j].Name
+	})
+	return data
+}
+
+// describeLanguages names the target language(s) of a prompt with their style instructions.
+// Several languages are listed with their codes, which key the structured response.
+func describeLanguages(languageCodes []string) (language, instructions string, languages []LanguageInfo) {
+	languages = make([]LanguageInfo, len(languageCodes))
+	for i, code := range languageCodes {
+		languages[i] = GetLanguageInfo(code)
+	}
+	if len(languages) == 1 {
+		return languages[0].Name, languages[0].Instructions, languages
+	}
+
+	lines := make([]string, len(languages))
+	for i, info := range languages {
+		lines[i] = fmt.Sprintf("- %s (%s): %s", languageCodes[i], info.Name, info.Instructions)
+	}
+	return "each requested language", "Requested languages:\n" + strings.Join(lines, "\n"), languages
+}
+
+// samplePromptData returns placeholder data used to validate templates of a kind
+func samplePromptData(kind PromptKind) any {
+	if kind == PromptPRBatch {
+		return prBatchPromptData("owner/repo", []github.PullRequest{{
+			Number:  1,
+			Title:   "Sample pull request",
+			Author:  "author",
+			HTMLURL: "https://github.com/owner/repo/pull/1",
+			Body:    "Description",
+			Labels:  []github.Label{{Name: "enhancement"}},
+		}}, []string{"en"})
+	}
+	return articlePromptData([]string{"en"}, StyleStandard, "Sample title", "Sample content")
+}
@@ -0,0 +1,144 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPromptTemplates(t *testing.T) {
+	article, err := DefaultPromptTemplate(PromptArticle).Execute(articlePromptData([]string{"es"}, StyleStandard, "Godot 4.4 released", "Article text"))
+	require.NoError(t, err)
+	assert.Contains(t, article, "A translated title in Español")
+	assert.Contains(t, article, "2. A 3-5 sentence technical summary in Español highlighting")
+	assert.Contains(t, article, "Usa lenguaje técnico pero accesible.")
+	assert.Contains(t, article, "Original Title: Godot 4.4 released\n\nArticle Content:\nArticle text")
+
+	prs := []github.PullRequest{
+		{Number: 102, Title: "Fix crash on exit", Author: "dev", HTMLURL: "https://github.com/godotengine/godot/pull/102",
+			Labels: []github.Label{{Name: "bug"}, {Name: "crash"}}},
+		{Number: 101, Title: "Add typed dictionaries", Author: "dev", HTMLURL: "https://github.com/godotengine/godot/pull/101",
+			Body: strings.Repeat("a", 300)},
+	}
+	batch, err := DefaultPromptTemplate(PromptPRBatch).Execute(prBatchPromptData("godotengine/godot", prs, []string{"en"}))
+	require.NoError(t, err)
+	assert.Contains(t, batch, `the repository "godotengine/godot". Analyze the following 2 merged pull requests`)
+	assert.Contains(t, batch, "by 40% for repeated queries")
+	assert.Contains(t, batch, "- PR #102: Fix crash on exit\n  Author: dev\n  Labels: bug, crash\n")
+	assert.Contains(t, batch, "  Description: "+strings.Repeat("a", 200)+"...")
+
+	for _, kind := range PromptKinds() {
+		assert.Equal(t, PromptVersion, DefaultPromptTemplate(kind).Version)
+	}
+}
+
+func TestArticlePrompt_SummaryStyles(t *testing.T) {
+	for _, style := range SummaryStyles() {
+		prompt, err := DefaultPromptTemplate(PromptArticle).Execute(articlePromptData([]string{"en"}, style, "Title", "Text"))
+		require.NoError(t, err)
+		assert.Contains(t, prompt, "2. "+style.Instructions()+" in English")
+	}
+
+	style, err := ParseSummaryStyle("")
+	require.NoError(t, err)
+	assert.Equal(t, StyleStandard, style)
+	style, err = ParseSummaryStyle("bullet")
+	require.NoError(t, err)
+	assert.Equal(t, StyleBullet, style)
+	_, err = ParseSummaryStyle("haiku")
+	assert.Error(t, err)
+
+	assert.Equal(t, StyleStandard, SummaryStyle("").orStandard())
+	assert.Equal(t, StyleTLDR, StyleTLDR.orStandard())
+}
+
+func TestParsePromptTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        PromptKind
+		text        string
+		expectError bool
+	}{
+		{name: "article variables", kind: PromptArticle, text: "Summarize {{.Title}} in {{.Language}}:\n{{.Content}}"},
+		{name: "language list", kind: PromptArticle, text: "{{range .Languages}}{{.Code}} {{end}}{{.Content}}"},
+		{name: "summary style", kind: PromptArticle, text: "{{if eq .Style \"tldr\"}}One line{{else}}{{.StyleInstructions}}{{end}}: {{.Content}}"},
+		{name: "PR variables", kind: PromptPRBatch, text: "{{.Repo}}{{range .PRs}} #{{.Number}} {{upper .Category}}{{end}}"},
+		{name: "syntax error", kind: PromptArticle, text: "{{.Title", expectError: true},
+		{name: "unknown variable", kind: PromptArticle, text: "{{.Repo}}", expectError: true},
+		{name: "variable of another kind", kind: PromptPRBatch, text: "{{.Content}}", expectError: true},
+		{name: "empty", kind: PromptArticle, text: "  ", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := ParsePromptTemplate(tt.kind, tt.text)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, template.Kind)
+			assert.Regexp(t, `^custom-[0-9a-f]{12}$`, template.Version)
+		})
+	}
+
+	// The version follows the text, so editing a template regenerates its summaries
+	a, _ := ParsePromptTemplate(PromptArticle, "{{.Content}}")
+	b, _ := ParsePromptTemplate(PromptArticle, "{{.Content}}")
+	c, _ := ParsePromptTemplate(PromptArticle, "TL;DR: {{.Content}}")
+	assert.Equal(t, a.Version, b.Version)
+	assert.NotEqual(t, a.Version, c.Version)
+}
+
+func TestLoadPromptTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "article.tmpl"), []byte("TL;DR {{.Title}}: {{.Content}}"), 0o644))
+
+	templates, err := LoadPromptTemplates(dir)
+	require.NoError(t, err)
+	assert.Len(t, templates, 1)
+	assert.Equal(t, PromptArticle, templates[PromptArticle].Kind)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pr-batch.tmpl"), []byte("{{.Title}}"), 0o644))
+	_, err = LoadPromptTemplates(dir)
+	assert.Error(t, err)
+}
+
+func TestSummarizer_UsesPromptTemplate(t *testing.T) {
+	provider := &fakeProvider{completions: []*Completion{
+		{Text: `{"translated_title": "Título", "summary": "Resumen."}`},
+		{Text: `{"es": {"summary": "Resumen."}, "en": {"summary": "Summary."}}`},
+		{Text: "**🚀 Features**"},
+	}}
+	config := testRateLimitConfig()
+	config.MaxTokensPerRequest = 10000
+	summarizer := NewSummarizer(provider, config)
+
+	article, err := ParsePromptTemplate(PromptArticle, "Summarize for beginners in {{.Language}}: {{.Content}}")
+	require.NoError(t, err)
+	pr, err := ParsePromptTemplate(PromptPRBatch, "Changelog of {{.Repo}} in {{.Language}}")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, err := summarizer.SummarizeInLanguage(ctx, "Article text", "Title", "es", StyleStandard, article)
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize for beginners in Español: Article text", provider.prompts[0])
+	assert.Equal(t, article.Version, response.PromptVersion)
+
+	responses, err := summarizer.SummarizeInLanguages(ctx, "Article text", "Title", []string{"es", "en"}, StyleStandard, article)
+	require.NoError(t, err)
+	assert.Contains(t, provider.prompts[1], "Summarize for beginners in each requested language")
+	assert.Contains(t, provider.prompts[1], "keyed by its language code (es, en)")
+	assert.Equal(t, article.Version, responses["en"].PromptVersion)
+
+	prs := []github.PullRequest{{Number: 1, Title: "Fix"}}
+	_, err = summarizer.SummarizePRBatch(ctx, "godotengine/godot", prs, "en", pr)
+	require.NoError(t, err)
+	assert.Equal(t, "Changelog of godotengine/godot in English", provider.prompts[2])
+}
@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ratelimit"
+)
+
+// Supported AI providers (AI_PROVIDER)
+const (
+	ProviderGemini = "gemini"
+	ProviderOpenAI = "openai"
+	// ProviderExtractive is the offline ExtractiveSummarizer; it is a Backend, not a Provider
+	ProviderExtractive = "extractive"
+)
+
+// Provider is an AI backend that generates text from a prompt. Summarizer builds
+// the prompts and handles rate limiting and retries on top of it.
+type Provider interface {
+	// Name identifies the provider and model in logs
+	Name() string
+	// CountTokens returns the number of tokens of a prompt (an estimate when the backend can't count them)
+	CountTokens(ctx context.Context, prompt string) (int, error)
+	// Generate sends a single request and returns the generated text
+	Generate(ctx context.Context, prompt string, options GenerateOptions) (*Completion, error)
+}
+
+// GenerateOptions configures a generation request
+type GenerateOptions struct {
+	MaxOutputTokens int
+	Temperature     float32
+	TopP            float32
+	TopK            int     // ignored by backends without top-k sampling
+	ResponseSchema  *Schema // requests a JSON response matching the schema (nil = free text)
+}
+
+// Completion is the text generated for a prompt
+type Completion struct {
+	Text      string
+	Truncated bool // generation stopped at MaxOutputTokens
+}
+
+// ProviderConfig selects and configures the AI provider
+type ProviderConfig struct {
+	Provider string // ProviderGemini (default) or ProviderOpenAI
+	APIKey   string // optional for OpenAI-compatible servers without authentication
+	Model    string // defaults to DefaultGeminiModel for Gemini; required for OpenAI
+	BaseURL  string // OpenAI-compatible endpoint, defaults to DefaultOpenAIBaseURL
+}
+
+// NewProvider creates the provider selected by the configuration
+func NewProvider(config ProviderConfig) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(config.Provider)) {
+	case "", ProviderGemini:
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("an API key is required for the %s provider", ProviderGemini)
+		}
+		model := config.Model
+		if model == "" {
+			model = DefaultGeminiModel
+		}
+		return NewGeminiProvider(config.APIKey, model), nil
+	case ProviderOpenAI:
+		if config.Model == "" {
+			return nil, fmt.Errorf("a model is required for the %s provider", ProviderOpenAI)
+		}
+		baseURL := config.BaseURL
+		if baseURL == "" {
+			baseURL = DefaultOpenAIBaseURL
+		}
+		return NewOpenAIProvider(baseURL, config.APIKey, config.Model), nil
+	case ProviderExtractive:
+		return nil, fmt.Errorf("the %s summarizer is not a generative provider, use NewBackend", ProviderExtractive)
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q (supported: %s, %s)", config.Provider, ProviderGemini, ProviderOpenAI)
+	}
+}
+
+// NewBackend creates a summarization backend for a chain entry: the offline extractive
+// summarizer, or a Summarizer with its own rate limiter on top of the configured provider
+func NewBackend(config ProviderConfig, rateLimitConfig ratelimit.Config) (Backend, error) {
+	if strings.ToLower(strings.TrimSpace(config.Provider)) == ProviderExtractive {
+		return NewExtractiveSummarizer(), nil
+	}
+
+	provider, err := NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewSummarizer(provider, rateLimitConfig), nil
+}
+
+// ParseProviderChain parses an ordered, comma-separated list of "provider[:model]"
+// entries (e.g. "gemini:gemini-2.5-flash,gemini:gemini-2.5-pro,openai:llama3.1").
+// Credentials and endpoints are left for the caller to fill in.
+func ParseProviderChain(spec string) ([]ProviderConfig, error) {
+	var configs []ProviderConfig
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, model, _ := strings.Cut(entry, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != ProviderGemini && name != ProviderOpenAI && name != ProviderExtractive {
+			return nil, fmt.Errorf("unknown AI provider %q in chain entry %q", name, entry)
+		}
+		configs = append(configs, ProviderConfig{Provider: name, Model: strings.TrimSpace(model)})
+	}
+
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("empty AI provider chain")
+	}
+	return configs, nil
+}
+
+// estimateTokens roughly estimates the tokens of a text (1 token ≈ 4 characters)
+func estimateTokens(text string) int {
+	return len(text) / 4
+}
@@ -0,0 +1,348 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/github"
+	"github.com/GustavoLR548/godot-news-bot/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider returns canned completions (or errors) in order and records the prompts and options
+type fakeProvider struct {
+	completions []*Completion
+	errs        []error
+	prompts     []string
+	options     []GenerateOptions
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) CountTokens(ctx context.Context, prompt string) (int, error) {
+	return estimateTokens(prompt), nil
+}
+
+func (p *fakeProvider) Generate(ctx context.Context, prompt string, options GenerateOptions) (*Completion, error) {
+	call := len(p.prompts)
+	p.prompts = append(p.prompts, prompt)
+	p.options = append(p.options, options)
+	if call < len(p.errs) && p.errs[call] != nil {
+		return nil, p.errs[call]
+	}
+	return p.completions[call], nil
+}
+
+func testRateLimitConfig() ratelimit.Config {
+	config := ratelimit.DefaultConfig()
+	config.RetryAttempts = 2
+	config.RetryBackoffBase = time.Millisecond
+	return config
+}
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       ProviderConfig
+		expectError  bool
+		expectedName string
+	}{
+		{
+			name:         "Gemini by default",
+			config:       ProviderConfig{APIKey: "key"},
+			expectedName: "Gemini (gemini-2.5-flash)",
+		},
+		{
+			name:         "Gemini with a custom model",
+			config:       ProviderConfig{Provider: "gemini", APIKey: "key", Model: "gemini-2.5-pro"},
+			expectedName: "Gemini (gemini-2.5-pro)",
+		},
+		{
+			name:        "Gemini requires an API key",
+			config:      ProviderConfig{Provider: "gemini"},
+			expectError: true,
+		},
+		{
+			name:         "OpenAI-compatible local server without API key",
+			config:       ProviderConfig{Provider: "openai", Model: "llama3.1", BaseURL: "http://localhost:11434/v1/"},
+			expectedName: "OpenAI-compatible (llama3.1 at http://localhost:11434/v1)",
+		},
+		{
+			name:         "OpenAI defaults to the OpenAI API",
+			config:       ProviderConfig{Provider: "OpenAI", APIKey: "key", Model: "gpt-4o-mini"},
+			expectedName: "OpenAI-compatible (gpt-4o-mini at https://api.openai.com/v1)",
+		},
+		{
+			name:        "OpenAI requires a model",
+			config:      ProviderConfig{Provider: "openai"},
+			expectError: true,
+		},
+		{
+			name:        "unknown provider",
+			config:      ProviderConfig{Provider: "claude", APIKey: "key"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewProvider(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, provider.Name())
+		})
+	}
+}
+
+func TestOpenAIProvider_Generate(t *testing.T) {
+	var received openAIChatRequest
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"  A summary.  "},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(server.URL+"/v1", "secret", "llama3.1")
+	completion, err := provider.Generate(context.Background(), "Summarize this", GenerateOptions{
+		MaxOutputTokens: 1500,
+		Temperature:     0.7,
+		TopP:            0.95,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "A summary.", completion.Text)
+	assert.False(t, completion.Truncated)
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, "llama3.1", received.Model)
+	assert.Equal(t, 1500, received.MaxTokens)
+	require.Len(t, received.Messages, 1)
+	assert.Equal(t, "user", received.Messages[0].Role)
+	assert.Equal(t, "Summarize this", received.Messages[0].Content)
+	assert.Nil(t, received.ResponseFormat)
+
+	// Structured output requests a JSON schema response format
+	_, err = provider.Generate(context.Background(), "Summarize this", GenerateOptions{ResponseSchema: summaryResponseSchema})
+	require.NoError(t, err)
+	require.NotNil(t, received.ResponseFormat)
+	assert.Equal(t, "json_schema", received.ResponseFormat.Type)
+	assert.Equal(t, summaryResponseSchema.Required, received.ResponseFormat.JSONSchema.Schema.Required)
+}
+
+func TestOpenAIProvider_Generate_Responses(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		body            string
+		apiKey          string
+		expectError     string
+		expectTruncated bool
+	}{
+		{
+			name:            "truncated by the token limit",
+			status:          http.StatusOK,
+			body:            `{"choices":[{"message":{"content":"Partial"},"finish_reason":"length"}]}`,
+			expectTruncated: true,
+		},
+		{
+			name:        "rate limited",
+			status:      http.StatusTooManyRequests,
+			body:        `{"error":{"message":"Rate limit reached"}}`,
+			expectError: "HTTP 429: Rate limit reached",
+		},
+		{
+			name:        "server error without JSON body",
+			status:      http.StatusBadGateway,
+			body:        "bad gateway",
+			expectError: "HTTP 502: bad gateway",
+		},
+		{
+			name:        "no choices",
+			status:      http.StatusOK,
+			body:        `{"choices":[]}`,
+			expectError: "no summary generated",
+		},
+		{
+			name:        "empty content",
+			status:      http.StatusOK,
+			body:        `{"choices":[{"message":{"content":" "},"finish_reason":"stop"}]}`,
+			expectError: "empty response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Empty(t, r.Header.Get("Authorization"))
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			completion, err := NewOpenAIProvider(server.URL, "", "llama3.1").Generate(context.Background(), "prompt", GenerateOptions{})
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectTruncated, completion.Truncated)
+		})
+	}
+}
+
+func TestSummarizer_SummarizeInLanguage(t *testing.T) {
+	provider := &fakeProvider{
+		errs: []error{fmt.Errorf("HTTP 503: overloaded")},
+		completions: []*Completion{
+			nil,
+			{Text: `{"translated_title": "Godot 4.4 lançado", "summary": "Resumo do artigo."}`},
+		},
+	}
+	summarizer := NewSummarizer(provider, testRateLimitConfig())
+
+	response, err := summarizer.SummarizeInLanguage(context.Background(), "Article text", "Godot 4.4 released", "pt-BR", StyleTLDR, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Godot 4.4 lançado", response.TranslatedTitle)
+	assert.Equal(t, "Resumo do artigo.", response.Summary)
+	// The retryable failure was retried with the same prompt
+	require.Len(t, provider.prompts, 2)
+	assert.Contains(t, provider.prompts[0], "Godot 4.4 released")
+	assert.Contains(t, provider.prompts[0], StyleTLDR.Instructions())
+	assert.Equal(t, provider.prompts[0], provider.prompts[1])
+	// Structured output is requested
+	assert.Equal(t, summaryResponseSchema, provider.options[0].ResponseSchema)
+}
+
+func TestSummarizer_SummarizeInLanguages(t *testing.T) {
+	provider := &fakeProvider{completions: []*Completion{{Text: `{
+		"pt-BR": {"translated_title": "Godot 4.4 lançado", "summary": "Resumo."},
+		"es": {"translated_title": "Godot 4.4 publicado", "summary": ""},
+		"en": {"translated_title": "Godot 4.4 released", "summary": "Summary."}
+	}`}}}
+	config := testRateLimitConfig()
+	config.MaxTokensPerRequest = 10000
+	summarizer := NewSummarizer(provider, config)
+
+	responses, err := summarizer.SummarizeInLanguages(context.Background(), "Article text", "Godot 4.4 released", []string{"pt-BR", "es", "en", "ja"}, StyleStandard, nil)
+	require.NoError(t, err)
+
+	// One request for every language, counted once against the rate limits
+	require.Len(t, provider.prompts, 1)
+	assert.Equal(t, int64(1), summarizer.GetRateLimitStatistics().TotalRequests)
+	assert.Contains(t, provider.prompts[0], "ja (日本語 (Japanese))")
+	schema := provider.options[0].ResponseSchema
+	assert.Equal(t, []string{"pt-BR", "es", "en", "ja"}, schema.Required)
+	assert.Equal(t, summaryResponseSchema, schema.Properties["ja"])
+
+	// The invalid and the omitted languages are left out for per-language fallbacks
+	assert.Len(t, responses, 2)
+	assert.Equal(t, "Godot 4.4 lançado", responses["pt-BR"].TranslatedTitle)
+	assert.Equal(t, "Summary.", responses["en"].Summary)
+
+	// A response without any valid language fails
+	provider = &fakeProvider{completions: []*Completion{{Text: `{"fr": {"summary": "Résumé."}}`}}}
+	_, err = NewSummarizer(provider, testRateLimitConfig()).SummarizeInLanguages(context.Background(), "Article text", "Title", []string{"es"}, StyleStandard, nil)
+	var validationErr *SummaryValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestSummarizer_SummarizeInLanguages_SplitsOverTokenLimit(t *testing.T) {
+	provider := &fakeProvider{completions: []*Completion{
+		{Text: `{"pt-BR": {"summary": "Resumo."}, "es": {"summary": "Resumen."}}`},
+		{Text: `{"en": {"summary": "Summary."}, "ja": {"summary": "要約。"}}`},
+	}}
+	config := testRateLimitConfig()
+	config.MaxTokensPerRequest = 4000 // room for two languages per request
+	summarizer := NewSummarizer(provider, config)
+
+	responses, err := summarizer.SummarizeInLanguages(context.Background(), "Article text", "Title", []string{"pt-BR", "es", "en", "ja"}, StyleStandard, nil)
+	require.NoError(t, err)
+	assert.Len(t, responses, 4)
+	require.Len(t, provider.options, 2)
+	assert.Equal(t, []string{"pt-BR", "es"}, provider.options[0].ResponseSchema.Required)
+	assert.Equal(t, []string{"en", "ja"}, provider.options[1].ResponseSchema.Required)
+
+	// Without room for two languages, callers fall back to one request per language
+	_, err = summarizer.SummarizeInLanguages(context.Background(), strings.Repeat("Long article. ", 1000), "Title", []string{"es", "en"}, StyleStandard, nil)
+	assert.Error(t, err)
+	assert.Len(t, provider.prompts, 2)
+}
+
+func TestSummarizer_SummarizeInLanguage_InvalidResponse(t *testing.T) {
+	provider := &fakeProvider{completions: []*Completion{{Text: `{"translated_title": "Title"}`}}}
+	summarizer := NewSummarizer(provider, testRateLimitConfig())
+
+	_, err := summarizer.SummarizeInLanguage(context.Background(), "Article text", "Title", "en", StyleStandard, nil)
+	var validationErr *SummaryValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "summary", validationErr.Field)
+}
+
+func TestSummarizer_SummarizeInLanguage_NonRetryableError(t *testing.T) {
+	provider := &fakeProvider{errs: []error{fmt.Errorf("HTTP 401: invalid api key")}}
+	summarizer := NewSummarizer(provider, testRateLimitConfig())
+
+	_, err := summarizer.SummarizeInLanguage(context.Background(), "Article text", "Title", "en", StyleStandard, nil)
+	require.Error(t, err)
+	assert.Len(t, provider.prompts, 1)
+}
+
+func TestSummarizer_SummarizePRBatch(t *testing.T) {
+	prs := []github.PullRequest{
+		{Number: 101, Title: "Add typed dictionaries", Author: "dev", HTMLURL: "https://github.com/godotengine/godot/pull/101"},
+	}
+	summary := "**🚀 Features**\n• **[PR #101](https://github.com/godotengine/godot/pull/101)**: Added typed dictionaries - Safer scripting"
+
+	provider := &fakeProvider{completions: []*Completion{{Text: "Here is a summary of the changes:\n" + summary}}}
+	summarizer := NewSummarizer(provider, testRateLimitConfig())
+
+	result, err := summarizer.SummarizePRBatch(context.Background(), "godotengine/godot", prs, "en", nil)
+	require.NoError(t, err)
+	assert.Equal(t, summary, result)
+	assert.Contains(t, provider.prompts[0], "PR #101: Add typed dictionaries")
+
+	// A truncated summary is rejected
+	provider = &fakeProvider{completions: []*Completion{{Text: summary, Truncated: true}}}
+	summarizer = NewSummarizer(provider, testRateLimitConfig())
+
+	_, err = summarizer.SummarizePRBatch(context.Background(), "godotengine/godot", prs, "en", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "truncated")
+}
+
+func TestSummarizer_SummarizePRBatchInLanguages(t *testing.T) {
+	prs := []github.PullRequest{
+		{Number: 101, Title: "Add typed dictionaries", Author: "dev", HTMLURL: "https://github.com/godotengine/godot/pull/101"},
+	}
+	provider := &fakeProvider{completions: []*Completion{{Text: `{
+		"en": "**🚀 Features**\n• **[PR #101](https://github.com/godotengine/godot/pull/101)**: Added typed dictionaries",
+		"es": "Here is a summary of the changes:\n**🚀 Funcionalidades**\n• **[PR #101](https://github.com/godotengine/godot/pull/101)**: Diccionarios tipados",
+		"de": ""
+	}`}}}
+	summarizer := NewSummarizer(provider, testRateLimitConfig())
+
+	summaries, err := summarizer.SummarizePRBatchInLanguages(context.Background(), "godotengine/godot", prs, []string{"en", "es", "de"}, nil)
+	require.NoError(t, err)
+	require.Len(t, provider.prompts, 1)
+	assert.Contains(t, provider.prompts[0], "PR #101: Add typed dictionaries")
+	assert.Equal(t, []string{"en", "es", "de"}, provider.options[0].ResponseSchema.Required)
+
+	assert.Len(t, summaries, 2)
+	assert.True(t, strings.HasPrefix(summaries["es"], "**🚀 Funcionalidades**"))
+	assert.NotContains(t, summaries, "de")
+}
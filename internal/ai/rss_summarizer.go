@@ -2,20 +2,32 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"strings"
 	"time"
 
 	"github.com/GustavoLR548/godot-news-bot/internal/ratelimit"
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
 )
 
-// SummaryResponse contains both translated title and summary
+// PromptVersion identifies the built-in prompt templates. Bump it whenever they change
+// so cached summaries are generated again.
+const PromptVersion = "3"
+
+// summaryOutputTokens caps the output of an article summary in one language
+const summaryOutputTokens = 1500
+
+// SummaryResponse contains both translated title and summary, plus the structured details
+// AI backends extract from the article (left empty by the extractive summarizer)
 type SummaryResponse struct {
-	TranslatedTitle string `json:"translated_title"`
-	Summary         string `json:"summary"`
+	TranslatedTitle string   `json:"translated_title"`
+	Summary         string   `json:"summary"`
+	KeyPoints       []string `json:"key_points,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	BreakingChange  bool     `json:"breaking_change,omitempty"`
+	PromptVersion   string   `json:"prompt_version,omitempty"` // Version of the prompt template the summary was generated with
 }
 
 // AISummarizer defines the interface for AI-based text summarization
@@ -23,153 +35,216 @@ type AISummarizer interface {
 	// Summarize generates a TL;DR summary in Brazilian Portuguese
 	Summarize(ctx context.Context, text string, originalTitle string) (*SummaryResponse, error)
 	// SummarizeInLanguage generates a TL;DR summary with translated title in the specified language
-	SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string) (*SummaryResponse, error)
+	// and summary style (the standard style when empty). The prompt is rendered from
+	// promptTemplate, or from the built-in template when it is nil.
+	SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string, style SummaryStyle, promptTemplate *PromptTemplate) (*SummaryResponse, error)
 }
 
-// GeminiSummarizer implements AISummarizer using Google's Gemini API for RSS feeds
-type GeminiSummarizer struct {
-	apiKey      string
-	model       string
-	prompt      string
-	rateLimiter *ratelimit.Manager
+// MultiLanguageSummarizer is implemented by summarizers that can generate the summaries of
+// an article in several languages with a single request
+type MultiLanguageSummarizer interface {
+	// SummarizeInLanguages returns the summaries by language code. Languages the response
+	// omits or gets wrong are left out, so callers can fall back to SummarizeInLanguage.
+	SummarizeInLanguages(ctx context.Context, text string, originalTitle string, languageCodes []string, style SummaryStyle, promptTemplate *PromptTemplate) (map[string]*SummaryResponse, error)
 }
 
-// NewGeminiSummarizer creates a new Gemini-based summarizer for RSS feeds
-func NewGeminiSummarizer(apiKey string) *GeminiSummarizer {
-	return &GeminiSummarizer{
-		apiKey:      apiKey,
-		model:       "gemini-2.5-flash",  // Using latest flash model (free tier)
-		prompt:      "Crie um resumo informativo em Português Brasileiro (PT-BR) para desenvolvedores de jogos sobre o seguinte artigo do Godot Engine. O resumo deve ter 3-5 frases, destacando as principais novidades, melhorias ou mudanças importantes. Seja claro, técnico e objetivo. IMPORTANTE: NÃO inclua nenhum preâmbulo, introdução ou frase como 'Aqui está um resumo'. Comece DIRETAMENTE com o conteúdo do resumo:",
-		rateLimiter: ratelimit.NewManager(ratelimit.DefaultConfig()),
-	}
-}
+// ErrCircuitOpen is returned instead of waiting when a summarizer's circuit breaker is open,
+// so a failover chain can move on to the next backend
+var ErrCircuitOpen = errors.New("circuit breaker open")
 
-// NewGeminiSummarizerWithRateLimit creates a new Gemini-based summarizer with custom rate limiting
-func NewGeminiSummarizerWithRateLimit(apiKey string, config ratelimit.Config) *GeminiSummarizer {
-	return &GeminiSummarizer{
-		apiKey:      apiKey,
-		model:       "gemini-2.5-flash",
-		prompt:      "Crie um resumo informativo em Português Brasileiro (PT-BR) para desenvolvedores de jogos sobre o seguinte artigo do Godot Engine. O resumo deve ter 3-5 frases, destacando as principais novidades, melhorias ou mudanças importantes. Seja claro, técnico e objetivo. IMPORTANTE: NÃO inclua nenhum preâmbulo, introdução ou frase como 'Aqui está um resumo'. Comece DIRETAMENTE com o conteúdo do resumo:",
-		rateLimiter: ratelimit.NewManager(config),
-	}
+// Summarizer implements AISummarizer and PRSummarizer on top of any AI Provider.
+// It renders the prompts and shares one rate limiter between article and PR summaries.
+// Prompts use the built-in templates unless the caller passes another one.
+type Summarizer struct {
+	provider    Provider
+	rateLimiter *ratelimit.Manager
 }
 
-// ListAvailableModels returns available Gemini models (for debugging)
-func (s *GeminiSummarizer) ListAvailableModels(ctx context.Context) ([]string, error) {
-	client, err := genai.NewClient(ctx, option.WithAPIKey(s.apiKey))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
-	}
-	defer client.Close()
-
-	var models []string
-	iter := client.ListModels(ctx)
-	for {
-		model, err := iter.Next()
-		if err != nil {
-			break
-		}
-		models = append(models, model.Name)
+// NewSummarizer creates a new summarizer using the given provider and rate limiting
+func NewSummarizer(provider Provider, config ratelimit.Config) *Summarizer {
+	return &Summarizer{
+		provider:    provider,
+		rateLimiter: ratelimit.NewManager(config),
 	}
-	return models, nil
 }
 
 // Summarize generates a TL;DR summary in English (default language) with rate limiting
-func (s *GeminiSummarizer) Summarize(ctx context.Context, text string, originalTitle string) (*SummaryResponse, error) {
+func (s *Summarizer) Summarize(ctx context.Context, text string, originalTitle string) (*SummaryResponse, error) {
 	// Default to English for backward compatibility
-	return s.SummarizeInLanguage(ctx, text, originalTitle, "en")
+	return s.SummarizeInLanguage(ctx, text, originalTitle, "en", StyleStandard, nil)
 }
 
 // SummarizeInLanguage generates a TL;DR summary with translated title in the specified language with rate limiting
-func (s *GeminiSummarizer) SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string) (*SummaryResponse, error) {
+func (s *Summarizer) SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string, style SummaryStyle, promptTemplate *PromptTemplate) (*SummaryResponse, error) {
 	if text == "" {
 		return nil, fmt.Errorf("empty text provided")
 	}
 
 	log.Printf("Starting RSS summary generation in language %s (input length: %d chars)", languageCode, len(text))
 
-	// Get language info
-	langInfo := GetLanguageInfo(languageCode)
+	// Build language-specific prompt; the response format is enforced by the schema
+	promptTemplate = templateOrDefault(promptTemplate, PromptArticle)
+	fullPrompt, err := promptTemplate.Execute(articlePromptData([]string{languageCode}, style.orStandard(), originalTitle, text))
+	if err != nil {
+		return nil, err
+	}
 
-	// Create client
-	client, err := genai.NewClient(ctx, option.WithAPIKey(s.apiKey))
+	// Count tokens before making request
+	inputTokens, err := s.provider.CountTokens(ctx, fullPrompt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		log.Printf("WARNING: Failed to count tokens: %v (proceeding anyway)", err)
+		inputTokens = estimateTokens(fullPrompt)
 	}
-	defer client.Close()
 
-	model := client.GenerativeModel(s.model)
+	estimatedOutputTokens := summaryOutputTokens
+	estimatedTotal := inputTokens + estimatedOutputTokens
+
+	log.Printf("Token estimate for %s: input=%d, estimated_output=%d, total=%d",
+		languageCode, inputTokens, estimatedOutputTokens, estimatedTotal)
+
+	completion, err := s.generate(ctx, fullPrompt, inputTokens, estimatedTotal, GenerateOptions{
+		MaxOutputTokens: summaryOutputTokens,
+		Temperature:     0.7,
+		TopP:            0.95,
+		TopK:            40,
+		ResponseSchema:  summaryResponseSchema,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize in %s: %w", languageCode, err)
+	}
 
-	// Build language-specific prompt with JSON response format
-	fullPrompt := fmt.Sprintf(`You are a technical news summarizer. Analyze the following article and provide:
-1. A translated title in %s (keep it concise, under 100 characters)
-2. A 3-5 sentence technical summary in %s highlighting key updates, improvements, or changes
+	log.Printf("RSS summary generated successfully (output length: %d chars)", len(completion.Text))
 
-%s
+	response, err := ParseSummaryResponse(completion, originalTitle, languageCode)
+	if err != nil {
+		log.Printf("ERROR: Invalid summary response for %s: %v", languageCode, err)
+		return nil, fmt.Errorf("failed to summarize in %s: %w", languageCode, err)
+	}
 
-IMPORTANT:
-- Respond ONLY with valid JSON
-- Use this exact format: {"translated_title": "...", "summary": "..."}
-- Do NOT include any preamble, explanation, markdown formatting, or code blocks
-- Start directly with the JSON object
-- The summary should be clear, technical, and professional
-- If the title is already in %s, you can keep it similar but ensure it's natural
+	response.PromptVersion = promptTemplate.Version
+	log.Printf("Translated title (%s): %s", languageCode, response.TranslatedTitle)
+	return response, nil
+}
 
-Original Title: %s
+// SummarizeInLanguages generates the summaries of an article in several languages with a
+// single request, reserved once against the rate limits. When every language doesn't fit
+// the per-request token limit, the languages are split over as few requests as possible.
+func (s *Summarizer) SummarizeInLanguages(ctx context.Context, text string, originalTitle string, languageCodes []string, style SummaryStyle, promptTemplate *PromptTemplate) (map[string]*SummaryResponse, error) {
+	if text == "" {
+		return nil, fmt.Errorf("empty text provided")
+	}
+	if len(languageCodes) == 0 {
+		return nil, fmt.Errorf("no languages requested")
+	}
 
-Article Content:
-%s`,
-		langInfo.Name,
-		langInfo.Name,
-		langInfo.Instructions,
-		langInfo.Name,
-		originalTitle,
-		text,
-	)
+	log.Printf("Starting RSS summary generation in languages %v (input length: %d chars)", languageCodes, len(text))
 
-	// Count tokens before making request
-	tokenResp, err := model.CountTokens(ctx, genai.Text(fullPrompt))
+	// Count the prompt with every language once; it bounds the prompt of any subset
+	promptTemplate = templateOrDefault(promptTemplate, PromptArticle)
+	fullPrompt, err := multiLanguageArticlePrompt(promptTemplate, languageCodes, style.orStandard(), originalTitle, text)
+	if err != nil {
+		return nil, err
+	}
+	inputTokens, err := s.provider.CountTokens(ctx, fullPrompt)
 	if err != nil {
 		log.Printf("WARNING: Failed to count tokens: %v (proceeding anyway)", err)
-		// Fallback token estimation with overflow protection
-		estimatedTokens := len(fullPrompt) / 4
-		if estimatedTokens > 2147483647 { // Max int32 value
-			estimatedTokens = 2147483647
+		inputTokens = estimateTokens(fullPrompt)
+	}
+
+	perRequest := len(languageCodes)
+	if maxTokens := s.rateLimiter.GetConfig().MaxTokensPerRequest; inputTokens+summaryOutputTokens*perRequest > maxTokens {
+		perRequest = (maxTokens - inputTokens) / summaryOutputTokens
+		if perRequest < 2 {
+			return nil, fmt.Errorf("article too long to summarize several languages within %d tokens per request", maxTokens)
 		}
-		tokenResp = &genai.CountTokensResponse{
-			TotalTokens: int32(estimatedTokens),
+		log.Printf("Splitting %d languages into requests of %d to fit %d tokens per request", len(languageCodes), perRequest, maxTokens)
+	}
+
+	responses := make(map[string]*SummaryResponse, len(languageCodes))
+	var lastErr error
+	for start := 0; start < len(languageCodes); start += perRequest {
+		end := start + perRequest
+		if end > len(languageCodes) {
+			end = len(languageCodes)
+		}
+		batch, err := s.summarizeLanguages(ctx, promptTemplate, style, text, originalTitle, languageCodes[start:end], inputTokens)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for code, response := range batch {
+			responses[code] = response
 		}
 	}
+	if len(responses) == 0 {
+		return nil, lastErr
+	}
 
-	inputTokens := int(tokenResp.TotalTokens)
-	estimatedOutputTokens := 1500
+	log.Printf("RSS summaries generated in %d/%d languages", len(responses), len(languageCodes))
+	return responses, nil
+}
+
+// summarizeLanguages generates the summaries of an article in several languages with one request
+func (s *Summarizer) summarizeLanguages(ctx context.Context, promptTemplate *PromptTemplate, style SummaryStyle, text string, originalTitle string, languageCodes []string, inputTokens int) (map[string]*SummaryResponse, error) {
+	prompt, err := multiLanguageArticlePrompt(promptTemplate, languageCodes, style.orStandard(), originalTitle, text)
+	if err != nil {
+		return nil, err
+	}
+
+	estimatedOutputTokens := summaryOutputTokens * len(languageCodes)
 	estimatedTotal := inputTokens + estimatedOutputTokens
 
-	log.Printf("Token estimate for %s: input=%d, estimated_output=%d, total=%d", 
-		languageCode, inputTokens, estimatedOutputTokens, estimatedTotal)
+	log.Printf("Token estimate for %v: input=%d, estimated_output=%d, total=%d",
+		languageCodes, inputTokens, estimatedOutputTokens, estimatedTotal)
 
-	// Check rate limits before making request
-	can, err := s.rateLimiter.CanMakeRequest(estimatedTotal)
-	if !can {
-		log.Printf("Rate limit check failed for %s: %v", languageCode, err)
-		// Try waiting for capacity
-		waitErr := s.rateLimiter.WaitForCapacity(ctx, estimatedTotal)
-		if waitErr != nil {
-			return nil, fmt.Errorf("rate limit exceeded and wait failed: %w", err)
-		}
-		log.Printf("Rate limit capacity available after waiting for %s", languageCode)
+	completion, err := s.generate(ctx, prompt, inputTokens, estimatedTotal, GenerateOptions{
+		MaxOutputTokens: estimatedOutputTokens,
+		Temperature:     0.7,
+		TopP:            0.95,
+		TopK:            40,
+		ResponseSchema:  languagesSchema(languageCodes, summaryResponseSchema),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize in %v: %w", languageCodes, err)
+	}
+
+	responses, err := ParseSummaryResponses(completion, originalTitle, languageCodes)
+	if err != nil {
+		log.Printf("ERROR: Invalid summary response for %v: %v", languageCodes, err)
+		return nil, fmt.Errorf("failed to summarize in %v: %w", languageCodes, err)
 	}
+	for _, response := range responses {
+		response.PromptVersion = promptTemplate.Version
+	}
+	return responses, nil
+}
+
+// multiLanguageArticlePrompt renders the article summary prompt for several languages
+func multiLanguageArticlePrompt(promptTemplate *PromptTemplate, languageCodes []string, style SummaryStyle, originalTitle, text string) (string, error) {
+	prompt, err := promptTemplate.Execute(articlePromptData(languageCodes, style, originalTitle, text))
+	if err != nil {
+		return "", err
+	}
+	return prompt + respondPerLanguage("one object", languageCodes), nil
+}
 
-	// Retry logic with exponential backoff
-	var rawResponse string
+// respondPerLanguage is appended to multi-language prompts; the codes key the structured response
+func respondPerLanguage(what string, languageCodes []string) string {
+	return fmt.Sprintf("\n\nRespond with %s per language, keyed by its language code (%s).", what, strings.Join(languageCodes, ", "))
+}
+
+// generate sends the prompt to the provider with rate limiting, retrying retryable
+// failures with exponential backoff. inputTokens and estimatedTotal size the rate
+// limit reservation; the actual usage is recorded on success.
+func (s *Summarizer) generate(ctx context.Context, prompt string, inputTokens, estimatedTotal int, options GenerateOptions) (*Completion, error) {
 	var lastErr error
 	maxRetries := s.rateLimiter.GetConfig().RetryAttempts
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			backoff := CalculateBackoff(s.rateLimiter, attempt-1)
-			log.Printf("Retry attempt %d/%d for %s after %v backoff", attempt, maxRetries, languageCode, backoff)
-			
+			log.Printf("Retry attempt %d/%d after %v backoff", attempt, maxRetries, backoff)
+
 			select {
 			case <-time.After(backoff):
 				// Continue with retry
@@ -178,125 +253,90 @@ Article Content:
 			}
 		}
 
-		rawResponse, lastErr = s.attemptSummarize(ctx, model, fullPrompt)
-		
-		if lastErr == nil {
-			// Success - parse JSON response
-			response, parseErr := ParseJSONResponse(rawResponse, originalTitle, languageCode)
-			if parseErr != nil {
-				log.Printf("WARNING: JSON parse error for %s: %v", languageCode, parseErr)
-				log.Printf("Attempting to extract summary from malformed JSON...")
-				
-				// Smarter fallback: try to extract summary even from broken JSON
-				extractedSummary := ExtractSummaryFromBrokenJSON(rawResponse)
-				
-				response = &SummaryResponse{
-					TranslatedTitle: originalTitle,
-					Summary:         extractedSummary,
-				}
-				
-				log.Printf("Extracted summary (%d chars) from malformed JSON", len(extractedSummary))
+		// Fail fast instead of waiting for the circuit breaker timeout
+		if !s.Available() {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w after: %v", ErrCircuitOpen, lastErr)
 			}
-			
+			return nil, fmt.Errorf("%s: %w", s.provider.Name(), ErrCircuitOpen)
+		}
+
+		// Reserve rate limit capacity for this attempt (shared with concurrent feed workers)
+		reservation, err := s.rateLimiter.Reserve(ctx, estimatedTotal)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit exceeded and wait failed: %w", err)
+		}
+
+		completion, err := s.provider.Generate(ctx, prompt, options)
+		if err == nil {
 			// Record request with actual token usage
-			actualTokens := inputTokens + (len(rawResponse) / 4)
-			s.rateLimiter.RecordRequest(actualTokens)
-			log.Printf("Request successful for %s, recorded %d tokens", languageCode, actualTokens)
-			log.Printf("Translated title (%s): %s", languageCode, response.TranslatedTitle)
-			return response, nil
+			actualTokens := inputTokens + estimateTokens(completion.Text)
+			reservation.Complete(actualTokens)
+			log.Printf("Request to %s successful, recorded %d tokens", s.provider.Name(), actualTokens)
+			return completion, nil
 		}
 
 		// Record failure for circuit breaker
-		s.rateLimiter.RecordFailure()
-		log.Printf("Attempt %d failed for %s: %v", attempt, languageCode, lastErr)
+		lastErr = err
+		reservation.Fail()
+		log.Printf("Attempt %d failed: %v", attempt, lastErr)
 
 		// Check if we should retry
 		if !ShouldRetry(lastErr) {
-			log.Printf("Error is not retryable for %s, aborting", languageCode)
+			log.Printf("Error is not retryable, aborting")
 			return nil, lastErr
 		}
 	}
 
-	return nil, fmt.Errorf("failed after %d attempts for %s: %w", maxRetries+1, languageCode, lastErr)
+	return nil, fmt.Errorf("failed after %d attempts: %w", maxRetries+1, lastErr)
 }
 
-// attemptSummarize performs a single summarization attempt
-func (s *GeminiSummarizer) attemptSummarize(ctx context.Context, model *genai.GenerativeModel, prompt string) (string, error) {
-	// Configure model for informative summaries
-	model.SetTemperature(0.7)
-	model.SetMaxOutputTokens(1500)
-	model.SetTopP(0.95)
-	model.SetTopK(40)
-
-	log.Printf("Sending RSS summary request to Gemini API (model: %s)", s.model)
-
-	// Generate content with timing
-	startTime := time.Now()
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	duration := time.Since(startTime)
-	
-	if err != nil {
-		log.Printf("Gemini API error after %v: %v", duration, err)
-		return "", fmt.Errorf("API request failed: %w", err)
-	}
-	
-	log.Printf("Gemini API responded in %v", duration)
-
-	// Extract text from response
-	if len(resp.Candidates) == 0 {
-		log.Printf("WARNING: No candidates in response")
-		return "", fmt.Errorf("no summary generated")
-	}
-
-	candidate := resp.Candidates[0]
-	log.Printf("Response finish reason: %v", candidate.FinishReason)
-	
-	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
-		log.Printf("WARNING: Empty content in response")
-		return "", fmt.Errorf("empty response from Gemini")
-	}
-
-	// Extract text from all parts
-	var summary string
-	for i, part := range candidate.Content.Parts {
-		partText := fmt.Sprintf("%v", part)
-		summary += partText
-		log.Printf("Part %d length: %d chars", i+1, len(partText))
-	}
+// Name identifies the summarizer's provider in logs
+func (s *Summarizer) Name() string {
+	return s.provider.Name()
+}
 
-	if summary == "" {
-		log.Printf("WARNING: No text content extracted")
-		return "", fmt.Errorf("no text content in response")
-	}
+// Available reports whether the summarizer accepts requests (its circuit breaker is closed)
+func (s *Summarizer) Available() bool {
+	return !s.rateLimiter.IsCircuitOpen()
+}
 
-	// Trim whitespace
-	summary = strings.TrimSpace(summary)
-	log.Printf("RSS summary generated successfully (output length: %d chars)", len(summary))
-	
-	// Validate summary isn't cut off mid-sentence
-	if !strings.HasSuffix(summary, ".") && !strings.HasSuffix(summary, "!") && !strings.HasSuffix(summary, "?") {
-		log.Printf("WARNING: Summary may be incomplete (doesn't end with punctuation)")
+// Close releases the provider's resources (e.g. the Gemini client), if it holds any
+func (s *Summarizer) Close() error {
+	if closer, ok := s.provider.(io.Closer); ok {
+		return closer.Close()
 	}
-
-	return summary, nil
+	return nil
 }
 
-// SetPrompt allows customizing the summarization prompt
-func (s *GeminiSummarizer) SetPrompt(prompt string) {
-	s.prompt = prompt
+// GetRateLimitStatistics returns current rate limiting statistics
+func (s *Summarizer) GetRateLimitStatistics() ratelimit.Statistics {
+	return s.rateLimiter.GetStatistics()
 }
 
-// SetModel allows changing the Gemini model
-func (s *GeminiSummarizer) SetModel(model string) {
-	s.model = model
+// BackendStatistics are the rate limiting statistics of one backend
+type BackendStatistics struct {
+	Name       string
+	Statistics ratelimit.Statistics
 }
 
-// GetRateLimitStatistics returns current rate limiting statistics
-func (s *GeminiSummarizer) GetRateLimitStatistics() ratelimit.Statistics {
-	return s.rateLimiter.GetStatistics()
+// RateLimitStatistics returns the rate limiting statistics of a summarizer, one entry per
+// backend of a failover chain; summarizers without rate limits (extractive) have none
+func RateLimitStatistics(summarizer AISummarizer) []BackendStatistics {
+	switch s := summarizer.(type) {
+	case *FailoverSummarizer:
+		return s.RateLimitStatistics()
+	case interface {
+		Name() string
+		GetRateLimitStatistics() ratelimit.Statistics
+	}:
+		return []BackendStatistics{{Name: s.Name(), Statistics: s.GetRateLimitStatistics()}}
+	default:
+		return nil
+	}
 }
 
 // ResetRateLimits resets rate limiting counters (useful for testing)
-func (s *GeminiSummarizer) ResetRateLimits() {
+func (s *Summarizer) ResetRateLimits() {
 	s.rateLimiter.Reset()
 }
@@ -0,0 +1,202 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// SchemaType is the JSON type of a schema node
+type SchemaType string
+
+// Schema types supported by structured output
+const (
+	SchemaObject  SchemaType = "object"
+	SchemaArray   SchemaType = "array"
+	SchemaString  SchemaType = "string"
+	SchemaBoolean SchemaType = "boolean"
+)
+
+// Schema describes the JSON a provider must respond with (structured output). It marshals
+// to JSON Schema for OpenAI-compatible servers and is converted for Gemini.
+type Schema struct {
+	Type        SchemaType         `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+const (
+	// Discord embed titles are limited to 256 characters
+	maxSummaryTitleLength = 256
+	maxSummaryKeyPoints   = 5
+	maxSummaryTags        = 5
+)
+
+// summaryResponseSchema is the structured output schema of SummaryResponse
+var summaryResponseSchema = &Schema{
+	Type: SchemaObject,
+	Properties: map[string]*Schema{
+		"translated_title": {
+			Type:        SchemaString,
+			Description: "The article title translated to the target language, under 100 characters",
+		},
+		"summary": {
+			Type:        SchemaString,
+			Description: "The technical summary in the target language, in the requested length and format",
+		},
+		"key_points": {
+			Type:        SchemaArray,
+			Description: "Up to 5 short key points of the article in the target language",
+			Items:       &Schema{Type: SchemaString},
+		},
+		"tags": {
+			Type:        SchemaArray,
+			Description: "Up to 5 short lowercase topic tags in English (e.g. rendering, gdscript, release)",
+			Items:       &Schema{Type: SchemaString},
+		},
+		"breaking_change": {
+			Type:        SchemaBoolean,
+			Description: "Whether the article announces changes that break existing projects or APIs",
+		},
+	},
+	Required: []string{"translated_title", "summary", "key_points", "tags", "breaking_change"},
+}
+
+// languagesSchema wraps a schema in an object with one required property per language code
+func languagesSchema(languageCodes []string, schema *Schema) *Schema {
+	properties := make(map[string]*Schema, len(languageCodes))
+	for _, code := range languageCodes {
+		properties[code] = schema
+	}
+	return &Schema{
+		Type:       SchemaObject,
+		Properties: properties,
+		Required:   append([]string(nil), languageCodes...),
+	}
+}
+
+// SummaryValidationError reports a structured summary response that doesn't match the schema
+type SummaryValidationError struct {
+	Field  string // JSON field at fault, empty when the response as a whole is invalid
+	Reason string
+	Err    error // underlying decoding error, if any
+}
+
+func (e *SummaryValidationError) Error() string {
+	message := "invalid summary response"
+	if e.Field != "" {
+		message += fmt.Sprintf(": field %q", e.Field)
+	}
+	message += ": " + e.Reason
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *SummaryValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ParseSummaryResponse decodes and validates a structured summary response. A missing title
+// falls back to the original one; anything else that doesn't match the schema is reported
+// as a *SummaryValidationError.
+func ParseSummaryResponse(completion *Completion, originalTitle string, languageCode string) (*SummaryResponse, error) {
+	if completion.Truncated {
+		return nil, &SummaryValidationError{Reason: "response truncated at the output token limit"}
+	}
+
+	var response SummaryResponse
+	if err := json.Unmarshal([]byte(completion.Text), &response); err != nil {
+		log.Printf("Raw response (first 200 chars): %s", TruncateString(completion.Text, 200))
+		return nil, &SummaryValidationError{Reason: "not a JSON object", Err: err}
+	}
+
+	response.Summary = strings.TrimSpace(response.Summary)
+	if response.Summary == "" {
+		return nil, &SummaryValidationError{Field: "summary", Reason: "empty"}
+	}
+
+	response.TranslatedTitle = strings.TrimSpace(response.TranslatedTitle)
+	if response.TranslatedTitle == "" {
+		log.Printf("WARNING: Empty translated title for %s, using original", languageCode)
+		response.TranslatedTitle = originalTitle
+	}
+	if len(response.TranslatedTitle) > maxSummaryTitleLength {
+		log.Printf("WARNING: Title too long for %s (%d chars), truncating", languageCode, len(response.TranslatedTitle))
+		response.TranslatedTitle = response.TranslatedTitle[:maxSummaryTitleLength-3] + "..."
+	}
+
+	response.KeyPoints = cleanList(response.KeyPoints, maxSummaryKeyPoints, false)
+	response.Tags = cleanList(response.Tags, maxSummaryTags, true)
+
+	return &response, nil
+}
+
+// ParseSummaryResponses decodes a multi-language structured response keyed by language code.
+// Languages that are missing or invalid are logged and left out; the response fails as a
+// whole only when it can't be decoded or has no valid language.
+func ParseSummaryResponses(completion *Completion, originalTitle string, languageCodes []string) (map[string]*SummaryResponse, error) {
+	byLanguage, err := decodeLanguages(completion)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(map[string]*SummaryResponse, len(languageCodes))
+	for _, code := range languageCodes {
+		raw, ok := byLanguage[code]
+		if !ok {
+			log.Printf("WARNING: Summary response has no %s summary", code)
+			continue
+		}
+		response, err := ParseSummaryResponse(&Completion{Text: string(raw)}, originalTitle, code)
+		if err != nil {
+			log.Printf("WARNING: Invalid %s summary in response: %v", code, err)
+			continue
+		}
+		responses[code] = response
+	}
+
+	if len(responses) == 0 {
+		return nil, &SummaryValidationError{Reason: "no valid summary for any requested language"}
+	}
+	return responses, nil
+}
+
+// decodeLanguages decodes a structured response object keyed by language code
+func decodeLanguages(completion *Completion) (map[string]json.RawMessage, error) {
+	if completion.Truncated {
+		return nil, &SummaryValidationError{Reason: "response truncated at the output token limit"}
+	}
+
+	var byLanguage map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(completion.Text), &byLanguage); err != nil {
+		log.Printf("Raw response (first 200 chars): %s", TruncateString(completion.Text, 200))
+		return nil, &SummaryValidationError{Reason: "not a JSON object", Err: err}
+	}
+	return byLanguage, nil
+}
+
+// cleanList trims the entries of a list, dropping empty and duplicate ones, and caps its length
+func cleanList(items []string, max int, lowercase bool) []string {
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if lowercase {
+			item = strings.ToLower(strings.TrimPrefix(item, "#"))
+		}
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		cleaned = append(cleaned, item)
+		if len(cleaned) == max {
+			break
+		}
+	}
+	return cleaned
+}
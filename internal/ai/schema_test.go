@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSummaryResponse(t *testing.T) {
+	tests := []struct {
+		name          string
+		completion    Completion
+		expected      *SummaryResponse
+		expectedField string
+	}{
+		{
+			name: "structured response",
+			completion: Completion{Text: `{"translated_title": "Godot 4.4 lançado", "summary": "Resumo.",
+				"key_points": ["Dicionários tipados", " ", "Jolt"], "tags": ["#Release", "gdscript", "release"], "breaking_change": true}`},
+			expected: &SummaryResponse{
+				TranslatedTitle: "Godot 4.4 lançado",
+				Summary:         "Resumo.",
+				KeyPoints:       []string{"Dicionários tipados", "Jolt"},
+				Tags:            []string{"release", "gdscript"},
+				BreakingChange:  true,
+			},
+		},
+		{
+			name:       "missing title uses the original",
+			completion: Completion{Text: `{"summary": "Summary."}`},
+			expected:   &SummaryResponse{TranslatedTitle: "Original", Summary: "Summary."},
+		},
+		{
+			name:          "empty summary",
+			completion:    Completion{Text: `{"translated_title": "Title", "summary": "  "}`},
+			expectedField: "summary",
+		},
+		{
+			name:       "not JSON",
+			completion: Completion{Text: "Here is a summary: Godot 4.4 is out."},
+		},
+		{
+			name:       "truncated",
+			completion: Completion{Text: `{"translated_title": "Title", "summary": "Cut`, Truncated: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := ParseSummaryResponse(&tt.completion, "Original", "pt-BR")
+			if tt.expected == nil {
+				var validationErr *SummaryValidationError
+				require.True(t, errors.As(err, &validationErr), "expected a validation error, got %v", err)
+				assert.Equal(t, tt.expectedField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, response)
+		})
+	}
+}
+
+func TestParseSummaryResponse_LongTitle(t *testing.T) {
+	completion := &Completion{Text: `{"translated_title": "` + strings.Repeat("a", 300) + `", "summary": "Summary."}`}
+
+	response, err := ParseSummaryResponse(completion, "Original", "en")
+	require.NoError(t, err)
+	assert.Len(t, response.TranslatedTitle, maxSummaryTitleLength)
+	assert.True(t, strings.HasSuffix(response.TranslatedTitle, "..."))
+}
+
+func TestSummaryResponseSchema(t *testing.T) {
+	// Every required field is described, and the JSON Schema sent to OpenAI-compatible servers is valid JSON
+	for _, field := range summaryResponseSchema.Required {
+		assert.Contains(t, summaryResponseSchema.Properties, field)
+	}
+
+	data, err := json.Marshal(summaryResponseSchema)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"key_points":{"type":"array"`)
+
+	converted := geminiSchema(summaryResponseSchema)
+	assert.Equal(t, genai.TypeObject, converted.Type)
+	assert.Equal(t, genai.TypeBoolean, converted.Properties["breaking_change"].Type)
+	assert.Equal(t, genai.TypeString, converted.Properties["tags"].Items.Type)
+}
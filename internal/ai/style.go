@@ -0,0 +1,66 @@
+package ai
+
+import "fmt"
+
+// SummaryStyle is the length and format of an article summary, chosen per channel
+type SummaryStyle string
+
+// Summary styles
+const (
+	StyleTLDR     SummaryStyle = "tldr"     // One-line TL;DR
+	StyleStandard SummaryStyle = "standard" // 3-5 sentences (the default)
+	StyleDetailed SummaryStyle = "detailed" // A few paragraphs
+	StyleBullet   SummaryStyle = "bullet"   // Bullet points
+)
+
+// SummaryStyles returns every summary style
+func SummaryStyles() []SummaryStyle {
+	return []SummaryStyle{StyleTLDR, StyleStandard, StyleDetailed, StyleBullet}
+}
+
+// ParseSummaryStyle validates a summary style name; an empty name is the standard style
+func ParseSummaryStyle(name string) (SummaryStyle, error) {
+	if name == "" {
+		return StyleStandard, nil
+	}
+	for _, style := range SummaryStyles() {
+		if string(style) == name {
+			return style, nil
+		}
+	}
+	return "", fmt.Errorf("unknown summary style %q (expected tldr, standard, detailed or bullet)", name)
+}
+
+// Instructions describes the summary the style asks for, as used in prompts
+func (s SummaryStyle) Instructions() string {
+	switch s {
+	case StyleTLDR:
+		return "A one-sentence TL;DR (under 200 characters)"
+	case StyleDetailed:
+		return "A detailed technical summary of 2-3 short paragraphs (8-12 sentences)"
+	case StyleBullet:
+		return "A technical summary written as 4-8 markdown bullet points (one line each, starting with •)"
+	default:
+		return "A 3-5 sentence technical summary"
+	}
+}
+
+// extractiveSentences is how many sentences extractive summaries of the style pick
+func (s SummaryStyle) extractiveSentences() int {
+	switch s {
+	case StyleTLDR:
+		return 1
+	case StyleDetailed:
+		return 8
+	default:
+		return maxSummarySentences
+	}
+}
+
+// orStandard returns the style, or the standard style when it is empty
+func (s SummaryStyle) orStandard() SummaryStyle {
+	if s == "" {
+		return StyleStandard
+	}
+	return s
+}
@@ -2,26 +2,53 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/GustavoLR548/godot-news-bot/internal/filter"
 	"github.com/GustavoLR548/godot-news-bot/internal/news"
+	"github.com/GustavoLR548/godot-news-bot/internal/schedule"
 	"github.com/GustavoLR548/godot-news-bot/internal/storage"
 	"github.com/bwmarrin/discordgo"
 )
 
+const (
+	// defaultMaxArticlesPerCheck caps how many new articles a single feed check may post
+	defaultMaxArticlesPerCheck = 5
+	// defaultFallbackInterval is used for unscheduled feeds when the check interval is not a valid schedule
+	defaultFallbackInterval = 15 * time.Minute
+	// minFeedContentLength is the shortest feed-provided content (in characters) summarized without scraping the article page
+	minFeedContentLength = 500
+)
+
 // Bot represents the Discord bot with its dependencies
 type Bot struct {
-	session       *discordgo.Session
-	newsFetcher   news.NewsFetcher
-	aiSummarizer  ai.AISummarizer
-	channelRepo   storage.ChannelRepository
-	historyRepo   storage.RSSHistoryRepository
-	feedRepo      storage.RSSFeedRepository
-	checkInterval time.Duration
-	stopChan      chan bool
+	session             *discordgo.Session
+	newsFetcher         news.NewsFetcher
+	aiSummarizer        ai.AISummarizer
+	extractive          ai.AISummarizer // offline summarizer for feeds with extractive summaries
+	channelRepo         storage.ChannelRepository
+	historyRepo         storage.RSSHistoryRepository
+	feedRepo            storage.RSSFeedRepository
+	checkInterval       time.Duration
+	maxArticlesPerCheck int
+	lastScheduleCheck   time.Time      // end of the previous scheduling window
+	feedAttempts        attemptTracker // in-process feed run attempts
+	feedPool            *feedPool      // bounds concurrent feed processing
+	leases              *storage.LeaseManager
+	leader              *leaderElection
+	delivery            *deliverer // records per-channel deliveries and retries failed posts
+	summaryCache        storage.SummaryCache
+	prompts             promptResolver
+	guildRepo           storage.GuildRepository // frozen guilds, nil when disabled
+	stopChan            chan bool
 }
 
 // NewBot creates a new bot instance with all dependencies
@@ -34,33 +61,110 @@ func NewBot(
 	feedRepo storage.RSSFeedRepository,
 	checkInterval time.Duration,
 ) *Bot {
-	return &Bot{
-		session:       session,
-		newsFetcher:   newsFetcher,
-		aiSummarizer:  aiSummarizer,
-		channelRepo:   channelRepo,
-		historyRepo:   historyRepo,
-		feedRepo:      feedRepo,
-		checkInterval: checkInterval,
-		stopChan:      make(chan bool),
+	b := &Bot{
+		session:             session,
+		newsFetcher:         newsFetcher,
+		aiSummarizer:        aiSummarizer,
+		extractive:          ai.NewExtractiveSummarizer(),
+		channelRepo:         channelRepo,
+		historyRepo:         historyRepo,
+		feedRepo:            feedRepo,
+		checkInterval:       checkInterval,
+		maxArticlesPerCheck: defaultMaxArticlesPerCheck,
+		feedPool:            newFeedPool(defaultFeedWorkers),
+		stopChan:            make(chan bool),
+	}
+	b.delivery = &deliverer{
+		kind: deliveryKindRSS,
+		send: b.sendEmbed,
+		subscriptions: func(channelID string) ([]string, error) {
+			return b.channelRepo.GetChannelFeeds(channelID)
+		},
+		frozen: func(channelID string) bool {
+			return len(withoutFrozenGuilds(b.guildRepo, []string{channelID}, b.channelRepo.GetChannelGuild)) == 0
+		},
+		unsubscribe: b.unsubscribeChannel,
+		notify: func(channelID, message string) {
+			notifyGuildOwner(b.session, channelID, message)
+		},
+	}
+	return b
+}
+
+// SetMaxArticlesPerCheck sets how many new articles a single feed check may post.
+// Older unseen articles beyond the cap are marked as seen without being posted.
+func (b *Bot) SetMaxArticlesPerCheck(max int) {
+	if max > 0 {
+		b.maxArticlesPerCheck = max
 	}
 }
 
+// SetFeedWorkers sets how many feeds may be processed concurrently (call before Start)
+func (b *Bot) SetFeedWorkers(workers int) {
+	if workers > 0 {
+		b.feedPool = newFeedPool(workers)
+	}
+}
+
+// SetLeaseManager coordinates this instance with other bot instances sharing the
+// same Redis (call before Start): feeds are locked while processed and only the
+// elected leader runs the scheduler
+func (b *Bot) SetLeaseManager(leases *storage.LeaseManager) {
+	b.leases = leases
+	b.leader = newLeaderElection(leases, "rss-scheduler")
+}
+
+// SetDeliveryRepository enables per-channel delivery records and retries of failed posts
+func (b *Bot) SetDeliveryRepository(deliveries storage.DeliveryRepository) {
+	b.delivery.deliveries = deliveries
+}
+
+// SetSummaryCache enables caching of article summaries, so articles posted again
+// (retries, manual updates, newly subscribed channels) reuse their summaries
+func (b *Bot) SetSummaryCache(cache storage.SummaryCache) {
+	b.summaryCache = cache
+}
+
+// SetPromptRepository enables custom prompt templates per feed and per guild
+func (b *Bot) SetPromptRepository(prompts storage.PromptRepository) {
+	b.prompts.repo = prompts
+}
+
+// SetPromptTemplates replaces the built-in prompt templates used when a feed or guild has none
+func (b *Bot) SetPromptTemplates(templates map[ai.PromptKind]*ai.PromptTemplate) {
+	b.prompts.configured = templates
+}
+
+// SetGuildRepository stops posts to channels of guilds frozen by the bot owners
+func (b *Bot) SetGuildRepository(guildRepo storage.GuildRepository) {
+	b.guildRepo = guildRepo
+}
+
 // Start begins the news checking loop with time-based scheduling
 func (b *Bot) Start() {
-	log.Println("Starting multi-feed news check loop...")
+	log.Printf("Starting multi-feed news check loop (%d workers)...", b.feedPool.workers())
 	
-	// Run immediately on start
-	b.checkAndPostNews()
+	// Run immediately on start for anything due in the current minute
+	now := time.Now()
+	if b.leader.isLeader() {
+		b.checkAndPostNews(now.Add(-time.Minute), now)
+	}
+	b.lastScheduleCheck = now
 	
-	// Check every minute for scheduled times
+	// Every minute, check feeds whose next run falls in the elapsed window
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
-			b.checkAndPostNews()
+		case now := <-ticker.C:
+			// Standby instances only track time; after a failover the new leader
+			// catches up on the slots missed since each feed's last run
+			if b.leader.isLeader() {
+				b.checkAndPostNews(b.lastScheduleCheck, now)
+				b.delivery.retryDue(now)
+			}
+			b.lastScheduleCheck = now
 		case <-b.stopChan:
 			log.Println("News loop stopped")
 			return
@@ -68,17 +172,56 @@ func (b *Bot) Start() {
 	}
 }
 
-// Stop gracefully stops the news checking loop
+// Stop gracefully stops the news checking loop, waits for the feeds being processed and
+// hands over scheduler leadership
 func (b *Bot) Stop() {
 	close(b.stopChan)
+	log.Println("Waiting for feeds being processed...")
+	b.feedPool.stop()
+	b.leader.resign()
 }
 
 // CheckAndPostNews checks for new articles and posts them (public method for manual triggers)
 // Returns true if successful (news found and posted or no news), false if error occurred
 func (b *Bot) CheckAndPostNews() bool {
 	log.Println("Manual check for new articles triggered (all feeds)...")
-	b.checkAndPostNews()
-	return true
+
+	feeds, err := b.feedRepo.GetAllFeeds()
+	if err != nil {
+		log.Printf("Error getting feeds: %v", err)
+		return false
+	}
+
+	// Manual triggers ignore schedules; feeds are processed concurrently on the worker pool
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	success := true
+	for _, feed := range feeds {
+		wg.Add(1)
+		go func(feed storage.RSSFeed) {
+			defer wg.Done()
+
+			log.Printf("Checking feed: %s (%s)", feed.Title, feed.ID)
+			err := b.feedPool.run(feed.ID, func() error { return b.runFeed(&feed) })
+			if errors.Is(err, errFeedBusy) || errors.Is(err, errLeaseHeld) {
+				log.Printf("Feed %s is already being processed, skipping", feed.ID)
+				return
+			}
+			if errors.Is(err, errPoolStopped) {
+				log.Printf("Bot is stopping, skipping feed %s", feed.ID)
+				return
+			}
+			if err != nil {
+				log.Printf("Error checking feed %s: %v", feed.ID, err)
+				mu.Lock()
+				success = false
+				mu.Unlock()
+			}
+		}(feed)
+	}
+	wg.Wait()
+
+	return success
 }
 
 // CheckAndPostFeedNews checks for new articles from a specific feed (public method for manual triggers)
@@ -93,13 +236,16 @@ func (b *Bot) CheckAndPostFeedNews(feedID string) bool {
 		return false
 	}
 	
-	// Process this feed immediately
-	b.processFeed(feed)
+	// Process this feed immediately, unless the scheduler is already processing it
+	if err := b.feedPool.run(feed.ID, func() error { return b.runFeed(feed) }); err != nil {
+		log.Printf("Error checking feed %s: %v", feedID, err)
+		return false
+	}
 	return true
 }
 
-// checkAndPostNews checks all feeds and posts new articles based on schedules
-func (b *Bot) checkAndPostNews() {
+// checkAndPostNews dispatches the feeds scheduled to run in the window (since, now] to the worker pool
+func (b *Bot) checkAndPostNews(since, now time.Time) {
 	// Get all registered feeds
 	feeds, err := b.feedRepo.GetAllFeeds()
 	if err != nil {
@@ -112,120 +258,269 @@ func (b *Bot) checkAndPostNews() {
 		return
 	}
 
-	currentTime := time.Now()
-	currentHourMin := currentTime.Format("15:04")
+	// Feeds without a schedule run every check interval
+	fallback, err := schedule.Every(b.checkInterval)
+	if err != nil {
+		log.Printf("Invalid check interval %v, using %v: %v", b.checkInterval, defaultFallbackInterval, err)
+		fallback, _ = schedule.Every(defaultFallbackInterval)
+	}
 
 	// Process each feed independently
 	for _, feed := range feeds {
-		// Check if this feed should be checked now
-		shouldCheck := false
-		
+		specs := []*schedule.Spec{fallback}
 		if len(feed.Schedule) > 0 {
-			// Time-based scheduling
-			for _, scheduledTime := range feed.Schedule {
-				if scheduledTime == currentHourMin {
-					shouldCheck = true
-					log.Printf("Feed %s matches scheduled time %s", feed.ID, scheduledTime)
-					break
-				}
+			specs, err = schedule.ParseAll(feed.Schedule)
+			if err != nil {
+				log.Printf("Feed %s has an invalid schedule, skipping: %v", feed.ID, err)
+				continue
 			}
-		} else {
-			// Fallback to interval-based (check every interval)
-			// For interval fallback, we check less frequently to avoid overwhelming
-			shouldCheck = (currentTime.Minute() % 15 == 0) // Check every 15 minutes
 		}
 
-		if shouldCheck {
-			log.Printf("Checking feed: %s (%s)", feed.Title, feed.ID)
-			b.processFeed(&feed)
+		// Schedules are evaluated in the feed's timezone
+		loc, err := schedule.LoadLocation(feed.Timezone)
+		if err != nil {
+			log.Printf("Feed %s has an invalid timezone, using local time: %v", feed.ID, err)
+			loc = time.Local
+		}
+
+		// Look back to the last successful run so slots missed during downtime run once
+		lastRun, err := b.feedRepo.GetLastRun(feed.ID)
+		if err != nil {
+			log.Printf("WARNING: Failed to get last run for feed %s: %v", feed.ID, err)
+		}
+		windowStart := scheduleWindowStart(lastRun, b.feedAttempts.last(feed.ID), since, now)
+
+		if !schedule.Due(specs, loc, windowStart, now) {
+			continue
+		}
+		if !schedule.Due(specs, loc, since, now) {
+			log.Printf("Catching up missed schedule for feed %s (last run: %s)", feed.ID, lastRun.Format(time.RFC3339))
 		}
+
+		// Process due feeds concurrently so one slow feed doesn't delay the others.
+		// The loop does not wait for them; a feed still running at the next tick is skipped,
+		// and Stop waits for the runs in progress.
+		go func(feed storage.RSSFeed) {
+			log.Printf("Checking feed: %s (%s)", feed.Title, feed.ID)
+			err := b.feedPool.run(feed.ID, func() error { return b.runFeed(&feed) })
+			if errors.Is(err, errFeedBusy) || errors.Is(err, errLeaseHeld) {
+				log.Printf("Feed %s is still being processed, skipping this run", feed.ID)
+				return
+			}
+			if errors.Is(err, errPoolStopped) {
+				log.Printf("Bot is stopping, skipping feed %s", feed.ID)
+				return
+			}
+			if err != nil {
+				log.Printf("Error checking feed %s: %v", feed.ID, err)
+			}
+		}(feed)
 	}
 }
 
-// processFeed processes a single feed - checks for new articles and posts them
-func (b *Bot) processFeed(feed *storage.RSSFeed) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+// runFeed processes a feed under its lease and records its last successful run for
+// schedule catch-up. It returns errLeaseHeld if another instance is processing the feed.
+func (b *Bot) runFeed(feed *storage.RSSFeed) error {
+	ctx, release, err := acquireProcessingLease(context.Background(), b.leases, "feed:"+feed.ID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	startedAt := time.Now()
+	b.feedAttempts.record(feed.ID, startedAt)
+
+	if err := b.processFeed(ctx, feed); err != nil {
+		return err
+	}
+
+	if err := b.feedRepo.SetLastRun(feed.ID, startedAt); err != nil {
+		log.Printf("WARNING: Failed to save last run for feed %s: %v", feed.ID, err)
+	}
+	return nil
+}
+
+// processFeed processes a single feed - checks for new articles and posts them.
+// It returns an error when the feed could not be checked; failures of individual
+// articles are logged and retried on the next check. Posting stops once ctx is done.
+func (b *Bot) processFeed(ctx context.Context, feed *storage.RSSFeed) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
 	// Get channels subscribed to this feed
 	channels, err := b.channelRepo.GetFeedChannels(feed.ID)
 	if err != nil {
-		log.Printf("Error getting channels for feed %s: %v", feed.ID, err)
-		return
+		return fmt.Errorf("failed to get channels for feed %s: %w", feed.ID, err)
 	}
+	channels = withoutFrozenGuilds(b.guildRepo, channels, b.channelRepo.GetChannelGuild)
 
-	// Process pending queue first if channels exist
+	// Pending articles are posted once channels exist (GetPending returns the oldest first)
+	var pendingGUIDs []string
 	if len(channels) > 0 {
-		pendingGUIDs, err := b.historyRepo.GetPending(feed.ID)
+		pendingGUIDs, err = b.historyRepo.GetPending(feed.ID)
 		if err != nil {
 			log.Printf("Error getting pending queue for feed %s: %v", feed.ID, err)
-		} else if len(pendingGUIDs) > 0 {
-			log.Printf("Processing %d pending article(s) for feed %s...", len(pendingGUIDs), feed.ID)
-			for _, guid := range pendingGUIDs {
-				if err := b.processPendingArticle(ctx, feed, guid, channels); err != nil {
-					log.Printf("Error processing pending article %s: %v", guid, err)
-				} else {
-					if err := b.historyRepo.RemoveFromPending(feed.ID, guid); err != nil {
-						log.Printf("Error removing from pending: %v", err)
-					}
-				}
-			}
 		}
 	}
 
-	// Create a fetcher for this feed's URL
+	// Create a fetcher for this feed's URL, primed with the validators of the last fetch.
+	// Pending articles were already in the feed at that fetch, so they need a full fetch
+	// to be found; otherwise they would wait for the feed to change.
 	feedFetcher := news.NewRSSFetcher(feed.URL)
-	
-	// Fetch latest article from this feed
-	article, err := feedFetcher.FetchLatestArticle()
+	if len(pendingGUIDs) == 0 {
+		if etag, lastModified, err := b.feedRepo.GetHTTPValidators(feed.ID); err != nil {
+			log.Printf("WARNING: Failed to get HTTP validators for feed %s: %v", feed.ID, err)
+		} else {
+			feedFetcher.SetValidators(etag, lastModified)
+		}
+	}
+
+	// Fetch every article currently in the feed, once for the pending and the new articles
+	articles, err := feedFetcher.FetchArticles()
+	if errors.Is(err, news.ErrNotModified) {
+		log.Printf("Feed %s not modified since last fetch", feed.ID)
+		return nil
+	}
 	if err != nil {
-		log.Printf("Error fetching article from feed %s: %v", feed.ID, err)
-		return
+		return fmt.Errorf("failed to fetch articles from feed %s: %w", feed.ID, err)
+	}
+
+	if len(pendingGUIDs) > 0 {
+		log.Printf("Processing %d pending article(s) for feed %s...", len(pendingGUIDs), feed.ID)
+		for _, guid := range pendingGUIDs {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := b.processPendingArticle(ctx, feed, feedFetcher, articles, guid, channels); err != nil {
+				log.Printf("Error processing pending article %s: %v", guid, err)
+			} else {
+				if err := b.historyRepo.RemoveFromPending(feed.ID, guid); err != nil {
+					log.Printf("Error removing from pending: %v", err)
+				}
+			}
+		}
 	}
 
-	// Check if article is new for this feed
-	lastGUID, err := b.historyRepo.GetLastGUID(feed.ID)
+	// Only remember the new validators once every article has been handled, otherwise
+	// the next fetch could answer 304 and hide articles that failed to post
+	allHandled := true
+	defer func() {
+		if !allHandled {
+			return
+		}
+		etag, lastModified := feedFetcher.Validators()
+		if err := b.feedRepo.SetHTTPValidators(feed.ID, etag, lastModified); err != nil {
+			log.Printf("WARNING: Failed to save HTTP validators for feed %s: %v", feed.ID, err)
+		}
+	}()
+
+	// Keep only articles not yet recorded in this feed's history
+	newArticles, err := b.unseenArticles(feed.ID, articles)
 	if err != nil {
-		log.Printf("Error getting last GUID for feed %s: %v", feed.ID, err)
-		return
+		allHandled = false
+		return fmt.Errorf("failed to check history for feed %s: %w", feed.ID, err)
 	}
 
-	if !news.IsNewArticle(article.GUID, lastGUID) {
+	if len(newArticles) == 0 {
 		log.Printf("No new articles in feed %s", feed.ID)
-		return
+		return nil
 	}
 
-	log.Printf("New article found in feed %s: %s", feed.ID, article.Title)
+	// Enforce the per-feed cap: only the newest articles are posted, older ones are
+	// marked as seen so a first run or a GUID rewrite can't flood the channels
+	if len(newArticles) > b.maxArticlesPerCheck {
+		skipped := newArticles[:len(newArticles)-b.maxArticlesPerCheck]
+		newArticles = newArticles[len(newArticles)-b.maxArticlesPerCheck:]
 
-	// If no channels subscribed, add to pending queue
-	if len(channels) == 0 {
-		log.Printf("No channels subscribed to feed %s, adding to pending queue", feed.ID)
-		if err := b.historyRepo.AddToPending(feed.ID, article.GUID); err != nil {
-			log.Printf("Error adding to pending queue: %v", err)
+		log.Printf("Feed %s has %d new articles, posting the newest %d and skipping %d",
+			feed.ID, len(skipped)+len(newArticles), len(newArticles), len(skipped))
+		for _, article := range skipped {
+			if err := b.historyRepo.SaveGUID(feed.ID, article.GUID); err != nil {
+				log.Printf("Error saving skipped GUID %s: %v", article.GUID, err)
+			}
 		}
-		if err := b.historyRepo.SaveGUID(feed.ID, article.GUID); err != nil {
-			log.Printf("Error saving GUID: %v", err)
+	}
+
+	log.Printf("Found %d new article(s) in feed %s", len(newArticles), feed.ID)
+
+	for i := range newArticles {
+		// Stop posting if the run was aborted (e.g. the feed's lease was lost),
+		// so another instance doesn't post the same articles
+		if err := ctx.Err(); err != nil {
+			allHandled = false
+			return fmt.Errorf("processing of feed %s aborted: %w", feed.ID, err)
+		}
+
+		article := &newArticles[i]
+		log.Printf("New article found in feed %s: %s", feed.ID, article.Title)
+
+		// If no channels subscribed, add to pending queue (unless the feed's filters drop it)
+		if len(channels) == 0 {
+			allowed, err := b.feedAllows(feed.ID, article)
+			if err != nil {
+				log.Printf("Error evaluating filters for feed %s: %v", feed.ID, err)
+				allHandled = false
+				continue
+			}
+			if !allowed {
+				log.Printf("Article %s filtered out by feed %s filters", article.GUID, feed.ID)
+				if err := b.historyRepo.SaveGUID(feed.ID, article.GUID); err != nil {
+					log.Printf("Error saving GUID: %v", err)
+				}
+				continue
+			}
+
+			log.Printf("No channels subscribed to feed %s, adding to pending queue", feed.ID)
+			if err := b.historyRepo.AddToPending(feed.ID, article.GUID); err != nil {
+				log.Printf("Error adding to pending queue: %v", err)
+			}
+			if err := b.historyRepo.SaveGUID(feed.ID, article.GUID); err != nil {
+				log.Printf("Error saving GUID: %v", err)
+			}
+			continue
+		}
+
+		// Process and post the article
+		if err := b.processAndPostArticle(ctx, feed, feedFetcher, article, channels); err != nil {
+			log.Printf("Error processing article %s: %v", article.GUID, err)
+			allHandled = false
 		}
-		return
 	}
 
-	// Process and post the article
-	if err := b.processAndPostArticle(ctx, feed, feedFetcher, article, channels); err != nil {
-		log.Printf("Error processing article: %v", err)
+	return nil
+}
+
+// unseenArticles returns the articles not yet recorded in the feed's history, oldest first
+func (b *Bot) unseenArticles(feedID string, articles []news.Article) ([]news.Article, error) {
+	unseen := make([]news.Article, 0, len(articles))
+	for _, article := range articles {
+		seen, err := b.historyRepo.HasGUID(feedID, article.GUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check GUID %s: %w", article.GUID, err)
+		}
+		if !seen {
+			unseen = append(unseen, article)
+		}
 	}
+
+	sortOldestFirst(unseen)
+	return unseen, nil
 }
 
-// processPendingArticle fetches and posts a pending article by GUID
-func (b *Bot) processPendingArticle(ctx context.Context, feed *storage.RSSFeed, guid string, channels []string) error {
-	// Create fetcher for this feed
-	feedFetcher := news.NewRSSFetcher(feed.URL)
-	
-	// Fetch the full feed to find this article
-	articles, err := feedFetcher.FetchArticles()
-	if err != nil {
-		return fmt.Errorf("failed to fetch articles: %w", err)
+// sortOldestFirst orders articles by publish date, oldest first. Feeds list their
+// items newest first, so ties keep the reverse of the feed order.
+func sortOldestFirst(articles []news.Article) {
+	for i, j := 0, len(articles)-1; i < j; i, j = i+1, j-1 {
+		articles[i], articles[j] = articles[j], articles[i]
 	}
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].PublishDate.Before(articles[j].PublishDate)
+	})
+}
 
+// processPendingArticle posts a pending article by GUID, looking it up in the feed's
+// current articles
+func (b *Bot) processPendingArticle(ctx context.Context, feed *storage.RSSFeed, feedFetcher news.NewsFetcher, articles []news.Article, guid string, channels []string) error {
 	// Find the article with this GUID
 	var article *news.Article
 	for _, a := range articles {
@@ -246,17 +541,42 @@ func (b *Bot) processPendingArticle(ctx context.Context, feed *storage.RSSFeed,
 
 // processAndPostArticle scrapes, summarizes, and posts an article with multilingual support
 func (b *Bot) processAndPostArticle(ctx context.Context, feed *storage.RSSFeed, fetcher news.NewsFetcher, article *news.Article, channels []string) error {
-	log.Printf("Generating summaries for %d channel(s) subscribed to feed %s...", len(channels), feed.ID)
-
-	// Scrape article content
-	content, err := fetcher.ScrapeArticleContent(article.Link)
+	// Apply feed and subscription filters before spending any scraping or AI quota
+	channels, err := b.acceptingChannels(feed.ID, article, channels)
 	if err != nil {
-		return fmt.Errorf("failed to scrape content: %w", err)
+		return fmt.Errorf("failed to evaluate filters: %w", err)
+	}
+	if len(channels) == 0 {
+		log.Printf("Article %s filtered out for every channel of feed %s", article.GUID, feed.ID)
+		if err := b.historyRepo.SaveGUID(feed.ID, article.GUID); err != nil {
+			return fmt.Errorf("failed to save GUID: %w", err)
+		}
+		return nil
 	}
 
-	// Group channels by language preference
-	channelsByLanguage := make(map[string][]string)
+	// Skip channels that already received the article (e.g. before a restart interrupted the broadcast)
+	itemKey := articleItemKey(feed.ID, article.GUID)
+	channels = b.delivery.undelivered(itemKey, channels)
+	if len(channels) == 0 {
+		log.Printf("Article %s was already posted to every channel of feed %s", article.GUID, feed.ID)
+		if err := b.historyRepo.SaveGUID(feed.ID, article.GUID); err != nil {
+			return fmt.Errorf("failed to save GUID: %w", err)
+		}
+		return nil
+	}
+
+	log.Printf("Generating summaries for %d channel(s) subscribed to feed %s...", len(channels), feed.ID)
+
+	// Pick the text to summarize; when nothing usable is found the article is posted with its title only
+	content, source := articleContent(feed, fetcher, article)
+	log.Printf("Article %s content source: %s", article.GUID, source)
+	b.delivery.recordSource(itemKey, string(source))
+
+	// Group channels by prompt template, summary style and language preference
+	groups := newPromptGroups()
+	prompts := b.prompts.lookup(ai.PromptArticle, feed.ID)
 	guildLanguageCache := make(map[string]string) // Cache guild languages to avoid redundant lookups
+	languageCount := 0
 
 	for _, channelID := range channels {
 		// Get channel language preference
@@ -266,94 +586,326 @@ func (b *Bot) processAndPostArticle(ctx context.Context, feed *storage.RSSFeed,
 			channelLang = "" // Will fall back to guild default
 		}
 
-		// If no channel-specific language, use guild default
-		if channelLang == "" {
-			// Get guild ID from channel
+		// The guild is needed for its default language and prompt template
+		guildID := ""
+		if channelLang == "" || prompts.needsGuild() {
 			channel, err := b.session.Channel(channelID)
 			if err != nil {
 				log.Printf("WARNING: Failed to get channel info for %s: %v, using en", channelID, err)
-				channelLang = "en"
 			} else {
-				guildID := channel.GuildID
-				
+				guildID = channel.GuildID
+			}
+		}
+
+		// If no channel-specific language, use guild default
+		if channelLang == "" {
+			if guildID == "" {
+				channelLang = "en"
+			} else if cachedLang, ok := guildLanguageCache[guildID]; ok {
 				// Check cache first
-				if cachedLang, ok := guildLanguageCache[guildID]; ok {
-					channelLang = cachedLang
-				} else {
-					// Fetch and cache guild language
-					guildLang, err := b.channelRepo.GetGuildLanguage(guildID)
-					if err != nil {
-						log.Printf("WARNING: Failed to get guild language for %s: %v, using en", guildID, err)
-						guildLang = "en"
-					}
-					if guildLang == "" {
-						guildLang = "en"
-					}
-					guildLanguageCache[guildID] = guildLang
-					channelLang = guildLang
+				channelLang = cachedLang
+			} else {
+				// Fetch and cache guild language
+				guildLang, err := b.channelRepo.GetGuildLanguage(guildID)
+				if err != nil {
+					log.Printf("WARNING: Failed to get guild language for %s: %v, using en", guildID, err)
+					guildLang = "en"
+				}
+				if guildLang == "" {
+					guildLang = "en"
 				}
+				guildLanguageCache[guildID] = guildLang
+				channelLang = guildLang
 			}
 		}
 
-		log.Printf("Channel %s will receive summary in: %s", channelID, channelLang)
-		channelsByLanguage[channelLang] = append(channelsByLanguage[channelLang], channelID)
+		prompt := prompts.template(guildID)
+		style := b.channelStyle(channelID)
+		log.Printf("Channel %s will receive summary in: %s (prompt %s, style %s)", channelID, channelLang, prompt.Version, style)
+		groups.add(prompt, style, channelLang, channelID)
 	}
 
-	log.Printf("Grouped channels into %d language(s): %v", len(channelsByLanguage), getLanguageList(channelsByLanguage))
-
-	// Generate one summary per language
-	summariesByLanguage := make(map[string]*ai.SummaryResponse)
 	totalSuccessCount := 0
+	for _, group := range groups.order {
+		log.Printf("Grouped channels of prompt %s (style %s) into %d language(s): %v", group.prompt.Version, group.style, len(group.channelsByLanguage), getLanguageList(group.channelsByLanguage))
+		languageCount += len(group.channelsByLanguage)
+
+		// Generate the summaries of every language (with a single request when possible)
+		summaries := b.summarizeArticleInLanguages(ctx, feed, content, source, article, getLanguageList(group.channelsByLanguage), group.style, group.prompt)
 
-	for lang, langChannels := range channelsByLanguage {
-		log.Printf("Generating summary in %s for %d channel(s)...", lang, len(langChannels))
-		
-		response, err := b.aiSummarizer.SummarizeInLanguage(ctx, content, article.Title, lang)
+		for lang, langChannels := range group.channelsByLanguage {
+			response := summaries[lang]
+
+			// Create embed message with feed info (language-specific) and broadcast it
+			// to all channels using this language
+			totalSuccessCount += b.broadcastEmbed(itemKey, feed.ID, langChannels, b.createNewsEmbed(feed, article, response, lang), lang)
+		}
+	}
+
+	log.Printf("Article posted to %d/%d total channels across %d language(s) for feed %s (content: %s)", 
+		totalSuccessCount, len(channels), languageCount, feed.ID, source)
+
+	// Save GUID to history for this feed, whatever the content source, so the article is posted only once
+	if err := b.historyRepo.SaveGUID(feed.ID, article.GUID); err != nil {
+		return fmt.Errorf("failed to save GUID: %w", err)
+	}
+
+	return nil
+}
+
+// channelStyle returns the summary style of a channel, or the standard style when it has none
+func (b *Bot) channelStyle(channelID string) ai.SummaryStyle {
+	name, err := b.channelRepo.GetChannelStyle(channelID)
+	if err != nil {
+		log.Printf("WARNING: Failed to get summary style for channel %s: %v, using standard", channelID, err)
+		return ai.StyleStandard
+	}
+
+	style, err := ai.ParseSummaryStyle(name)
+	if err != nil {
+		log.Printf("WARNING: Ignoring summary style of channel %s: %v", channelID, err)
+		return ai.StyleStandard
+	}
+	return style
+}
+
+// summarizeArticle generates the article summary in the given language, falling back to
+// English. When there is no content or the AI fails, it returns the original title without
+// a summary, so the article is still posted with its link. Feeds with extractive summaries
+// skip the AI and get the article's key sentences in its own language.
+func (b *Bot) summarizeArticle(ctx context.Context, feed *storage.RSSFeed, content string, source contentSource, article *news.Article, lang string, style ai.SummaryStyle, promptTemplate *ai.PromptTemplate) *ai.SummaryResponse {
+	titleOnly := &ai.SummaryResponse{TranslatedTitle: article.Title}
+	if source == contentTitleOnly {
+		return titleOnly
+	}
+
+	if feed.Extractive && b.extractive != nil {
+		response, err := b.extractive.SummarizeInLanguage(ctx, content, article.Title, lang, style, promptTemplate)
 		if err != nil {
-			log.Printf("ERROR: Failed to generate summary in %s: %v", lang, err)
-			// Try fallback to English if primary language fails
-			if lang != "en" {
-				log.Printf("Attempting fallback to English for %s channels", lang)
-				response, err = b.aiSummarizer.SummarizeInLanguage(ctx, content, article.Title, "en")
-				if err != nil {
-					log.Printf("ERROR: English fallback also failed: %v", err)
-					continue
-				}
-				log.Printf("Successfully generated English fallback summary")
-			} else {
-				continue
-			}
+			log.Printf("ERROR: Failed to generate extractive summary of %s: %v", article.GUID, err)
+			return titleOnly
 		}
+		return response
+	}
 
-		summariesByLanguage[lang] = response
+	response, err := b.summarizeInLanguage(ctx, feed.ID, content, article, lang, style, promptTemplate)
+	if err == nil {
 		log.Printf("Summary generated in %s: %s", lang, response.TranslatedTitle)
+		return response
+	}
+	log.Printf("ERROR: Failed to generate summary in %s: %v", lang, err)
 
-		// Create embed message with feed info (language-specific)
-		embed := b.createNewsEmbed(feed, article, response, lang)
+	// Try fallback to English if primary language fails
+	if lang != "en" {
+		log.Printf("Attempting fallback to English for %s channels", lang)
+		response, err = b.summarizeInLanguage(ctx, feed.ID, content, article, "en", style, promptTemplate)
+		if err == nil {
+			log.Printf("Successfully generated English fallback summary")
+			return response
+		}
+		log.Printf("ERROR: English fallback also failed: %v", err)
+	}
+
+	log.Printf("Posting article %s in %s without a summary", article.GUID, lang)
+	return titleOnly
+}
 
-		// Broadcast to all channels using this language
-		successCount := 0
-		for _, channelID := range langChannels {
-			if err := b.sendEmbed(channelID, embed); err != nil {
-				log.Printf("Error sending to channel %s: %v", channelID, err)
+// summarizeArticleInLanguages generates the article summary in every language. When the AI
+// supports it, the languages without a cached summary are generated with a single request;
+// languages missing from its response fall back to summarizeArticle.
+func (b *Bot) summarizeArticleInLanguages(ctx context.Context, feed *storage.RSSFeed, content string, source contentSource, article *news.Article, languages []string, style ai.SummaryStyle, promptTemplate *ai.PromptTemplate) map[string]*ai.SummaryResponse {
+	summaries := make(map[string]*ai.SummaryResponse, len(languages))
+
+	multi, ok := b.aiSummarizer.(ai.MultiLanguageSummarizer)
+	if ok && source != contentTitleOnly && !feed.Extractive {
+		var missing []string
+		for _, lang := range languages {
+			if cached := b.cachedSummary(feed.ID, article.GUID, lang, style, promptTemplate); cached != nil {
+				summaries[lang] = cached
 			} else {
-				successCount++
+				missing = append(missing, lang)
 			}
 		}
 
-		log.Printf("Article in %s posted to %d/%d channels", lang, successCount, len(langChannels))
-		totalSuccessCount += successCount
+		if len(missing) > 1 {
+			log.Printf("Generating summary in %d languages with one request: %v", len(missing), missing)
+			responses, err := multi.SummarizeInLanguages(ctx, content, article.Title, missing, style, promptTemplate)
+			if err != nil {
+				log.Printf("ERROR: Failed to generate summaries in %v: %v", missing, err)
+			}
+			for lang, response := range responses {
+				summaries[lang] = response
+				b.cacheSummary(feed.ID, article.GUID, lang, style, promptTemplate, response)
+			}
+		}
 	}
 
-	log.Printf("Article posted to %d/%d total channels across %d language(s) for feed %s", 
-		totalSuccessCount, len(channels), len(summariesByLanguage), feed.ID)
+	for _, lang := range languages {
+		if summaries[lang] == nil {
+			summaries[lang] = b.summarizeArticle(ctx, feed, content, source, article, lang, style, promptTemplate)
+		}
+	}
+	return summaries
+}
 
-	// Save GUID to history for this feed
-	if err := b.historyRepo.SaveGUID(feed.ID, article.GUID); err != nil {
-		return fmt.Errorf("failed to save GUID: %w", err)
+// summarizeInLanguage returns the cached summary of the article in the language, generating
+// and caching it when there is none
+func (b *Bot) summarizeInLanguage(ctx context.Context, feedID string, content string, article *news.Article, lang string, style ai.SummaryStyle, promptTemplate *ai.PromptTemplate) (*ai.SummaryResponse, error) {
+	if cached := b.cachedSummary(feedID, article.GUID, lang, style, promptTemplate); cached != nil {
+		return cached, nil
 	}
 
-	return nil
+	log.Printf("Generating summary in %s...", lang)
+	response, err := b.aiSummarizer.SummarizeInLanguage(ctx, content, article.Title, lang, style, promptTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	b.cacheSummary(feedID, article.GUID, lang, style, promptTemplate, response)
+	return response, nil
+}
+
+// cachedSummary returns the cached summary of the article in the language, or nil. Summaries
+// are cached per summary style and prompt template version.
+func (b *Bot) cachedSummary(feedID, guid, lang string, style ai.SummaryStyle, promptTemplate *ai.PromptTemplate) *ai.SummaryResponse {
+	if b.summaryCache == nil {
+		return nil
+	}
+
+	cached, err := b.summaryCache.GetSummary(feedID, guid, lang, string(style), promptTemplate.Version)
+	if err != nil {
+		log.Printf("WARNING: Failed to read cached %s summary of %s: %v", lang, guid, err)
+		return nil
+	}
+	if cached != nil {
+		log.Printf("Using cached %s summary of %s", lang, guid)
+	}
+	return cached
+}
+
+// cacheSummary caches the summary of the article in the language
+func (b *Bot) cacheSummary(feedID, guid, lang string, style ai.SummaryStyle, promptTemplate *ai.PromptTemplate, response *ai.SummaryResponse) {
+	if b.summaryCache == nil {
+		return
+	}
+
+	if err := b.summaryCache.SaveSummary(feedID, guid, lang, string(style), promptTemplate.Version, response); err != nil {
+		log.Printf("WARNING: Failed to cache %s summary of %s: %v", lang, guid, err)
+	}
+}
+
+// broadcastEmbed sends the embed to every channel of a language group and returns how many succeeded.
+// Failed sends are queued for retry, so saving the GUID afterwards doesn't lose them.
+func (b *Bot) broadcastEmbed(itemKey, feedID string, channels []string, embed *discordgo.MessageEmbed, language string) int {
+	successCount := 0
+	for _, channelID := range channels {
+		if err := b.delivery.deliver(itemKey, feedID, channelID, embed); err == nil {
+			successCount++
+		}
+	}
+
+	log.Printf("Article in %s posted to %d/%d channels", language, successCount, len(channels))
+	return successCount
+}
+
+// articleItemKey identifies an article in delivery records
+func articleItemKey(feedID, guid string) string {
+	return deliveryKindRSS + ":" + feedID + ":" + guid
+}
+
+// unsubscribeChannel removes a channel from every feed it is subscribed to and returns the feed IDs
+func (b *Bot) unsubscribeChannel(channelID string) ([]string, error) {
+	feedIDs, err := b.channelRepo.GetChannelFeeds(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel feeds: %w", err)
+	}
+	for _, feedID := range feedIDs {
+		if err := b.channelRepo.RemoveChannel(channelID, feedID); err != nil {
+			return nil, fmt.Errorf("failed to remove channel from feed %s: %w", feedID, err)
+		}
+	}
+	return feedIDs, nil
+}
+
+// contentSource describes where the summarized text of an article came from
+type contentSource string
+
+const (
+	contentFromPage  contentSource = "scraped page"
+	contentFromFeed  contentSource = "feed content"
+	contentTitleOnly contentSource = "title only"
+)
+
+// articleContent returns the text to summarize and where it came from, falling back through:
+// feed-provided content when it is long enough, the scraped article page (unless scraping is
+// disabled for the feed), whatever content or description the feed provides, and finally
+// nothing, in which case the article is posted with its title only.
+func articleContent(feed *storage.RSSFeed, fetcher news.NewsFetcher, article *news.Article) (string, contentSource) {
+	if utf8.RuneCountInString(article.Content) >= minFeedContentLength {
+		return article.Content, contentFromFeed
+	}
+
+	if !feed.SkipScrape {
+		content, err := fetcher.ScrapeArticleContent(article.Link)
+		if err == nil {
+			return content, contentFromPage
+		}
+		log.Printf("WARNING: Failed to scrape article %s, falling back to feed content: %v", article.Link, err)
+	}
+
+	if article.Content != "" {
+		return article.Content, contentFromFeed
+	}
+	if description := news.HTMLToText(article.Description); description != "" {
+		return description, contentFromFeed
+	}
+	return "", contentTitleOnly
+}
+
+// feedAllows reports whether the article passes the feed-level filters
+func (b *Bot) feedAllows(feedID string, article *news.Article) (bool, error) {
+	rules, err := b.feedRepo.GetFilters(feedID, "")
+	if err != nil {
+		return false, fmt.Errorf("failed to get feed filters: %w", err)
+	}
+	return filter.Allows(rules, articleFilterItem(article)), nil
+}
+
+// acceptingChannels returns the channels whose subscription filters accept the article,
+// or none when the feed-level filters already drop it
+func (b *Bot) acceptingChannels(feedID string, article *news.Article, channels []string) ([]string, error) {
+	allowed, err := b.feedAllows(feedID, article)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, nil
+	}
+
+	item := articleFilterItem(article)
+	accepted := make([]string, 0, len(channels))
+	for _, channelID := range channels {
+		rules, err := b.feedRepo.GetFilters(feedID, channelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get filters for channel %s: %w", channelID, err)
+		}
+		if filter.Allows(rules, item) {
+			accepted = append(accepted, channelID)
+		} else {
+			log.Printf("Article %s filtered out for channel %s", article.GUID, channelID)
+		}
+	}
+
+	return accepted, nil
+}
+
+// articleFilterItem returns the article fields that filter rules are evaluated against
+func articleFilterItem(article *news.Article) filter.Item {
+	return filter.Item{
+		Title:       article.Title,
+		Description: article.Description,
+		Categories:  article.Categories,
+	}
 }
 
 // getLanguageList returns a list of language codes from the channelsByLanguage map
@@ -403,6 +955,9 @@ func (b *Bot) createNewsEmbed(feed *storage.RSSFeed, article *news.Article, resp
 	if feed.Description != "" {
 		footerText = fmt.Sprintf("%s • %s", feed.Title, feed.Description)
 	}
+	if len(response.Tags) > 0 {
+		footerText += " • #" + strings.Join(response.Tags, " #")
+	}
 	
 	// Build embed fields
 	fields := []*discordgo.MessageEmbedField{}
@@ -416,6 +971,22 @@ func (b *Bot) createNewsEmbed(feed *storage.RSSFeed, article *news.Article, resp
 		})
 	}
 	
+	// Key points and breaking changes extracted by the AI
+	if len(response.KeyPoints) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   localized(keyPointsLabel, language),
+			Value:  ai.TruncateString("• "+strings.Join(response.KeyPoints, "\n• "), maxEmbedFieldLength-3),
+			Inline: false,
+		})
+	}
+	if response.BreakingChange {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   localized(breakingChangeLabel, language),
+			Value:  localized(breakingChangeNote, language),
+			Inline: false,
+		})
+	}
+	
 	// Add article link
 	fields = append(fields, &discordgo.MessageEmbedField{
 		Name:   "🔗",
@@ -436,6 +1007,45 @@ func (b *Bot) createNewsEmbed(feed *storage.RSSFeed, article *news.Article, resp
 	}
 }
 
+// maxEmbedFieldLength is Discord's limit for embed field values
+const maxEmbedFieldLength = 1024
+
+// Translations of the structured summary labels
+var (
+	keyPointsLabel = map[string]string{
+		"pt-BR": "🔑 Pontos-chave",
+		"en":    "🔑 Key Points",
+		"es":    "🔑 Puntos clave",
+		"fr":    "🔑 Points clés",
+		"de":    "🔑 Kernpunkte",
+		"ja":    "🔑 要点",
+	}
+	breakingChangeLabel = map[string]string{
+		"pt-BR": "⚠️ Mudanças incompatíveis",
+		"en":    "⚠️ Breaking Changes",
+		"es":    "⚠️ Cambios incompatibles",
+		"fr":    "⚠️ Changements incompatibles",
+		"de":    "⚠️ Inkompatible Änderungen",
+		"ja":    "⚠️ 互換性のない変更",
+	}
+	breakingChangeNote = map[string]string{
+		"pt-BR": "Projetos existentes podem precisar de ajustes.",
+		"en":    "Existing projects may need changes.",
+		"es":    "Los proyectos existentes pueden necesitar cambios.",
+		"fr":    "Les projets existants peuvent nécessiter des modifications.",
+		"de":    "Bestehende Projekte müssen eventuell angepasst werden.",
+		"ja":    "既存のプロジェクトは変更が必要になる場合があります。",
+	}
+)
+
+// localized returns the translation for the language, falling back to English
+func localized(translations map[string]string, language string) string {
+	if text, ok := translations[language]; ok {
+		return text
+	}
+	return translations["en"]
+}
+
 // sendEmbed sends an embed message to a specific channel
 func (b *Bot) sendEmbed(channelID string, embed *discordgo.MessageEmbed) error {
 	_, err := b.session.ChannelMessageSendEmbed(channelID, embed)
@@ -0,0 +1,801 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/GustavoLR548/godot-news-bot/internal/filter"
+	"github.com/GustavoLR548/godot-news-bot/internal/news"
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bwmarrin/discordgo"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRSSHistoryRepository is a mock for testing
+type MockRSSHistoryRepository struct {
+	seen    map[string]map[string]bool // feedID -> set of GUIDs
+	pending map[string][]string
+}
+
+func NewMockRSSHistoryRepository() *MockRSSHistoryRepository {
+	return &MockRSSHistoryRepository{
+		seen:    make(map[string]map[string]bool),
+		pending: make(map[string][]string),
+	}
+}
+
+func (m *MockRSSHistoryRepository) GetLastGUID(feedID string) (string, error) { return "", nil }
+func (m *MockRSSHistoryRepository) SaveGUID(feedID, guid string) error {
+	if m.seen[feedID] == nil {
+		m.seen[feedID] = make(map[string]bool)
+	}
+	m.seen[feedID][guid] = true
+	return nil
+}
+func (m *MockRSSHistoryRepository) HasGUID(feedID, guid string) (bool, error) {
+	return m.seen[feedID][guid], nil
+}
+func (m *MockRSSHistoryRepository) AddToPending(feedID, guid string) error {
+	m.pending[feedID] = append([]string{guid}, m.pending[feedID]...)
+	return nil
+}
+// GetPending returns the oldest GUID first, like the Redis repository
+func (m *MockRSSHistoryRepository) GetPending(feedID string) ([]string, error) {
+	queue := m.pending[feedID]
+	guids := make([]string, len(queue))
+	for i, guid := range queue {
+		guids[len(queue)-1-i] = guid
+	}
+	return guids, nil
+}
+func (m *MockRSSHistoryRepository) RemoveFromPending(feedID, guid string) error {
+	var kept []string
+	for _, g := range m.pending[feedID] {
+		if g != guid {
+			kept = append(kept, g)
+		}
+	}
+	m.pending[feedID] = kept
+	return nil
+}
+func (m *MockRSSHistoryRepository) ClearPending(feedID string) (int, error) {
+	cleared := len(m.pending[feedID])
+	delete(m.pending, feedID)
+	return cleared, nil
+}
+func (m *MockRSSHistoryRepository) IsPending(feedID, guid string) (bool, error) {
+	return false, nil
+}
+
+// MockScraper is a NewsFetcher that only scrapes, recording the pages it was asked for
+type MockScraper struct {
+	content string
+	err     error
+	scraped []string
+}
+
+func (m *MockScraper) FetchLatestArticle() (*news.Article, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *MockScraper) FetchArticles() ([]news.Article, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (m *MockScraper) ScrapeArticleContent(url string) (string, error) {
+	m.scraped = append(m.scraped, url)
+	return m.content, m.err
+}
+
+func TestBot_UnseenArticles(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		seen     []string
+		articles []news.Article
+		expected []string
+	}{
+		{
+			name: "all new articles are returned oldest first",
+			articles: []news.Article{
+				{GUID: "c", PublishDate: base.Add(2 * time.Hour)},
+				{GUID: "b", PublishDate: base.Add(1 * time.Hour)},
+				{GUID: "a", PublishDate: base},
+			},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name: "articles already in history are skipped",
+			seen: []string{"a", "c"},
+			articles: []news.Article{
+				{GUID: "d", PublishDate: base.Add(3 * time.Hour)},
+				{GUID: "c", PublishDate: base.Add(2 * time.Hour)},
+				{GUID: "b", PublishDate: base.Add(1 * time.Hour)},
+				{GUID: "a", PublishDate: base},
+			},
+			expected: []string{"b", "d"},
+		},
+		{
+			name: "identical dates keep reverse feed order",
+			articles: []news.Article{
+				{GUID: "newest", PublishDate: base},
+				{GUID: "middle", PublishDate: base},
+				{GUID: "oldest", PublishDate: base},
+			},
+			expected: []string{"oldest", "middle", "newest"},
+		},
+		{
+			name:     "nothing new",
+			seen:     []string{"a"},
+			articles: []news.Article{{GUID: "a", PublishDate: base}},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := NewMockRSSHistoryRepository()
+			for _, guid := range tt.seen {
+				require.NoError(t, history.SaveGUID("feed", guid))
+			}
+			b := &Bot{historyRepo: history, maxArticlesPerCheck: defaultMaxArticlesPerCheck}
+
+			unseen, err := b.unseenArticles("feed", tt.articles)
+			require.NoError(t, err)
+
+			guids := make([]string, 0, len(unseen))
+			for _, article := range unseen {
+				guids = append(guids, article.GUID)
+			}
+			assert.Equal(t, tt.expected, guids)
+		})
+	}
+}
+
+func TestBot_SetMaxArticlesPerCheck(t *testing.T) {
+	b := &Bot{maxArticlesPerCheck: defaultMaxArticlesPerCheck}
+
+	b.SetMaxArticlesPerCheck(10)
+	assert.Equal(t, 10, b.maxArticlesPerCheck)
+
+	// Non-positive values are ignored
+	b.SetMaxArticlesPerCheck(0)
+	assert.Equal(t, 10, b.maxArticlesPerCheck)
+}
+
+func TestBot_AcceptingChannels(t *testing.T) {
+	feedRepo := NewMockRSSFeedRepository()
+	b := &Bot{feedRepo: feedRepo}
+
+	release := &news.Article{GUID: "1", Title: "Godot 4.3 released", Categories: []string{"Release"}}
+	showcase := &news.Article{GUID: "2", Title: "Showcase: Cassette Beasts", Categories: []string{"Showcase"}}
+	channels := []string{"ch1", "ch2"}
+
+	// No filters: every channel receives everything
+	accepted, err := b.acceptingChannels("feed", showcase, channels)
+	require.NoError(t, err)
+	assert.Equal(t, channels, accepted)
+
+	// Subscription filter on ch2 only keeps releases
+	require.NoError(t, feedRepo.AddFilter("feed", "ch2", filter.Rule{Action: filter.ActionInclude, Type: filter.TypeCategory, Value: "release"}))
+
+	accepted, err = b.acceptingChannels("feed", showcase, channels)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch1"}, accepted)
+
+	accepted, err = b.acceptingChannels("feed", release, channels)
+	require.NoError(t, err)
+	assert.Equal(t, channels, accepted)
+
+	// Feed filter drops the article for everyone
+	require.NoError(t, feedRepo.AddFilter("feed", "", filter.Rule{Action: filter.ActionExclude, Type: filter.TypeKeyword, Value: "godot 4.3"}))
+
+	accepted, err = b.acceptingChannels("feed", release, channels)
+	require.NoError(t, err)
+	assert.Empty(t, accepted)
+
+	allowed, err := b.feedAllows("feed", release)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestFeedPool_PreventsConcurrentRunsOfSameFeed(t *testing.T) {
+	pool := newFeedPool(2)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- pool.run("feed", func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	// The same feed is busy, another feed still gets a worker
+	assert.ErrorIs(t, pool.run("feed", func() error { return nil }), errFeedBusy)
+	assert.NoError(t, pool.run("other", func() error { return nil }))
+
+	close(release)
+	require.NoError(t, <-done)
+
+	// Once finished the feed can run again
+	assert.NoError(t, pool.run("feed", func() error { return nil }))
+}
+
+func TestFeedPool_StopWaitsForRuns(t *testing.T) {
+	pool := newFeedPool(2)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := false
+	go func() {
+		_ = pool.run("feed", func() error {
+			close(started)
+			<-release
+			finished = true
+			return nil
+		})
+	}()
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		pool.stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("stop returned while a feed was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+	assert.True(t, finished)
+
+	// No new runs once stopped
+	assert.ErrorIs(t, pool.run("other", func() error { return nil }), errPoolStopped)
+}
+
+// TestGitHubMonitor_StopCancelsAndWaitsForRuns tests that Stop cancels manual repository runs and waits for them
+func TestGitHubMonitor_StopCancelsAndWaitsForRuns(t *testing.T) {
+	m := NewGitHubMonitor(nil, nil, nil, nil)
+	m.SetLeaseManager(nil)
+
+	started := make(chan struct{})
+	finished := false
+	m.goRun(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		finished = true
+	})
+	<-started
+
+	m.Stop()
+	assert.True(t, finished, "Stop returned while a repository was running")
+
+	// No new runs once stopped
+	ran := false
+	m.goRun(func(ctx context.Context) { ran = true })
+	m.Stop()
+	assert.False(t, ran)
+}
+
+func TestFeedPool_BoundsConcurrency(t *testing.T) {
+	pool := newFeedPool(2)
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			_ = pool.run(id, func() error {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+
+				time.Sleep(20 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return nil
+			})
+		}(fmt.Sprintf("feed-%d", i))
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, maxRunning)
+}
+
+func TestBot_SetFeedWorkers(t *testing.T) {
+	b := &Bot{feedPool: newFeedPool(defaultFeedWorkers)}
+
+	b.SetFeedWorkers(8)
+	assert.Equal(t, 8, b.feedPool.workers())
+
+	// Non-positive values are ignored
+	b.SetFeedWorkers(0)
+	assert.Equal(t, 8, b.feedPool.workers())
+}
+
+func TestArticleContent(t *testing.T) {
+	longContent := strings.Repeat("Feed-provided paragraph. ", 40)
+
+	tests := []struct {
+		name           string
+		skipScrape     bool
+		article        news.Article
+		scrapeErr      error
+		expected       string
+		expectedSource contentSource
+		expectScrape   bool
+	}{
+		{
+			name:           "long feed content is used without scraping",
+			article:        news.Article{Link: "https://example.com/a", Content: longContent},
+			expected:       longContent,
+			expectedSource: contentFromFeed,
+		},
+		{
+			name:           "short feed content falls back to scraping",
+			article:        news.Article{Link: "https://example.com/a", Content: "Teaser"},
+			expected:       "Scraped page",
+			expectedSource: contentFromPage,
+			expectScrape:   true,
+		},
+		{
+			name:           "scrape failure falls back to feed content",
+			article:        news.Article{Link: "https://example.com/a", Content: "Teaser", Description: "Description"},
+			scrapeErr:      fmt.Errorf("403 forbidden"),
+			expected:       "Teaser",
+			expectedSource: contentFromFeed,
+			expectScrape:   true,
+		},
+		{
+			name:           "scrape failure falls back to the description as text",
+			article:        news.Article{Link: "https://example.com/a", Description: "<p>Short <b>description</b></p>"},
+			scrapeErr:      fmt.Errorf("403 forbidden"),
+			expected:       "Short description",
+			expectedSource: contentFromFeed,
+			expectScrape:   true,
+		},
+		{
+			name:           "scrape failure without feed content posts the title only",
+			article:        news.Article{Link: "https://example.com/a"},
+			scrapeErr:      fmt.Errorf("paywall"),
+			expectedSource: contentTitleOnly,
+			expectScrape:   true,
+		},
+		{
+			name:           "skip-scrape uses short feed content",
+			skipScrape:     true,
+			article:        news.Article{Link: "https://example.com/a", Content: "Teaser", Description: "Description"},
+			expected:       "Teaser",
+			expectedSource: contentFromFeed,
+		},
+		{
+			name:           "skip-scrape falls back to the description",
+			skipScrape:     true,
+			article:        news.Article{Link: "https://example.com/a", Description: "Description"},
+			expected:       "Description",
+			expectedSource: contentFromFeed,
+		},
+		{
+			name:           "skip-scrape without any content posts the title only",
+			skipScrape:     true,
+			article:        news.Article{Link: "https://example.com/a"},
+			expectedSource: contentTitleOnly,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraper := &MockScraper{content: "Scraped page", err: tt.scrapeErr}
+			feed := &storage.RSSFeed{ID: "feed", SkipScrape: tt.skipScrape}
+
+			content, source := articleContent(feed, scraper, &tt.article)
+			assert.Equal(t, tt.expected, content)
+			assert.Equal(t, tt.expectedSource, source)
+			assert.Equal(t, tt.expectScrape, len(scraper.scraped) > 0)
+		})
+	}
+}
+
+// articlePrompt is the built-in article prompt template the summary tests use
+var articlePrompt = ai.DefaultPromptTemplate(ai.PromptArticle)
+
+// MockAISummarizer returns canned summaries, failing for the configured languages
+type MockAISummarizer struct {
+	failing map[string]bool
+	calls   []string
+}
+
+func (m *MockAISummarizer) Summarize(ctx context.Context, text string, originalTitle string) (*ai.SummaryResponse, error) {
+	return m.SummarizeInLanguage(ctx, text, originalTitle, "pt-BR", ai.StyleStandard, nil)
+}
+
+func (m *MockAISummarizer) SummarizeInLanguage(ctx context.Context, text string, originalTitle string, languageCode string, style ai.SummaryStyle, promptTemplate *ai.PromptTemplate) (*ai.SummaryResponse, error) {
+	m.calls = append(m.calls, languageCode)
+	if m.failing[languageCode] {
+		return nil, fmt.Errorf("summarization failed")
+	}
+	return &ai.SummaryResponse{TranslatedTitle: originalTitle + " (" + languageCode + ")", Summary: "Summary"}, nil
+}
+
+func TestBot_SummarizeArticle(t *testing.T) {
+	godot := &storage.RSSFeed{ID: "godot"}
+	article := &news.Article{GUID: "a", Title: "Godot 4.4 released"}
+
+	tests := []struct {
+		name          string
+		source        contentSource
+		failing       map[string]bool
+		lang          string
+		expectedTitle string
+		expectSummary bool
+		expectedCalls []string
+	}{
+		{
+			name:          "summary in the requested language",
+			source:        contentFromPage,
+			lang:          "es",
+			expectedTitle: "Godot 4.4 released (es)",
+			expectSummary: true,
+			expectedCalls: []string{"es"},
+		},
+		{
+			name:          "falls back to English",
+			source:        contentFromFeed,
+			failing:       map[string]bool{"es": true},
+			lang:          "es",
+			expectedTitle: "Godot 4.4 released (en)",
+			expectSummary: true,
+			expectedCalls: []string{"es", "en"},
+		},
+		{
+			name:          "AI failure posts the title only",
+			source:        contentFromFeed,
+			failing:       map[string]bool{"es": true, "en": true},
+			lang:          "es",
+			expectedTitle: "Godot 4.4 released",
+			expectedCalls: []string{"es", "en"},
+		},
+		{
+			name:          "no content skips the AI",
+			source:        contentTitleOnly,
+			lang:          "es",
+			expectedTitle: "Godot 4.4 released",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summarizer := &MockAISummarizer{failing: tt.failing}
+			b := &Bot{aiSummarizer: summarizer}
+
+			response := b.summarizeArticle(context.Background(), godot, "content", tt.source, article, tt.lang, ai.StyleStandard, articlePrompt)
+			assert.Equal(t, tt.expectedTitle, response.TranslatedTitle)
+			assert.Equal(t, tt.expectSummary, response.Summary != "")
+			assert.Equal(t, tt.expectedCalls, summarizer.calls)
+		})
+	}
+}
+
+func TestBot_SummarizeArticle_UsesCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	godot := &storage.RSSFeed{ID: "godot"}
+	article := &news.Article{GUID: "a", Title: "Godot 4.4 released"}
+	summarizer := &MockAISummarizer{failing: map[string]bool{"ja": true}}
+	b := &Bot{aiSummarizer: summarizer}
+	b.SetSummaryCache(storage.NewRedisSummaryCache(client))
+
+	// The first post generates and caches the summary
+	first := b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "es", ai.StyleStandard, articlePrompt)
+	assert.Equal(t, "Godot 4.4 released (es)", first.TranslatedTitle)
+
+	// Re-posting the article reuses it
+	again := b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "es", ai.StyleStandard, articlePrompt)
+	assert.Equal(t, first, again)
+	assert.Equal(t, []string{"es"}, summarizer.calls)
+
+	// The English fallback is cached as the English summary, the failed language is retried
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "ja", ai.StyleStandard, articlePrompt)
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "ja", ai.StyleStandard, articlePrompt)
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "en", ai.StyleStandard, articlePrompt)
+	assert.Equal(t, []string{"es", "ja", "en", "ja"}, summarizer.calls)
+
+	// Other feeds don't share the article's summaries
+	b.summarizeArticle(context.Background(), &storage.RSSFeed{ID: "gdquest"}, "content", contentFromPage, article, "es", ai.StyleStandard, articlePrompt)
+	assert.Equal(t, []string{"es", "ja", "en", "ja", "es"}, summarizer.calls)
+}
+
+func TestBot_CreateNewsEmbed_StructuredSummary(t *testing.T) {
+	feed := &storage.RSSFeed{ID: "godot", Title: "Godot Engine"}
+	article := &news.Article{GUID: "a", Title: "Godot 4.4 released", Link: "https://godotengine.org/article/godot-4-4"}
+	b := &Bot{}
+
+	response := &ai.SummaryResponse{
+		TranslatedTitle: "Godot 4.4 lançado",
+		Summary:         "Resumo.",
+		KeyPoints:       []string{"Dicionários tipados", "Jolt"},
+		Tags:            []string{"release", "physics"},
+		BreakingChange:  true,
+	}
+	embed := b.createNewsEmbed(feed, article, response, "pt-BR")
+
+	assert.Equal(t, "Godot Engine • #release #physics", embed.Footer.Text)
+	require.Len(t, embed.Fields, 4)
+	assert.Equal(t, "📰 Título Original", embed.Fields[0].Name)
+	assert.Equal(t, "🔑 Pontos-chave", embed.Fields[1].Name)
+	assert.Equal(t, "• Dicionários tipados\n• Jolt", embed.Fields[1].Value)
+	assert.Equal(t, "⚠️ Mudanças incompatíveis", embed.Fields[2].Name)
+	assert.Equal(t, "🔗", embed.Fields[3].Name)
+
+	// Plain summaries (e.g. extractive ones) only get the link
+	embed = b.createNewsEmbed(feed, article, &ai.SummaryResponse{TranslatedTitle: article.Title, Summary: "Summary."}, "en")
+	assert.Equal(t, "Godot Engine", embed.Footer.Text)
+	require.Len(t, embed.Fields, 1)
+	assert.Equal(t, "🔗", embed.Fields[0].Name)
+}
+
+func TestBot_SummarizeArticle_Extractive(t *testing.T) {
+	feed := &storage.RSSFeed{ID: "godot", Extractive: true}
+	article := &news.Article{GUID: "a", Title: "Godot 4.4 released"}
+	content := "Godot 4.4 brings typed dictionaries to GDScript. " +
+		"Typed dictionaries make GDScript code safer and faster to write. " +
+		"The editor now previews dictionaries with their key and value types."
+
+	summarizer := &MockAISummarizer{}
+	b := &Bot{aiSummarizer: summarizer, extractive: ai.NewExtractiveSummarizer()}
+
+	response := b.summarizeArticle(context.Background(), feed, content, contentFromPage, article, "es", ai.StyleStandard, articlePrompt)
+	assert.Equal(t, "Godot 4.4 released", response.TranslatedTitle)
+	assert.Contains(t, response.Summary, "typed dictionaries")
+	assert.Empty(t, summarizer.calls)
+}
+
+// MockMultiLanguageSummarizer generates every language in one call, omitting the configured ones
+type MockMultiLanguageSummarizer struct {
+	MockAISummarizer
+	omitted    map[string]bool
+	multiCalls [][]string
+}
+
+func (m *MockMultiLanguageSummarizer) SummarizeInLanguages(ctx context.Context, text string, originalTitle string, languageCodes []string, style ai.SummaryStyle, promptTemplate *ai.PromptTemplate) (map[string]*ai.SummaryResponse, error) {
+	m.multiCalls = append(m.multiCalls, languageCodes)
+	responses := make(map[string]*ai.SummaryResponse)
+	for _, code := range languageCodes {
+		if !m.omitted[code] {
+			responses[code] = &ai.SummaryResponse{TranslatedTitle: originalTitle + " [" + code + "]", Summary: "Summary"}
+		}
+	}
+	return responses, nil
+}
+
+func TestBot_SummarizeArticleInLanguages(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	godot := &storage.RSSFeed{ID: "godot"}
+	article := &news.Article{GUID: "a", Title: "Godot 4.4 released"}
+	summarizer := &MockMultiLanguageSummarizer{omitted: map[string]bool{"ja": true}}
+	b := &Bot{aiSummarizer: summarizer}
+	b.SetSummaryCache(storage.NewRedisSummaryCache(client))
+
+	// The cached language is reused, the others are generated together, the omitted one on its own
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "fr", ai.StyleStandard, articlePrompt)
+	summaries := b.summarizeArticleInLanguages(context.Background(), godot, "content", contentFromPage, article, []string{"es", "fr", "ja", "pt-BR"}, ai.StyleStandard, articlePrompt)
+
+	assert.Equal(t, [][]string{{"es", "ja", "pt-BR"}}, summarizer.multiCalls)
+	assert.Equal(t, []string{"fr", "ja"}, summarizer.calls)
+	assert.Equal(t, "Godot 4.4 released [es]", summaries["es"].TranslatedTitle)
+	assert.Equal(t, "Godot 4.4 released (fr)", summaries["fr"].TranslatedTitle)
+	assert.Equal(t, "Godot 4.4 released (ja)", summaries["ja"].TranslatedTitle)
+	assert.Equal(t, "Godot 4.4 released [pt-BR]", summaries["pt-BR"].TranslatedTitle)
+
+	// The generated summaries were cached
+	b.summarizeArticleInLanguages(context.Background(), godot, "content", contentFromPage, article, []string{"es", "fr", "ja", "pt-BR"}, ai.StyleStandard, articlePrompt)
+	assert.Len(t, summarizer.multiCalls, 1)
+	assert.Equal(t, []string{"fr", "ja"}, summarizer.calls)
+}
+
+func TestPromptResolver(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	repo := storage.NewRedisPromptRepository(client)
+	require.NoError(t, repo.SetPromptTemplate(storage.PromptScopeFeed, "godot", ai.PromptArticle, "Feed: {{.Content}}"))
+	require.NoError(t, repo.SetPromptTemplate(storage.PromptScopeGuild, "guild-1", ai.PromptArticle, "Guild: {{.Content}}"))
+	require.NoError(t, repo.SetPromptTemplate(storage.PromptScopeGuild, "guild-2", ai.PromptArticle, "{{.Invalid}}"))
+	configured, err := ai.ParsePromptTemplate(ai.PromptArticle, "Configured: {{.Content}}")
+	require.NoError(t, err)
+
+	resolver := &promptResolver{repo: repo, configured: map[ai.PromptKind]*ai.PromptTemplate{ai.PromptArticle: configured}}
+
+	// The feed's template applies to every guild
+	feedLookup := resolver.lookup(ai.PromptArticle, "godot")
+	assert.False(t, feedLookup.needsGuild())
+	assert.Equal(t, "Feed: {{.Content}}", feedLookup.template("guild-1").Text)
+
+	// Otherwise the guild's, then the configured template (also for invalid stored ones)
+	lookup := resolver.lookup(ai.PromptArticle, "gdquest")
+	assert.True(t, lookup.needsGuild())
+	assert.Equal(t, "Guild: {{.Content}}", lookup.template("guild-1").Text)
+	assert.Same(t, configured, lookup.template("guild-2"))
+	assert.Same(t, configured, lookup.template(""))
+
+	// Without configured templates the built-in ones apply
+	assert.Same(t, ai.DefaultPromptTemplate(ai.PromptPRBatch), (&promptResolver{}).lookup(ai.PromptPRBatch, "").template("guild-1"))
+}
+
+func TestBot_SummarizeArticle_CachesPerPromptVersion(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	godot := &storage.RSSFeed{ID: "godot"}
+	article := &news.Article{GUID: "a", Title: "Godot 4.4 released"}
+	summarizer := &MockAISummarizer{}
+	b := &Bot{aiSummarizer: summarizer}
+	b.SetSummaryCache(storage.NewRedisSummaryCache(client))
+
+	custom, err := ai.ParsePromptTemplate(ai.PromptArticle, "TL;DR: {{.Content}}")
+	require.NoError(t, err)
+
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "es", ai.StyleStandard, articlePrompt)
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "es", ai.StyleStandard, custom)
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "es", ai.StyleStandard, custom)
+	assert.Equal(t, []string{"es", "es"}, summarizer.calls)
+
+	// Each summary style is cached separately
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "es", ai.StyleTLDR, custom)
+	b.summarizeArticle(context.Background(), godot, "content", contentFromPage, article, "es", ai.StyleTLDR, custom)
+	assert.Equal(t, []string{"es", "es", "es"}, summarizer.calls)
+}
+
+func TestBot_ChannelStylesGroupChannels(t *testing.T) {
+	channelRepo := NewMockChannelRepository(10)
+	require.NoError(t, channelRepo.SetChannelStyle("announcements", "tldr"))
+	require.NoError(t, channelRepo.SetChannelStyle("engine-dev", "bullet"))
+	require.NoError(t, channelRepo.SetChannelStyle("broken", "haiku"))
+	b := &Bot{channelRepo: channelRepo}
+
+	assert.Equal(t, ai.StyleTLDR, b.channelStyle("announcements"))
+	assert.Equal(t, ai.StyleBullet, b.channelStyle("engine-dev"))
+	assert.Equal(t, ai.StyleStandard, b.channelStyle("general"))
+	assert.Equal(t, ai.StyleStandard, b.channelStyle("broken"))
+
+	// Channels are grouped by (prompt, style), then by language
+	prompt := ai.DefaultPromptTemplate(ai.PromptArticle)
+	groups := newPromptGroups()
+	for _, channelID := range []string{"announcements", "general", "engine-dev", "broken"} {
+		groups.add(prompt, b.channelStyle(channelID), "en", channelID)
+	}
+	groups.add(prompt, ai.StyleTLDR, "es", "anuncios")
+
+	require.Len(t, groups.order, 3)
+	assert.Equal(t, ai.StyleTLDR, groups.order[0].style)
+	assert.Equal(t, map[string][]string{"en": {"announcements"}, "es": {"anuncios"}}, groups.order[0].channelsByLanguage)
+	assert.Equal(t, map[string][]string{"en": {"general", "broken"}}, groups.order[1].channelsByLanguage)
+	assert.Equal(t, ai.StyleBullet, groups.order[2].style)
+}
+
+// TestWithoutFrozenGuilds tests that channels of frozen guilds don't receive posts
+func TestWithoutFrozenGuilds(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	guildRepo := storage.NewRedisGuildRepository(client)
+	channelRepo, err := storage.NewRedisChannelRepository(client, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch1", "godot", 0))
+	require.NoError(t, channelRepo.AddChannel("guild-2", "ch2", "godot", 0))
+	// ch3 was never subscribed through the bot, so its guild isn't recorded
+	channels := []string{"ch1", "ch2", "ch3"}
+	guildOf := channelRepo.GetChannelGuild
+
+	assert.Equal(t, channels, withoutFrozenGuilds(nil, channels, guildOf), "freezing is disabled")
+	assert.Equal(t, channels, withoutFrozenGuilds(guildRepo, channels, guildOf), "no frozen guild")
+
+	require.NoError(t, guildRepo.SetGuildFrozen("guild-1", true))
+	assert.Equal(t, []string{"ch2", "ch3"}, withoutFrozenGuilds(guildRepo, channels, guildOf))
+
+	require.NoError(t, guildRepo.SetGuildFrozen("guild-1", false))
+	assert.Equal(t, channels, withoutFrozenGuilds(guildRepo, channels, guildOf))
+}
+
+// TestBot_ProcessFeed_PostsPendingOldestFirst tests that queued articles are posted in the order they were queued
+func TestBot_ProcessFeed_PostsPendingOldestFirst(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Empty(t, r.Header.Get("If-None-Match"), "pending articles need a full fetch")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Godot</title>
+<item><guid>a3</guid><title>Third</title><link>https://example.com/3</link><description>Third article</description></item>
+<item><guid>a2</guid><title>Second</title><link>https://example.com/2</link><description>Second article</description></item>
+<item><guid>a1</guid><title>First</title><link>https://example.com/1</link><description>First article</description></item>
+</channel></rss>`)
+	}))
+	t.Cleanup(server.Close)
+
+	channelRepo := NewMockChannelRepository(0)
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch1", "godot", 0))
+	require.NoError(t, channelRepo.SetChannelLanguage("ch1", "en"))
+	historyRepo := NewMockRSSHistoryRepository()
+	for _, guid := range []string{"a1", "a2", "a3"} {
+		require.NoError(t, historyRepo.AddToPending("godot", guid))
+	}
+
+	feedRepo := NewMockRSSFeedRepository()
+	require.NoError(t, feedRepo.SetHTTPValidators("godot", `"v1"`, ""))
+
+	b := NewBot(nil, nil, &MockAISummarizer{}, channelRepo, historyRepo, feedRepo, time.Minute)
+	var posted []string
+	b.delivery.send = func(channelID string, embed *discordgo.MessageEmbed) error {
+		posted = append(posted, embed.URL)
+		return nil
+	}
+
+	feed := &storage.RSSFeed{ID: "godot", URL: server.URL, SkipScrape: true}
+	require.NoError(t, b.processFeed(context.Background(), feed))
+
+	assert.Equal(t, []string{"https://example.com/1", "https://example.com/2", "https://example.com/3"}, posted)
+	assert.Equal(t, 1, requests, "the feed is fetched once for every pending article")
+	pending, err := historyRepo.GetPending("godot")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+// TestBot_ProcessFeed_NotModifiedKeepsPending tests that a 304 answer keeps pending articles queued
+func TestBot_ProcessFeed_NotModifiedKeepsPending(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, `"v1"`, r.Header.Get("If-None-Match"))
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	t.Cleanup(server.Close)
+
+	// No channel is subscribed yet, so the pending article waits without a full fetch
+	historyRepo := NewMockRSSHistoryRepository()
+	require.NoError(t, historyRepo.AddToPending("godot", "a1"))
+	feedRepo := NewMockRSSFeedRepository()
+	require.NoError(t, feedRepo.SetHTTPValidators("godot", `"v1"`, ""))
+
+	b := NewBot(nil, nil, &MockAISummarizer{}, NewMockChannelRepository(0), historyRepo, feedRepo, time.Minute)
+	feed := &storage.RSSFeed{ID: "godot", URL: server.URL, SkipScrape: true}
+	require.NoError(t, b.processFeed(context.Background(), feed))
+
+	assert.Equal(t, 1, requests)
+	pending, err := historyRepo.GetPending("godot")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1"}, pending)
+}
@@ -1,9 +1,11 @@
 package bot
 
 import (
+"fmt"
 "log"
 "time"
 
+"github.com/GustavoLR548/godot-news-bot/internal/schedule"
 "github.com/bwmarrin/discordgo"
 )
 
@@ -94,3 +96,20 @@ func (h *CommandHandler) followUpSuccess(s *discordgo.Session, i *discordgo.Inte
 		log.Printf("Error sending follow-up success: %v", err)
 	}
 }
+
+// nextRunDisplay formats the next time a schedule fires as a Discord timestamp
+func nextRunDisplay(times []string, timezone string) string {
+	specs, err := schedule.ParseAll(times)
+	if err != nil {
+		return "invalid schedule"
+	}
+	loc, err := schedule.LoadLocation(timezone)
+	if err != nil {
+		return "invalid timezone"
+	}
+	next, ok := schedule.NextRun(specs, loc, time.Now())
+	if !ok {
+		return "never"
+	}
+	return fmt.Sprintf("<t:%d:f> (<t:%d:R>)", next.Unix(), next.Unix())
+}
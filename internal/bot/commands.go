@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
 	"github.com/GustavoLR548/godot-news-bot/internal/storage"
 	"github.com/bwmarrin/discordgo"
 )
@@ -12,13 +13,23 @@ import (
 // Individual command implementations are split across:
 //   - rss_commands.go: RSS feed management commands
 //   - github_commands.go: GitHub repository commands
-//   - language_commands.go: Language configuration commands
+//   - filter_commands.go: Feed filter commands
+//   - prompt_commands.go: Prompt template commands
+//   - quota_commands.go: Per-guild quotas and the /owner quota subcommands
+//   - owner_commands.go: Bot owner administration commands (/owner)
+//   - language_commands.go: Language and summary style configuration commands
 //   - command_utils.go: Shared utility functions
 type CommandHandler struct {
 	channelRepo   storage.ChannelRepository
 	feedRepo      storage.RSSFeedRepository
 	githubRepo    storage.GitHubRepository
-	maxLimit      int
+	promptRepo    storage.PromptRepository   // custom prompt templates, nil when disabled
+	quotaRepo     storage.QuotaRepository    // per-guild quotas, nil when disabled
+	guildRepo     storage.GuildRepository    // frozen guilds and guild purges, nil when disabled
+	deliveries    storage.DeliveryRepository // queued delivery retries cleared by guild purges, nil when disabled
+	owners        map[string]bool            // bot owner user IDs, who manage catalog feeds and repos
+	ownerGuildID  string                     // home guild whose admins are bot owners too
+	maxLimit      int                        // global channel cap across all guilds (0 = no cap)
 	bot           *Bot           // Reference to bot for triggering updates
 	githubMonitor *GitHubMonitor // Reference to GitHub monitor for triggering updates
 }
@@ -43,6 +54,11 @@ func (h *CommandHandler) SetGitHubMonitor(monitor *GitHubMonitor) {
 	h.githubMonitor = monitor
 }
 
+// SetPromptRepository enables the /prompt-template command
+func (h *CommandHandler) SetPromptRepository(promptRepo storage.PromptRepository) {
+	h.promptRepo = promptRepo
+}
+
 // RegisterCommands registers all slash commands with Discord
 func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 	commands := []*discordgo.ApplicationCommand{
@@ -136,6 +152,12 @@ func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 					Description: "Feed description",
 					Required:    false,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "catalog",
+					Description: "Add the feed to the public catalog of every server (bot owners only)",
+					Required:    false,
+				},
 			},
 		},
 		{
@@ -156,7 +178,7 @@ func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 		},
 		{
 			Name:        "schedule-feed",
-			Description: "Set check times for a feed (Admin only)",
+			Description: "Set the check schedule for a feed: times, intervals or cron (Admin only)",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
@@ -167,9 +189,42 @@ func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
 					Name:        "times",
-					Description: "Check times in 24h format, comma-separated (e.g., 09:00,13:00,18:00)",
+					Description: "e.g. 09:00,18:00 | every 2h | 09:00 mon-fri | */30 9-18 * * 1-5 (separate entries with ;)",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "timezone",
+					Description: "IANA timezone for the times, e.g. America/Sao_Paulo (\"local\" for the bot's clock)",
+					Required:    false,
+				},
+			},
+		},
+		feedFilterCommand(),
+		promptTemplateCommand(),
+		ownerCommand(),
+		{
+			Name:        "feed-settings",
+			Description: "View or change the settings of a feed (Admin only)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "feed",
+					Description: "The feed identifier",
 					Required:    true,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "skip-scrape",
+					Description: "Never scrape article pages, only use the content provided by the feed",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "extractive",
+					Description: "Summarize offline by picking key sentences instead of using the AI",
+					Required:    false,
+				},
 			},
 		},
 		{
@@ -221,6 +276,33 @@ func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 				},
 			},
 		},
+		{
+			Name:        "set-channel-style",
+			Description: "Set the length and format of news summaries in a channel",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionChannel,
+					Name:        "channel",
+					Description: "The channel to configure",
+					Required:    true,
+					ChannelTypes: []discordgo.ChannelType{
+						discordgo.ChannelTypeGuildText,
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "style",
+					Description: "Select style (leave empty to use the standard summary)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "TL;DR (one line)", Value: string(ai.StyleTLDR)},
+						{Name: "Standard (3-5 sentences)", Value: string(ai.StyleStandard)},
+						{Name: "Detailed (a few paragraphs)", Value: string(ai.StyleDetailed)},
+						{Name: "Bullet points", Value: string(ai.StyleBullet)},
+					},
+				},
+			},
+		},
 		{
 			Name:        "help",
 			Description: "Show all available commands and how to use them",
@@ -254,6 +336,12 @@ func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 					Description: "Target branch to monitor (default: 'main')",
 					Required:    false,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "catalog",
+					Description: "Add the repository to the public catalog of every server (bot owners only)",
+					Required:    false,
+				},
 			},
 		},
 		{
@@ -316,7 +404,7 @@ func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 		},
 		{
 			Name:        "schedule-repo",
-			Description: "Set check times for a GitHub repository (e.g., 09:00,13:00,18:00)",
+			Description: "Set the check schedule for a GitHub repository (times, intervals or cron)",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
@@ -327,9 +415,15 @@ func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
 					Name:        "times",
-					Description: "Comma-separated check times in HH:MM format (empty to use interval)",
+					Description: "e.g. 09:00,18:00 | every 2h | 09:00 mon-fri | */30 9-18 * * 1-5 (separate entries with ;)",
 					Required:    true,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "timezone",
+					Description: "IANA timezone for the times, e.g. America/Sao_Paulo (\"local\" for the bot's clock)",
+					Required:    false,
+				},
 			},
 		},
 		{
@@ -364,6 +458,12 @@ func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
 // HandleCommands sets up the command handler routing
 func (h *CommandHandler) HandleCommands(s *discordgo.Session) {
 	s.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		// Frozen guilds can't use commands; the bot owners can still unfreeze them
+		if i.ApplicationCommandData().Name != "owner" && h.isGuildFrozen(i.GuildID) {
+			h.respondError(s, i, frozenGuildMessage)
+			return
+		}
+
 		switch i.ApplicationCommandData().Name {
 		// RSS Feed Commands (rss_commands.go)
 		case "setup-feed-channel":
@@ -382,12 +482,28 @@ func (h *CommandHandler) HandleCommands(s *discordgo.Session) {
 			h.handleListFeeds(s, i)
 		case "schedule-feed":
 			h.handleScheduleFeed(s, i)
+		case "feed-settings":
+			h.handleFeedSettings(s, i)
+
+		// Feed Filter Commands (filter_commands.go)
+		case "feed-filter":
+			h.handleFeedFilter(s, i)
+
+		// Prompt Template Commands (prompt_commands.go)
+		case "prompt-template":
+			h.handlePromptTemplate(s, i)
+
+		// Bot Owner Commands (owner_commands.go, quota_commands.go)
+		case "owner":
+			h.handleOwner(s, i)
 			
 		// Language Commands (language_commands.go)
 		case "set-language":
 			h.handleSetLanguage(s, i)
 		case "set-channel-language":
 			h.handleSetChannelLanguage(s, i)
+		case "set-channel-style":
+			h.handleSetChannelStyle(s, i)
 			
 		// View/Info Commands (commands.go)
 		case "list-channels":
@@ -425,24 +541,35 @@ func (h *CommandHandler) handleHelp(s *discordgo.Session, i *discordgo.Interacti
 		"• `/register-feed <feed-url>` - Register a new RSS feed\n" +
 		"• `/unregister-feed <feed-url>` - Unregister an existing RSS feed\n" +
 		"• `/list-feeds` - List all registered RSS feeds\n" +
-		"• `/schedule-feed <feed-url> <interval-minutes>` - Schedule automatic updates for a feed\n" +
+		"• `/schedule-feed <feed> <schedule> [timezone]` - Set check times, intervals or cron for a feed\n" +
 		"• `/update-feed [feed]` - Manually trigger update for a specific feed\n" +
-		"• `/update-all-feeds` - Manually trigger update for all feeds\n\n" +
+		"• `/update-all-feeds` - Manually trigger update for all feeds\n" +
+		"• `/feed-settings <feed> [skip-scrape] [extractive]` - View or change feed settings (e.g. disable scraping)\n" +
+		"• `/feed-filter add|remove|list|clear <feed> [channel]` - Manage include/exclude filters\n" +
+		"• `/prompt-template show|set|reset <kind> [feed]` - Customize the AI prompts of the server or a feed\n\n" +
 		"**GitHub Repository Commands:**\n" +
 		"• `/register-repo <repo-url>` - Register a GitHub repository for monitoring\n" +
 		"• `/unregister-repo <repo-url>` - Unregister a GitHub repository\n" +
 		"• `/list-repos` - List all registered GitHub repositories\n" +
 		"• `/setup-repo-channel <repo-url> <channel>` - Setup a channel for repository updates\n" +
 		"• `/remove-repo-channel <repo-url> <channel>` - Remove a channel from repository updates\n" +
-		"• `/schedule-repo <repo-url> <interval-minutes>` - Schedule automatic updates for a repository\n" +
+		"• `/schedule-repo <repo> <schedule> [timezone]` - Set check times, intervals or cron for a repository\n" +
 		"• `/update-repo <repo-url>` - Manually trigger update for a specific repository\n" +
 		"• `/update-all-repos` - Manually trigger update for all repositories\n\n" +
 		"**Language Commands:**\n" +
 		"• `/set-language <language>` - Set the server's default language\n" +
-		"• `/set-channel-language <channel> <language>` - Set a channel's language\n\n" +
+		"• `/set-channel-language <channel> <language>` - Set a channel's language\n" +
+		"• `/set-channel-style <channel> [style]` - Set a channel's summary style (tldr, standard, detailed, bullet)\n\n" +
 		"**Other Commands:**\n" +
-		"• `/list-channels` - List all registered channels and their feeds/repos\n" +
-		"• `/help` - Show this help message"
+		"• `/list-channels` - List all registered channels and their feeds/repos, and this server's quota usage\n" +
+		"• `/help` - Show this help message\n\n" +
+		"**Bot Owner Commands:**\n" +
+		"• `/owner guilds` - List the servers using the bot and their usage\n" +
+		"• `/owner ratelimit` - Show the AI rate limiting statistics\n" +
+		"• `/owner freeze|unfreeze <guild>` - Pause or resume a server's commands and posts\n" +
+		"• `/owner purge <guild> confirm:true` - Delete a server's subscriptions, feeds, repositories and settings\n" +
+		"• `/owner clear-pending [feed] [repo]` - Empty the pending queue of a feed or repository\n" +
+		"• `/owner quota show|set|reset [resource] [limit] [guild]` - View or change a server's quotas"
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
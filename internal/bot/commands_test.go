@@ -1,14 +1,19 @@
 package bot
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/GustavoLR548/godot-news-bot/internal/filter"
 	"github.com/GustavoLR548/godot-news-bot/internal/github"
 	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/bwmarrin/discordgo"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,11 +33,20 @@ func (m *MockGitHubRepository) GetRepository(repoID string) (*github.Repository,
 func (m *MockGitHubRepository) GetAllRepositories() ([]github.Repository, error) {
 	return []github.Repository{}, nil
 }
+func (m *MockGitHubRepository) GetGuildRepoCount(guildID string) (int, error) {
+	return 0, nil
+}
 func (m *MockGitHubRepository) HasRepository(repoID string) (bool, error) { return false, nil }
-func (m *MockGitHubRepository) AddRepoChannel(repoID, channelID string) error { return nil }
+func (m *MockGitHubRepository) AddRepoChannel(guildID, repoID, channelID string, maxGuildChannels int) error {
+	return nil
+}
 func (m *MockGitHubRepository) RemoveRepoChannel(repoID, channelID string) error {
 	return nil
 }
+func (m *MockGitHubRepository) GetGuildRepoChannels(guildID string) ([]string, error) {
+	return []string{}, nil
+}
+func (m *MockGitHubRepository) GetChannelGuild(channelID string) (string, error) { return "", nil }
 func (m *MockGitHubRepository) GetRepoChannels(repoID string) ([]string, error) {
 	return []string{}, nil
 }
@@ -61,6 +75,7 @@ func (m *MockGitHubRepository) GetLastChecked(repoID string) (time.Time, error)
 	return time.Time{}, nil
 }
 func (m *MockGitHubRepository) SetSchedule(repoID string, times []string) error { return nil }
+func (m *MockGitHubRepository) SetTimezone(repoID, timezone string) error        { return nil }
 func (m *MockGitHubRepository) GetSchedule(repoID string) ([]string, error) {
 	return []string{}, nil
 }
@@ -75,6 +90,9 @@ func (m *MockGitHubRepository) GetGuildLanguage(guildID string) (string, error)
 type MockChannelRepository struct {
 	mu           sync.RWMutex
 	channelFeeds map[string]map[string]bool // channelID -> set of feedIDs
+	styles       map[string]string          // channelID -> summary style
+	languages    map[string]string          // channelID -> language
+	guilds       map[string]string          // channelID -> guildID
 	maxLimit     int
 	addError     error
 	getError     error
@@ -83,11 +101,12 @@ type MockChannelRepository struct {
 func NewMockChannelRepository(maxLimit int) *MockChannelRepository {
 	return &MockChannelRepository{
 		channelFeeds: make(map[string]map[string]bool),
+		guilds:       make(map[string]string),
 		maxLimit:     maxLimit,
 	}
 }
 
-func (m *MockChannelRepository) AddChannel(channelID, feedID string) error {
+func (m *MockChannelRepository) AddChannel(guildID, channelID, feedID string, maxGuildChannels int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
@@ -95,15 +114,27 @@ func (m *MockChannelRepository) AddChannel(channelID, feedID string) error {
 		return m.addError
 	}
 	if m.channelFeeds[channelID] == nil {
-		if len(m.channelFeeds) >= m.maxLimit {
+		if m.maxLimit > 0 && len(m.channelFeeds) >= m.maxLimit {
 			return fmt.Errorf("channel limit reached (%d/%d)", len(m.channelFeeds), m.maxLimit)
 		}
+		if maxGuildChannels > 0 {
+			used := 0
+			for _, g := range m.guilds {
+				if g == guildID {
+					used++
+				}
+			}
+			if used >= maxGuildChannels {
+				return &storage.QuotaExceededError{Resource: storage.QuotaChannels, Used: used, Limit: maxGuildChannels}
+			}
+		}
 		m.channelFeeds[channelID] = make(map[string]bool)
 	}
 	if m.channelFeeds[channelID][feedID] {
 		return fmt.Errorf("channel %s already subscribed to feed %s", channelID, feedID)
 	}
 	m.channelFeeds[channelID][feedID] = true
+	m.guilds[channelID] = guildID
 	return nil
 }
 
@@ -115,6 +146,7 @@ func (m *MockChannelRepository) RemoveChannel(channelID, feedID string) error {
 		delete(m.channelFeeds[channelID], feedID)
 		if len(m.channelFeeds[channelID]) == 0 {
 			delete(m.channelFeeds, channelID)
+			delete(m.guilds, channelID)
 		}
 	}
 	return nil
@@ -144,6 +176,48 @@ func (m *MockChannelRepository) GetChannelCount() (int, error) {
 	return len(m.channelFeeds), nil
 }
 
+func (m *MockChannelRepository) GetGuildChannelCount(guildID string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.getError != nil {
+		return 0, m.getError
+	}
+	count := 0
+	for _, g := range m.guilds {
+		if g == guildID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockChannelRepository) GetGuildChannels(guildID string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.getError != nil {
+		return nil, m.getError
+	}
+	var channels []string
+	for ch, g := range m.guilds {
+		if g == guildID {
+			channels = append(channels, ch)
+		}
+	}
+	return channels, nil
+}
+
+func (m *MockChannelRepository) GetChannelGuild(channelID string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.getError != nil {
+		return "", m.getError
+	}
+	return m.guilds[channelID], nil
+}
+
 func (m *MockChannelRepository) HasChannel(channelID string) (bool, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -187,13 +261,44 @@ func (m *MockChannelRepository) GetFeedChannels(feedID string) ([]string, error)
 }
 
 func (m *MockChannelRepository) SetChannelLanguage(channelID, languageCode string) error {
-	// Mock implementation - just return nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.languages == nil {
+		m.languages = make(map[string]string)
+	}
+	if languageCode == "" {
+		delete(m.languages, channelID)
+		return nil
+	}
+	m.languages[channelID] = languageCode
 	return nil
 }
 
 func (m *MockChannelRepository) GetChannelLanguage(channelID string) (string, error) {
-	// Mock implementation - return empty (use guild default)
-	return "", nil
+	// Empty unless set (use guild default)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.languages[channelID], nil
+}
+
+func (m *MockChannelRepository) SetChannelStyle(channelID, style string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.styles == nil {
+		m.styles = make(map[string]string)
+	}
+	if style == "" {
+		delete(m.styles, channelID)
+		return nil
+	}
+	m.styles[channelID] = style
+	return nil
+}
+
+func (m *MockChannelRepository) GetChannelStyle(channelID string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.styles[channelID], nil
 }
 
 func (m *MockChannelRepository) SetGuildLanguage(guildID, languageCode string) error {
@@ -208,12 +313,18 @@ func (m *MockChannelRepository) GetGuildLanguage(guildID string) (string, error)
 
 // MockRSSFeedRepository is a mock for feed testing
 type MockRSSFeedRepository struct {
-	feeds map[string]storage.RSSFeed
+	feeds    map[string]storage.RSSFeed
+	filters  map[string][]filter.Rule // "feedID/channelID" -> rules
+	lastRuns map[string]time.Time
+	etags    map[string]string
 }
 
 func NewMockRSSFeedRepository() *MockRSSFeedRepository {
 	return &MockRSSFeedRepository{
-		feeds: make(map[string]storage.RSSFeed),
+		feeds:    make(map[string]storage.RSSFeed),
+		filters:  make(map[string][]filter.Rule),
+		lastRuns: make(map[string]time.Time),
+		etags:    make(map[string]string),
 	}
 }
 
@@ -246,6 +357,16 @@ func (m *MockRSSFeedRepository) GetAllFeeds() ([]storage.RSSFeed, error) {
 	return feeds, nil
 }
 
+func (m *MockRSSFeedRepository) GetGuildFeedCount(guildID string) (int, error) {
+	count := 0
+	for _, feed := range m.feeds {
+		if feed.OwnerGuildID == guildID {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (m *MockRSSFeedRepository) HasFeed(feedID string) (bool, error) {
 	_, ok := m.feeds[feedID]
 	return ok, nil
@@ -269,6 +390,78 @@ func (m *MockRSSFeedRepository) GetSchedule(feedID string) ([]string, error) {
 	return feed.Schedule, nil
 }
 
+func (m *MockRSSFeedRepository) SetTimezone(feedID, timezone string) error {
+	feed, ok := m.feeds[feedID]
+	if !ok {
+		return fmt.Errorf("feed not found")
+	}
+	feed.Timezone = timezone
+	m.feeds[feedID] = feed
+	return nil
+}
+
+func (m *MockRSSFeedRepository) SetSkipScrape(feedID string, skip bool) error {
+	feed, ok := m.feeds[feedID]
+	if !ok {
+		return fmt.Errorf("feed not found")
+	}
+	feed.SkipScrape = skip
+	m.feeds[feedID] = feed
+	return nil
+}
+
+func (m *MockRSSFeedRepository) SetExtractive(feedID string, extractive bool) error {
+	feed, ok := m.feeds[feedID]
+	if !ok {
+		return fmt.Errorf("feed not found")
+	}
+	feed.Extractive = extractive
+	m.feeds[feedID] = feed
+	return nil
+}
+
+func (m *MockRSSFeedRepository) GetLastRun(feedID string) (time.Time, error) {
+	return m.lastRuns[feedID], nil
+}
+
+func (m *MockRSSFeedRepository) SetLastRun(feedID string, t time.Time) error {
+	m.lastRuns[feedID] = t
+	return nil
+}
+
+func (m *MockRSSFeedRepository) GetHTTPValidators(feedID string) (string, string, error) {
+	return m.etags[feedID], "", nil
+}
+
+func (m *MockRSSFeedRepository) SetHTTPValidators(feedID, etag, lastModified string) error {
+	m.etags[feedID] = etag
+	return nil
+}
+
+func (m *MockRSSFeedRepository) AddFilter(feedID, channelID string, rule filter.Rule) error {
+	key := feedID + "/" + channelID
+	m.filters[key] = append(m.filters[key], rule)
+	return nil
+}
+
+func (m *MockRSSFeedRepository) RemoveFilter(feedID, channelID string, position int) error {
+	key := feedID + "/" + channelID
+	if position < 1 || position > len(m.filters[key]) {
+		return fmt.Errorf("filter #%d not found", position)
+	}
+	m.filters[key] = append(m.filters[key][:position-1], m.filters[key][position:]...)
+	return nil
+}
+
+func (m *MockRSSFeedRepository) GetFilters(feedID, channelID string) ([]filter.Rule, error) {
+	return m.filters[feedID+"/"+channelID], nil
+}
+
+func (m *MockRSSFeedRepository) ClearFilters(feedID, channelID string) error {
+	delete(m.filters, feedID+"/"+channelID)
+	return nil
+}
+
 // TestNewCommandHandler tests handler creation
 func TestNewCommandHandler(t *testing.T) {
 	repo := NewMockChannelRepository(5)
@@ -438,7 +631,7 @@ func TestCommandHandler_SetupNews_ChannelLimitValidation(t *testing.T) {
 			
 			// Seed existing channels
 			for _, chID := range tt.existingChannels {
-				err := repo.AddChannel(chID, "test-feed")
+				err := repo.AddChannel("guild1", chID, "test-feed", 0)
 				require.NoError(t, err)
 			}
 
@@ -465,7 +658,7 @@ func TestCommandHandler_SetupNews_ChannelLimitValidation(t *testing.T) {
 			}
 
 			// Try to add
-			err = repo.AddChannel(tt.newChannelID, "test-feed")
+			err = repo.AddChannel("guild1", tt.newChannelID, "test-feed", 0)
 
 			if tt.shouldSucceed {
 				assert.NoError(t, err)
@@ -538,7 +731,7 @@ func TestCommandHandler_SetupNews_ErrorHandling(t *testing.T) {
 					_, err = repo.GetChannelCount()
 				}
 				if err == nil {
-					err = repo.AddChannel("test", "test-feed")
+					err = repo.AddChannel("guild1", "test", "test-feed", 0)
 				}
 				assert.Error(t, err, "Should encounter an error in repository operations")
 			}
@@ -571,7 +764,7 @@ func TestCommandHandler_Integration(t *testing.T) {
 		assert.Equal(t, i, count)
 
 		// Add channel
-		err = repo.AddChannel(chID, "test-feed")
+		err = repo.AddChannel("guild1", chID, "test-feed", 0)
 		require.NoError(t, err)
 
 		// Verify added
@@ -586,7 +779,7 @@ func TestCommandHandler_Integration(t *testing.T) {
 	assert.Equal(t, maxLimit, count)
 
 	// Try to add one more - should fail
-	err = repo.AddChannel("ch4", "test-feed")
+	err = repo.AddChannel("guild1", "ch4", "test-feed", 0)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "limit reached")
 
@@ -606,7 +799,7 @@ func TestCommandHandler_ConcurrentAccess(t *testing.T) {
 	for i := 0; i < 5; i++ {
 		go func(id int) {
 			chID := fmt.Sprintf("channel-%d", id)
-			_ = repo.AddChannel(chID, "test-feed")
+			_ = repo.AddChannel("guild1", chID, "test-feed", 0)
 			done <- true
 		}(i)
 	}
@@ -662,7 +855,7 @@ func TestCommandHandler_RemoveNews(t *testing.T) {
 
 			// Seed existing channels
 			for _, chID := range tt.existingChannels {
-				err := repo.AddChannel(chID, "test-feed")
+				err := repo.AddChannel("guild1", chID, "test-feed", 0)
 				require.NoError(t, err)
 			}
 
@@ -738,7 +931,7 @@ func TestCommandHandler_ListChannels(t *testing.T) {
 
 			// Seed channels
 			for _, chID := range tt.registeredChannels {
-				err := repo.AddChannel(chID, "test-feed")
+				err := repo.AddChannel("guild1", chID, "test-feed", 0)
 				require.NoError(t, err)
 			}
 
@@ -765,6 +958,33 @@ func TestCommandHandler_ListChannels(t *testing.T) {
 	}
 }
 
+// TestCommandHandler_ListChannels_OnlyOwnGuild tests that a server only sees its own channels
+func TestCommandHandler_ListChannels_OnlyOwnGuild(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	channelRepo := NewMockChannelRepository(0)
+	githubRepo := storage.NewRedisGitHubRepository(client)
+	handler := NewCommandHandler(channelRepo, NewMockRSSFeedRepository(), githubRepo, 0)
+
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch1", "godot", 0))
+	require.NoError(t, channelRepo.AddChannel("guild-2", "ch2", "godot", 0))
+	require.NoError(t, githubRepo.AddRepoChannel("guild-1", "engine", "ch3", 0))
+	require.NoError(t, githubRepo.AddRepoChannel("guild-2", "engine", "ch4", 0))
+
+	list, err := handler.guildChannelsList("guild-1")
+	require.NoError(t, err)
+	assert.Contains(t, list, "<#ch1>")
+	assert.Contains(t, list, "<#ch3> (repos: engine)")
+	assert.NotContains(t, list, "ch2")
+	assert.NotContains(t, list, "ch4")
+
+	list, err = handler.guildChannelsList("guild-3")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
 // TestCommandHandler_RemoveNews_Integration tests full remove workflow
 func TestCommandHandler_RemoveNews_Integration(t *testing.T) {
 	repo := NewMockChannelRepository(5)
@@ -773,7 +993,7 @@ func TestCommandHandler_RemoveNews_Integration(t *testing.T) {
 	// Add channels
 	channels := []string{"ch1", "ch2", "ch3"}
 	for _, ch := range channels {
-		err := repo.AddChannel(ch, "test-feed")
+		err := repo.AddChannel("guild1", ch, "test-feed", 0)
 		require.NoError(t, err)
 	}
 
@@ -867,3 +1087,268 @@ func TestCommandHandler_SetBot(t *testing.T) {
 	assert.NotNil(t, handler.bot)
 	assert.Equal(t, mockBot, handler.bot)
 }
+
+// commandInteraction builds an interaction of a user in a guild, for access checks
+func commandInteraction(guildID, userID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		GuildID: guildID,
+		Member:  &discordgo.Member{User: &discordgo.User{ID: userID}},
+	}}
+}
+
+// TestCommandHandler_FeedOwnership tests which guilds can see and change feeds
+func TestCommandHandler_FeedOwnership(t *testing.T) {
+	feedRepo := NewMockRSSFeedRepository()
+	require.NoError(t, feedRepo.RegisterFeed(storage.RSSFeed{ID: "godot-official"}))
+	require.NoError(t, feedRepo.RegisterFeed(storage.RSSFeed{ID: "team-blog", OwnerGuildID: "guild-1"}))
+
+	handler := NewCommandHandler(NewMockChannelRepository(5), feedRepo, NewMockGitHubRepository(), 5)
+	handler.SetOwnerUserIDs(ParseOwnerUserIDs(" owner-1 ,, owner-2"))
+
+	member := commandInteraction("guild-1", "user-1")
+	other := commandInteraction("guild-2", "user-2")
+	owner := commandInteraction("guild-2", "owner-2")
+
+	tests := []struct {
+		name          string
+		interaction   *discordgo.InteractionCreate
+		feedID        string
+		manage        bool
+		expectFound   bool
+		errorContains string
+	}{
+		{name: "catalog feed visible everywhere", interaction: other, feedID: "godot-official", expectFound: true},
+		{name: "catalog feed read-only for guild admins", interaction: member, feedID: "godot-official", manage: true, errorContains: "public catalog"},
+		{name: "catalog feed editable by bot owners", interaction: owner, feedID: "godot-official", manage: true, expectFound: true},
+		{name: "guild feed editable by its guild", interaction: member, feedID: "team-blog", manage: true, expectFound: true},
+		{name: "guild feed hidden from other guilds", interaction: other, feedID: "team-blog", errorContains: "not found"},
+		{name: "guild feed hidden from bot owners in other guilds", interaction: owner, feedID: "team-blog", manage: true, errorContains: "not found"},
+		{name: "missing feed", interaction: member, feedID: "missing", errorContains: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, message := handler.lookupFeed(tt.interaction, tt.feedID, tt.manage)
+			if tt.expectFound {
+				require.NotNil(t, feed)
+				assert.Empty(t, message)
+				return
+			}
+			assert.Nil(t, feed)
+			assert.Contains(t, message, tt.errorContains)
+		})
+	}
+
+	// Guild admins register feeds for their guild; only bot owners add catalog entries
+	ownerGuildID, message := handler.registrationOwner(member, false)
+	assert.Equal(t, "guild-1", ownerGuildID)
+	assert.Empty(t, message)
+	_, message = handler.registrationOwner(member, true)
+	assert.Contains(t, message, "bot owners")
+	ownerGuildID, message = handler.registrationOwner(owner, true)
+	assert.Equal(t, "", ownerGuildID)
+	assert.Empty(t, message)
+}
+
+// TestCommandHandler_RepoOwnership tests which guilds can see and change repositories
+func TestCommandHandler_RepoOwnership(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	githubRepo := storage.NewRedisGitHubRepository(client)
+	require.NoError(t, githubRepo.RegisterRepository(github.Repository{ID: "godot", Owner: "godotengine", Name: "godot"}))
+	require.NoError(t, githubRepo.RegisterRepository(github.Repository{ID: "game", Owner: "team", Name: "game", OwnerGuildID: "guild-1"}))
+
+	handler := NewCommandHandler(NewMockChannelRepository(5), NewMockRSSFeedRepository(), githubRepo, 5)
+	handler.SetOwnerUserIDs([]string{"owner-1"})
+
+	repo, _ := handler.lookupRepo(commandInteraction("guild-2", "user-2"), "godot", false)
+	assert.NotNil(t, repo)
+	_, message := handler.lookupRepo(commandInteraction("guild-2", "user-2"), "godot", true)
+	assert.Contains(t, message, "public catalog")
+	repo, _ = handler.lookupRepo(commandInteraction("guild-2", "owner-1"), "godot", true)
+	assert.NotNil(t, repo)
+
+	repo, _ = handler.lookupRepo(commandInteraction("guild-1", "user-1"), "game", true)
+	assert.NotNil(t, repo)
+	_, message = handler.lookupRepo(commandInteraction("guild-2", "user-2"), "game", false)
+	assert.Contains(t, message, "not found")
+}
+
+// TestCommandHandler_GuildQuotas tests that quotas count the usage of each guild separately
+func TestCommandHandler_GuildQuotas(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	channelRepo := NewMockChannelRepository(0)
+	feedRepo := NewMockRSSFeedRepository()
+	githubRepo := storage.NewRedisGitHubRepository(client)
+	quotaRepo := storage.NewRedisQuotaRepository(client, storage.Quotas{Channels: 2, Feeds: 1, Repos: 0})
+
+	handler := NewCommandHandler(channelRepo, feedRepo, githubRepo, 0)
+
+	// Quotas are disabled without a quota repository
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch1", "godot-official", 0))
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch2", "godot-official", 0))
+	assert.Empty(t, handler.checkQuota("guild-1", storage.QuotaChannels))
+
+	handler.SetQuotaRepository(quotaRepo)
+	assert.Contains(t, handler.checkQuota("guild-1", storage.QuotaChannels), "quota of channels (2/2)")
+	assert.Empty(t, handler.checkQuota("guild-2", storage.QuotaChannels), "other guilds have their own quota")
+
+	// Catalog entries don't count against any guild
+	require.NoError(t, feedRepo.RegisterFeed(storage.RSSFeed{ID: "godot-official"}))
+	assert.Empty(t, handler.checkQuota("guild-1", storage.QuotaFeeds))
+	require.NoError(t, feedRepo.RegisterFeed(storage.RSSFeed{ID: "team-blog", OwnerGuildID: "guild-1"}))
+	assert.Contains(t, handler.checkQuota("guild-1", storage.QuotaFeeds), "quota of feeds (1/1)")
+
+	// 0 is unlimited
+	require.NoError(t, githubRepo.RegisterRepository(github.Repository{ID: "game", Owner: "team", Name: "game", OwnerGuildID: "guild-1"}))
+	assert.Empty(t, handler.checkQuota("guild-1", storage.QuotaRepos))
+
+	// Overrides of the bot owners replace the default of one guild
+	require.NoError(t, quotaRepo.SetGuildQuota("guild-1", storage.QuotaChannels, 3))
+	assert.Empty(t, handler.checkQuota("guild-1", storage.QuotaChannels))
+
+	// Channels subscribed to repositories count too, once when also subscribed to feeds
+	require.NoError(t, githubRepo.AddRepoChannel("guild-1", "game", "ch2", 0))
+	require.NoError(t, githubRepo.AddRepoChannel("guild-1", "game", "ch3", 0))
+	assert.Contains(t, handler.checkQuota("guild-1", storage.QuotaChannels), "quota of channels (3/3)")
+
+	// Subscriptions are checked against the guild's channel quota by the storage
+	maxGuildChannels, err := handler.guildChannelQuota("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, maxGuildChannels)
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch1", "team-blog", maxGuildChannels), "subscribed channels are counted already")
+	err = channelRepo.AddChannel("guild-1", "ch4", "team-blog", 2)
+	assert.Contains(t, quotaExceededMessage(err), "quota of channels (2/2)")
+	assert.Empty(t, quotaExceededMessage(errors.New("connection refused")))
+
+	usage, err := handler.guildUsage("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, storage.Quotas{Channels: 3, Feeds: 1, Repos: 1}, usage)
+
+	summary, err := handler.guildQuotaSummary("guild-1")
+	require.NoError(t, err)
+	assert.Contains(t, summary, "• channels: 3/3")
+	assert.Contains(t, summary, "• repos: 1/unlimited")
+	assert.Contains(t, summary, "Overridden: channels")
+}
+
+// TestCommandHandler_BotOwners tests who can use the /owner commands
+func TestCommandHandler_BotOwners(t *testing.T) {
+	handler := NewCommandHandler(NewMockChannelRepository(0), NewMockRSSFeedRepository(), NewMockGitHubRepository(), 0)
+	handler.SetOwnerUserIDs([]string{"owner-1"})
+
+	homeAdmin := commandInteraction("home", "admin-1")
+	homeAdmin.Member.Permissions = discordgo.PermissionManageServer
+	homeMember := commandInteraction("home", "user-1")
+	otherAdmin := commandInteraction("guild-1", "admin-2")
+	otherAdmin.Member.Permissions = discordgo.PermissionManageServer
+
+	assert.True(t, handler.isBotOwner(commandInteraction("guild-1", "owner-1")))
+	assert.False(t, handler.isBotOwner(homeAdmin), "no home guild configured")
+
+	handler.SetOwnerGuildID("home")
+	assert.True(t, handler.isBotOwner(homeAdmin))
+	assert.False(t, handler.isBotOwner(homeMember), "home guild members need Manage Server")
+	assert.False(t, handler.isBotOwner(otherAdmin), "admins of other guilds aren't bot owners")
+}
+
+// TestCommandHandler_OwnerCommands tests freezing, purging and clearing queues as a bot owner
+func TestCommandHandler_OwnerCommands(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	channelRepo := NewMockChannelRepository(0)
+	feedRepo := NewMockRSSFeedRepository()
+	githubRepo := storage.NewRedisGitHubRepository(client)
+	guildRepo := storage.NewRedisGuildRepository(client)
+	quotaRepo := storage.NewRedisQuotaRepository(client, storage.Quotas{Channels: 5})
+	deliveryRepo := storage.NewRedisDeliveryRepository(client)
+
+	handler := NewCommandHandler(channelRepo, feedRepo, githubRepo, 0)
+	handler.SetQuotaRepository(quotaRepo)
+	handler.SetGuildRepository(guildRepo)
+	handler.SetDeliveryRepository(deliveryRepo)
+
+	// Freezing
+	assert.False(t, handler.isGuildFrozen("guild-1"))
+	require.NoError(t, guildRepo.SetGuildFrozen("guild-1", true))
+	assert.True(t, handler.isGuildFrozen("guild-1"))
+	assert.False(t, handler.isGuildFrozen("guild-2"))
+
+	overview := handler.guildsOverview([]*discordgo.Guild{
+		{ID: "guild-1", Name: "Team", MemberCount: 10},
+		{ID: "guild-2", Name: "Other", MemberCount: 20},
+	})
+	assert.Contains(t, overview, "Servers using the bot** (2)")
+	assert.Contains(t, overview, "❄️ • **Team** (`guild-1`)")
+	assert.Less(t, strings.Index(overview, "Other"), strings.Index(overview, "Team"), "largest servers first")
+
+	// Purging removes the guild's subscriptions, entries and settings, and nothing else
+	require.NoError(t, feedRepo.RegisterFeed(storage.RSSFeed{ID: "godot-official"}))
+	require.NoError(t, feedRepo.RegisterFeed(storage.RSSFeed{ID: "team-blog", OwnerGuildID: "guild-1"}))
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch1", "godot-official", 0))
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch1", "team-blog", 0))
+	require.NoError(t, channelRepo.AddChannel("guild-2", "ch2", "godot-official", 0))
+	require.NoError(t, githubRepo.RegisterRepository(github.Repository{ID: "engine", Owner: "godotengine", Name: "godot"}))
+	require.NoError(t, githubRepo.RegisterRepository(github.Repository{ID: "game", Owner: "team", Name: "game", OwnerGuildID: "guild-1"}))
+	require.NoError(t, githubRepo.AddRepoChannel("guild-1", "engine", "ch3", 0))
+	require.NoError(t, githubRepo.AddRepoChannel("guild-1", "game", "ch3", 0))
+	require.NoError(t, githubRepo.AddRepoChannel("guild-2", "engine", "ch4", 0))
+	require.NoError(t, quotaRepo.SetGuildQuota("guild-1", storage.QuotaChannels, 10))
+	require.NoError(t, channelRepo.SetChannelLanguage("ch1", "fr"))
+	require.NoError(t, channelRepo.SetChannelStyle("ch1", "tldr"))
+	require.NoError(t, channelRepo.SetChannelLanguage("ch2", "de"))
+	now := time.Now()
+	require.NoError(t, deliveryRepo.ScheduleRetry(storage.DeliveryRetry{Kind: deliveryKindRSS, ItemKey: "rss:godot-official:guid-1", ChannelID: "ch1", NextAttempt: now}))
+	require.NoError(t, deliveryRepo.ScheduleRetry(storage.DeliveryRetry{Kind: deliveryKindRSS, ItemKey: "rss:godot-official:guid-1", ChannelID: "ch2", NextAttempt: now}))
+	require.NoError(t, deliveryRepo.ScheduleRetry(storage.DeliveryRetry{Kind: deliveryKindGitHub, ItemKey: "github:engine:1-2", ChannelID: "ch3", NextAttempt: now}))
+
+	result, err := handler.purgeGuild("guild-1", []string{"ch1", "ch3"})
+	require.NoError(t, err)
+	assert.Equal(t, purgeResult{subscriptions: 4, feeds: 1, repos: 1, settings: 1}, result)
+
+	feeds, err := channelRepo.GetChannelFeeds("ch2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"godot-official"}, feeds)
+	hasFeed, err := feedRepo.HasFeed("team-blog")
+	require.NoError(t, err)
+	assert.False(t, hasFeed)
+	hasRepo, err := githubRepo.HasRepository("game")
+	require.NoError(t, err)
+	assert.False(t, hasRepo)
+	channels, err := githubRepo.GetRepoChannels("engine")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch4"}, channels)
+	quotas, err := quotaRepo.GetGuildQuotas("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, 5, quotas.Channels, "quota overrides are purged")
+	assert.NotContains(t, channelRepo.languages, "ch1", "channel languages are purged")
+	assert.NotContains(t, channelRepo.styles, "ch1", "channel styles are purged")
+	assert.Equal(t, "de", channelRepo.languages["ch2"])
+	assert.True(t, handler.isGuildFrozen("guild-1"), "purging keeps the guild frozen")
+	retries, err := deliveryRepo.GetDueRetries(deliveryKindRSS, now, 10)
+	require.NoError(t, err)
+	require.Len(t, retries, 1, "delivery retries of the guild's channels are purged")
+	assert.Equal(t, "ch2", retries[0].ChannelID)
+	retries, err = deliveryRepo.GetDueRetries(deliveryKindGitHub, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, retries)
+
+	// Clearing pending queues
+	require.NoError(t, githubRepo.AddToPendingQueue("engine", github.PullRequest{ID: 1}))
+	require.NoError(t, githubRepo.AddToPendingQueue("engine", github.PullRequest{ID: 2}))
+	cleared, message := handler.clearRepoPending("engine")
+	assert.Empty(t, message)
+	assert.Equal(t, 2, cleared)
+	count, err := githubRepo.GetPendingCount("engine")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	_, message = handler.clearRepoPending("missing")
+	assert.Contains(t, message, "not found")
+}
@@ -0,0 +1,314 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// Delivery kinds separate the retry queues of the RSS bot and the GitHub monitor
+	deliveryKindRSS    = "rss"
+	deliveryKindGitHub = "github"
+
+	// maxDeliveryAttempts is how many times a post is attempted before giving up
+	maxDeliveryAttempts = 6
+	// deliveryRetryBase is the delay before the first retry; it doubles on every attempt
+	deliveryRetryBase = 2 * time.Minute
+	// deliveryRetryMax caps the delay between retries
+	deliveryRetryMax = 2 * time.Hour
+	// deliveryRetryBatch bounds how many retries are sent per scheduler tick
+	deliveryRetryBatch = 20
+)
+
+// permanentDeliveryErrors maps the Discord error codes after which a channel can never
+// receive posts again to the reason shown to the server owner
+var permanentDeliveryErrors = map[int]string{
+	discordgo.ErrCodeUnknownChannel:     "the channel no longer exists",
+	discordgo.ErrCodeMissingAccess:      "the bot can no longer access the channel",
+	discordgo.ErrCodeMissingPermissions: "the bot is missing permissions to post in the channel",
+}
+
+// resubscribeCommands names the command that subscribes a channel again, per delivery kind
+var resubscribeCommands = map[string]string{
+	deliveryKindRSS:    "/setup-feed-channel",
+	deliveryKindGitHub: "/setup-repo-channel",
+}
+
+// deliverer posts embeds to channels. It records every (item, channel) delivery so an
+// item is never posted twice to a channel, queues failed posts for retry with backoff,
+// and unsubscribes channels that can never receive posts again.
+type deliverer struct {
+	kind       string
+	deliveries storage.DeliveryRepository // nil disables delivery records and retries
+
+	send          func(channelID string, embed *discordgo.MessageEmbed) error
+	subscriptions func(channelID string) ([]string, error) // returns the feed/repo IDs the channel is subscribed to, nil skips the check
+	frozen        func(channelID string) bool              // reports whether the channel's guild is frozen, nil skips the check
+	unsubscribe   func(channelID string) ([]string, error) // removes every subscription of the channel, returning the feed/repo IDs
+	notify        func(channelID, message string)          // tells the channel's server admins about the channel
+}
+
+// undelivered returns the channels the item was not posted to yet
+func (d *deliverer) undelivered(itemKey string, channels []string) []string {
+	if d.deliveries == nil {
+		return channels
+	}
+
+	pending := make([]string, 0, len(channels))
+	for _, channelID := range channels {
+		delivered, err := d.deliveries.IsDelivered(itemKey, channelID)
+		if err != nil {
+			log.Printf("WARNING: Failed to check delivery of %s to channel %s: %v", itemKey, channelID, err)
+		}
+		if !delivered {
+			pending = append(pending, channelID)
+		}
+	}
+	return pending
+}
+
+// deliver posts the item to a channel and records the outcome. Failed posts are queued
+// for retry, unless the channel can never receive posts again, in which case it is
+// unsubscribed. It returns the send error, if any.
+func (d *deliverer) deliver(itemKey, sourceID, channelID string, embed *discordgo.MessageEmbed) error {
+	err := d.send(channelID, embed)
+	if err == nil {
+		d.markDelivered(itemKey, channelID)
+		return nil
+	}
+
+	payload, marshalErr := json.Marshal(embed)
+	if marshalErr != nil {
+		log.Printf("ERROR: Failed to serialize message for %s: %v", itemKey, marshalErr)
+		return err
+	}
+
+	d.handleFailure(storage.DeliveryRetry{
+		Kind:      d.kind,
+		ItemKey:   itemKey,
+		SourceID:  sourceID,
+		ChannelID: channelID,
+		Payload:   payload,
+	}, err)
+	return err
+}
+
+// retryDue sends the queued posts whose retry time has come
+func (d *deliverer) retryDue(now time.Time) {
+	if d.deliveries == nil {
+		return
+	}
+
+	retries, err := d.deliveries.GetDueRetries(d.kind, now, deliveryRetryBatch)
+	if err != nil {
+		log.Printf("ERROR: Failed to get due %s delivery retries: %v", d.kind, err)
+		return
+	}
+
+	for _, retry := range retries {
+		// The channel may have been unsubscribed since the post failed
+		if !d.stillSubscribed(retry) {
+			log.Printf("Dropping retry of %s: channel %s is no longer subscribed to %s", retry.ItemKey, retry.ChannelID, retry.SourceID)
+			d.removeRetry(retry)
+			continue
+		}
+		// Frozen guilds receive no posts, including the ones that failed before the freeze
+		if d.frozen != nil && d.frozen(retry.ChannelID) {
+			log.Printf("Dropping retry of %s: the guild of channel %s is frozen", retry.ItemKey, retry.ChannelID)
+			d.removeRetry(retry)
+			continue
+		}
+
+		var embed discordgo.MessageEmbed
+		if err := json.Unmarshal(retry.Payload, &embed); err != nil {
+			log.Printf("ERROR: Dropping unreadable retry of %s to channel %s: %v", retry.ItemKey, retry.ChannelID, err)
+			d.removeRetry(retry)
+			continue
+		}
+
+		log.Printf("Retrying delivery of %s to channel %s (attempt %d/%d)", retry.ItemKey, retry.ChannelID, retry.Attempts+1, maxDeliveryAttempts)
+		err := d.send(retry.ChannelID, &embed)
+		if err == nil {
+			d.markDelivered(retry.ItemKey, retry.ChannelID)
+			d.removeRetry(retry)
+			continue
+		}
+		d.handleFailure(retry, err)
+	}
+}
+
+// stillSubscribed reports whether the channel of a retry is still subscribed to its feed or
+// repository. Retries are kept when the subscriptions can't be read.
+func (d *deliverer) stillSubscribed(retry storage.DeliveryRetry) bool {
+	if d.subscriptions == nil {
+		return true
+	}
+
+	sourceIDs, err := d.subscriptions(retry.ChannelID)
+	if err != nil {
+		log.Printf("WARNING: Failed to get subscriptions of channel %s: %v", retry.ChannelID, err)
+		return true
+	}
+	for _, sourceID := range sourceIDs {
+		if sourceID == retry.SourceID {
+			return true
+		}
+	}
+	return false
+}
+
+// handleFailure queues a failed post for retry with exponential backoff, gives up after
+// maxDeliveryAttempts, and unsubscribes the channel on permanent failures
+func (d *deliverer) handleFailure(retry storage.DeliveryRetry, sendErr error) {
+	if reason, permanent := permanentDeliveryFailure(sendErr); permanent {
+		log.Printf("ERROR: Channel %s can't receive posts (%s): %v", retry.ChannelID, reason, sendErr)
+		d.removeRetry(retry)
+		d.unsubscribeChannel(retry.ChannelID, reason)
+		return
+	}
+
+	retry.Attempts++
+	retry.LastError = sendErr.Error()
+	if retry.Attempts >= maxDeliveryAttempts {
+		log.Printf("ERROR: Giving up delivery of %s to channel %s after %d attempts: %v", retry.ItemKey, retry.ChannelID, retry.Attempts, sendErr)
+		d.removeRetry(retry)
+		return
+	}
+
+	if d.deliveries == nil {
+		log.Printf("Error sending %s to channel %s: %v", retry.ItemKey, retry.ChannelID, sendErr)
+		return
+	}
+
+	retry.NextAttempt = time.Now().Add(deliveryRetryDelay(retry.Attempts))
+	if err := d.deliveries.ScheduleRetry(retry); err != nil {
+		log.Printf("ERROR: Failed to queue retry of %s to channel %s: %v", retry.ItemKey, retry.ChannelID, err)
+		return
+	}
+	log.Printf("Delivery of %s to channel %s failed, retrying at %s: %v",
+		retry.ItemKey, retry.ChannelID, retry.NextAttempt.Format(time.RFC3339), sendErr)
+}
+
+// unsubscribeChannel removes every subscription of a channel that can't receive posts
+// and tells the server admins why
+func (d *deliverer) unsubscribeChannel(channelID, reason string) {
+	ids, err := d.unsubscribe(channelID)
+	if err != nil {
+		log.Printf("ERROR: Failed to unsubscribe channel %s: %v", channelID, err)
+		return
+	}
+
+	if d.deliveries != nil {
+		if err := d.deliveries.RemoveChannelRetries(d.kind, channelID); err != nil {
+			log.Printf("WARNING: Failed to drop queued retries for channel %s: %v", channelID, err)
+		}
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+	log.Printf("Unsubscribed channel %s from %s", channelID, strings.Join(ids, ", "))
+
+	d.notify(channelID, fmt.Sprintf(
+		"⚠️ I couldn't post to <#%s> because %s, so it was unsubscribed from: %s.\n"+
+			"Fix the channel and subscribe it again with `%s`.",
+		channelID, reason, strings.Join(ids, ", "), resubscribeCommands[d.kind],
+	))
+}
+
+// markDelivered records a successful post
+func (d *deliverer) markDelivered(itemKey, channelID string) {
+	if d.deliveries == nil {
+		return
+	}
+	if err := d.deliveries.MarkDelivered(itemKey, channelID); err != nil {
+		log.Printf("WARNING: Failed to record delivery of %s to channel %s: %v", itemKey, channelID, err)
+	}
+}
+
+// recordSource records where the posted content of an item came from
+func (d *deliverer) recordSource(itemKey, source string) {
+	if d.deliveries == nil {
+		return
+	}
+	if err := d.deliveries.SetContentSource(itemKey, source); err != nil {
+		log.Printf("WARNING: Failed to record content source of %s: %v", itemKey, err)
+	}
+}
+
+// removeRetry drops a post from the retry queue
+func (d *deliverer) removeRetry(retry storage.DeliveryRetry) {
+	if d.deliveries == nil {
+		return
+	}
+	if err := d.deliveries.RemoveRetry(retry.Kind, retry.ItemKey, retry.ChannelID); err != nil {
+		log.Printf("WARNING: Failed to remove retry of %s to channel %s: %v", retry.ItemKey, retry.ChannelID, err)
+	}
+}
+
+// deliveryRetryDelay returns the backoff before the next attempt after the given number of failures
+func deliveryRetryDelay(attempts int) time.Duration {
+	delay := deliveryRetryBase
+	for i := 1; i < attempts && delay < deliveryRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > deliveryRetryMax {
+		delay = deliveryRetryMax
+	}
+	return delay
+}
+
+// permanentDeliveryFailure reports whether a send error means the channel can never
+// receive posts again, and why
+func permanentDeliveryFailure(err error) (string, bool) {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Message == nil {
+		return "", false
+	}
+	reason, ok := permanentDeliveryErrors[restErr.Message.Code]
+	return reason, ok
+}
+
+// notifyGuildOwner sends a message about a channel to the owner of its server, by direct
+// message or, when the owner doesn't accept DMs, in the server's system channel
+func notifyGuildOwner(session *discordgo.Session, channelID, message string) {
+	channel, err := session.State.Channel(channelID)
+	if err != nil {
+		channel, err = session.Channel(channelID)
+	}
+	if err != nil {
+		log.Printf("WARNING: Can't find the server of channel %s to notify its owner: %v", channelID, err)
+		return
+	}
+
+	guild, err := session.State.Guild(channel.GuildID)
+	if err != nil {
+		guild, err = session.Guild(channel.GuildID)
+	}
+	if err != nil {
+		log.Printf("WARNING: Can't get server %s to notify its owner: %v", channel.GuildID, err)
+		return
+	}
+
+	dm, err := session.UserChannelCreate(guild.OwnerID)
+	if err == nil {
+		if _, err = session.ChannelMessageSend(dm.ID, fmt.Sprintf("**%s**: %s", guild.Name, message)); err == nil {
+			return
+		}
+	}
+	log.Printf("WARNING: Can't DM the owner of server %s: %v", guild.ID, err)
+
+	if guild.SystemChannelID == "" || guild.SystemChannelID == channelID {
+		return
+	}
+	if _, err := session.ChannelMessageSend(guild.SystemChannelID, message); err != nil {
+		log.Printf("WARNING: Can't notify server %s in its system channel: %v", guild.ID, err)
+	}
+}
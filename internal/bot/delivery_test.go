@@ -0,0 +1,242 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bwmarrin/discordgo"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDeliverer records sends, unsubscriptions and notifications; sends to channels
+// listed in failures return the given error
+type testDeliverer struct {
+	*deliverer
+	failures     map[string]error
+	sent         []string
+	unsubscribed []string
+	notified     []string
+}
+
+func newTestDeliverer(t *testing.T, kind string) *testDeliverer {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	td := &testDeliverer{failures: make(map[string]error)}
+	td.deliverer = &deliverer{
+		kind:       kind,
+		deliveries: storage.NewRedisDeliveryRepository(client),
+		send: func(channelID string, embed *discordgo.MessageEmbed) error {
+			if err := td.failures[channelID]; err != nil {
+				return err
+			}
+			td.sent = append(td.sent, channelID+":"+embed.Title)
+			return nil
+		},
+		unsubscribe: func(channelID string) ([]string, error) {
+			td.unsubscribed = append(td.unsubscribed, channelID)
+			return []string{"godot-official"}, nil
+		},
+		notify: func(channelID, message string) {
+			td.notified = append(td.notified, message)
+		},
+	}
+	return td
+}
+
+func discordError(status, code int) error {
+	return fmt.Errorf("failed to send message: %w", &discordgo.RESTError{
+		Response: &http.Response{StatusCode: status, Status: http.StatusText(status)},
+		Message:  &discordgo.APIErrorMessage{Code: code, Message: "error"},
+	})
+}
+
+func TestDeliverer_RecordsDeliveries(t *testing.T) {
+	td := newTestDeliverer(t, deliveryKindRSS)
+	embed := &discordgo.MessageEmbed{Title: "Article"}
+
+	require.NoError(t, td.deliver("rss:godot:guid-1", "godot", "channel1", embed))
+
+	assert.Equal(t, []string{"channel2"}, td.undelivered("rss:godot:guid-1", []string{"channel1", "channel2"}))
+	assert.Equal(t, []string{"channel1"}, td.undelivered("rss:godot:guid-2", []string{"channel1"}))
+
+	td.recordSource("rss:godot:guid-1", string(contentTitleOnly))
+	source, err := td.deliveries.GetContentSource("rss:godot:guid-1")
+	require.NoError(t, err)
+	assert.Equal(t, string(contentTitleOnly), source)
+
+	// Without a repository every channel is pending
+	td.deliveries = nil
+	assert.Equal(t, []string{"channel1"}, td.undelivered("rss:godot:guid-1", []string{"channel1"}))
+}
+
+func TestDeliverer_RetriesTransientFailures(t *testing.T) {
+	td := newTestDeliverer(t, deliveryKindRSS)
+	td.failures["channel1"] = errors.New("HTTP 500 Internal Server Error")
+
+	err := td.deliver("rss:godot:guid-1", "godot", "channel1", &discordgo.MessageEmbed{Title: "Article"})
+	require.Error(t, err)
+	assert.Empty(t, td.unsubscribed)
+
+	// Not due yet
+	td.retryDue(time.Now())
+	assert.Empty(t, td.sent)
+
+	// Still failing: the retry is rescheduled with a longer delay
+	td.retryDue(time.Now().Add(deliveryRetryBase + time.Minute))
+	retries, err := td.deliveries.GetDueRetries(deliveryKindRSS, time.Now().Add(deliveryRetryBase+time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, retries)
+
+	retries, err = td.deliveries.GetDueRetries(deliveryKindRSS, time.Now().Add(deliveryRetryDelay(2)+time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, retries, 1)
+	assert.Equal(t, 2, retries[0].Attempts)
+	assert.Equal(t, "HTTP 500 Internal Server Error", retries[0].LastError)
+
+	// The channel recovers: the stored embed is sent and recorded
+	delete(td.failures, "channel1")
+	td.retryDue(time.Now().Add(deliveryRetryDelay(2) + time.Minute))
+	assert.Equal(t, []string{"channel1:Article"}, td.sent)
+	assert.Empty(t, td.undelivered("rss:godot:guid-1", []string{"channel1"}))
+
+	retries, err = td.deliveries.GetDueRetries(deliveryKindRSS, time.Now().Add(deliveryRetryMax), 10)
+	require.NoError(t, err)
+	assert.Empty(t, retries)
+}
+
+func TestDeliverer_GivesUpAfterMaxAttempts(t *testing.T) {
+	td := newTestDeliverer(t, deliveryKindGitHub)
+	td.failures["channel1"] = errors.New("timeout")
+
+	require.Error(t, td.deliver("github:godot:1-5", "godot", "channel1", &discordgo.MessageEmbed{Title: "PRs"}))
+	for i := 0; i < maxDeliveryAttempts; i++ {
+		td.retryDue(time.Now().Add(deliveryRetryMax + time.Minute))
+	}
+
+	retries, err := td.deliveries.GetDueRetries(deliveryKindGitHub, time.Now().Add(deliveryRetryMax+time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, retries)
+	assert.Empty(t, td.sent)
+}
+
+func TestDeliverer_UnsubscribesOnPermanentFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+	}{
+		{name: "deleted channel", code: discordgo.ErrCodeUnknownChannel},
+		{name: "missing access", code: discordgo.ErrCodeMissingAccess},
+		{name: "missing permissions", code: discordgo.ErrCodeMissingPermissions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newTestDeliverer(t, deliveryKindRSS)
+			td.failures["channel1"] = discordError(403, tt.code)
+
+			require.Error(t, td.deliver("rss:godot:guid-1", "godot", "channel1", &discordgo.MessageEmbed{Title: "Article"}))
+
+			assert.Equal(t, []string{"channel1"}, td.unsubscribed)
+			require.Len(t, td.notified, 1)
+			assert.Contains(t, td.notified[0], "godot-official")
+			assert.Contains(t, td.notified[0], "/setup-feed-channel")
+
+			// Nothing is queued for a channel that can't receive posts
+			retries, err := td.deliveries.GetDueRetries(deliveryKindRSS, time.Now().Add(deliveryRetryMax), 10)
+			require.NoError(t, err)
+			assert.Empty(t, retries)
+		})
+	}
+}
+
+func TestDeliverer_PermanentFailureOnRetry(t *testing.T) {
+	td := newTestDeliverer(t, deliveryKindGitHub)
+	td.failures["channel1"] = errors.New("HTTP 502 Bad Gateway")
+	require.Error(t, td.deliver("github:godot:1-5", "godot", "channel1", &discordgo.MessageEmbed{Title: "PRs"}))
+
+	// The channel is deleted before the retry
+	td.failures["channel1"] = discordError(404, discordgo.ErrCodeUnknownChannel)
+	td.retryDue(time.Now().Add(deliveryRetryBase + time.Minute))
+
+	assert.Equal(t, []string{"channel1"}, td.unsubscribed)
+	require.Len(t, td.notified, 1)
+	assert.Contains(t, td.notified[0], "/setup-repo-channel")
+
+	retries, err := td.deliveries.GetDueRetries(deliveryKindGitHub, time.Now().Add(deliveryRetryMax), 10)
+	require.NoError(t, err)
+	assert.Empty(t, retries)
+}
+
+func TestDeliverer_DropsRetriesOfUnsubscribedChannels(t *testing.T) {
+	td := newTestDeliverer(t, deliveryKindRSS)
+	subscriptions := map[string][]string{
+		"channel1": {"godot"},
+		"channel2": {"godot"},
+	}
+	td.subscriptions = func(channelID string) ([]string, error) {
+		return subscriptions[channelID], nil
+	}
+	td.failures["channel1"] = errors.New("timeout")
+	td.failures["channel2"] = errors.New("timeout")
+	require.Error(t, td.deliver("rss:godot:guid-1", "godot", "channel1", &discordgo.MessageEmbed{Title: "Article"}))
+	require.Error(t, td.deliver("rss:godot:guid-1", "godot", "channel2", &discordgo.MessageEmbed{Title: "Article"}))
+
+	// channel1 runs /remove-news before the retry, channel2 stays subscribed
+	subscriptions["channel1"] = []string{"other-feed"}
+	td.failures = map[string]error{}
+	td.retryDue(time.Now().Add(deliveryRetryBase + time.Minute))
+
+	assert.Equal(t, []string{"channel2:Article"}, td.sent)
+	retries, err := td.deliveries.GetDueRetries(deliveryKindRSS, time.Now().Add(deliveryRetryMax), 10)
+	require.NoError(t, err)
+	assert.Empty(t, retries, "the retry of the unsubscribed channel is dropped")
+}
+
+func TestDeliverer_DropsRetriesOfFrozenGuilds(t *testing.T) {
+	td := newTestDeliverer(t, deliveryKindGitHub)
+	frozen := map[string]bool{}
+	td.frozen = func(channelID string) bool {
+		return frozen[channelID]
+	}
+	td.failures["channel1"] = errors.New("timeout")
+	td.failures["channel2"] = errors.New("timeout")
+	require.Error(t, td.deliver("github:engine:1-2", "engine", "channel1", &discordgo.MessageEmbed{Title: "PRs"}))
+	require.Error(t, td.deliver("github:engine:1-2", "engine", "channel2", &discordgo.MessageEmbed{Title: "PRs"}))
+
+	// The bot owners freeze channel1's guild before the retry
+	frozen["channel1"] = true
+	td.failures = map[string]error{}
+	td.retryDue(time.Now().Add(deliveryRetryBase + time.Minute))
+
+	assert.Equal(t, []string{"channel2:PRs"}, td.sent)
+	retries, err := td.deliveries.GetDueRetries(deliveryKindGitHub, time.Now().Add(deliveryRetryMax), 10)
+	require.NoError(t, err)
+	assert.Empty(t, retries, "the retry of the frozen guild's channel is dropped")
+}
+
+func TestDeliveryRetryDelay(t *testing.T) {
+	assert.Equal(t, deliveryRetryBase, deliveryRetryDelay(1))
+	assert.Equal(t, 2*deliveryRetryBase, deliveryRetryDelay(2))
+	assert.Equal(t, 4*deliveryRetryBase, deliveryRetryDelay(3))
+	assert.Equal(t, deliveryRetryMax, deliveryRetryDelay(20))
+}
+
+func TestPermanentDeliveryFailure(t *testing.T) {
+	_, permanent := permanentDeliveryFailure(discordError(404, discordgo.ErrCodeUnknownChannel))
+	assert.True(t, permanent)
+
+	_, permanent = permanentDeliveryFailure(discordError(429, 0))
+	assert.False(t, permanent)
+
+	_, permanent = permanentDeliveryFailure(errors.New("connection reset"))
+	assert.False(t, permanent)
+}
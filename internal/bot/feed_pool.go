@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"errors"
+	"sync"
+)
+
+// defaultFeedWorkers is the number of feeds processed concurrently
+const defaultFeedWorkers = 3
+
+// errFeedBusy is returned when a feed is already being processed
+var errFeedBusy = errors.New("feed is already being processed")
+
+// errPoolStopped is returned when a feed is run after the bot started stopping
+var errPoolStopped = errors.New("feed pool is stopped")
+
+// feedPool bounds how many feeds are processed concurrently and makes sure
+// the same feed is never processed twice at once (e.g. a manual /update-feed
+// racing the scheduled run)
+type feedPool struct {
+	slots chan struct{}
+
+	mu      sync.Mutex
+	running map[string]bool
+	stopped bool
+	active  sync.WaitGroup // runs in progress or waiting for a slot
+}
+
+// newFeedPool creates a pool running at most workers feeds at a time
+func newFeedPool(workers int) *feedPool {
+	if workers <= 0 {
+		workers = defaultFeedWorkers
+	}
+	return &feedPool{
+		slots:   make(chan struct{}, workers),
+		running: make(map[string]bool),
+	}
+}
+
+// run executes fn for the feed on a worker slot, blocking while all workers are busy.
+// It returns errFeedBusy without running fn if the feed is already running or queued,
+// and errPoolStopped once the pool is stopped.
+func (p *feedPool) run(feedID string, fn func() error) error {
+	if err := p.tryLock(feedID); err != nil {
+		return err
+	}
+	defer p.unlock(feedID)
+
+	p.slots <- struct{}{}
+	defer func() { <-p.slots }()
+
+	return fn()
+}
+
+// tryLock marks a feed as running, failing if it already is or the pool is stopped
+func (p *feedPool) tryLock(feedID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return errPoolStopped
+	}
+	if p.running[feedID] {
+		return errFeedBusy
+	}
+	p.running[feedID] = true
+	p.active.Add(1)
+	return nil
+}
+
+// unlock marks a feed as no longer running
+func (p *feedPool) unlock(feedID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.running, feedID)
+	p.active.Done()
+}
+
+// stop refuses new runs and waits for the runs in progress to finish, so their history
+// and delivery records are written before the storage is closed
+func (p *feedPool) stop() {
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+
+	p.active.Wait()
+}
+
+// workers returns the pool size
+func (p *feedPool) workers() int {
+	return cap(p.slots)
+}
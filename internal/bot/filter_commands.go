@@ -0,0 +1,285 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/filter"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Feed Filter Commands
+// This file contains the /feed-filter command and its subcommands
+
+// feedFilterCommand returns the /feed-filter command definition
+func feedFilterCommand() *discordgo.ApplicationCommand {
+	feedOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "feed",
+		Description: "The feed identifier",
+		Required:    true,
+	}
+	channelOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionChannel,
+		Name:        "channel",
+		Description: "Limit the rules to this channel's subscription (default: the whole feed)",
+		Required:    false,
+		ChannelTypes: []discordgo.ChannelType{
+			discordgo.ChannelTypeGuildText,
+		},
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        "feed-filter",
+		Description: "Manage include/exclude filters for a feed or a channel subscription (Admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Add a filter rule",
+				Options: []*discordgo.ApplicationCommandOption{
+					feedOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "action",
+						Description: "Keep only matching articles (include) or drop them (exclude)",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "include", Value: filter.ActionInclude},
+							{Name: "exclude", Value: filter.ActionExclude},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "type",
+						Description: "What the value is matched against",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "keyword (title/description)", Value: filter.TypeKeyword},
+							{Name: "category (RSS <category>)", Value: filter.TypeCategory},
+							{Name: "regex (title/description)", Value: filter.TypeRegex},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "value",
+						Description: "Keyword, category name or regular expression",
+						Required:    true,
+					},
+					channelOption,
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Remove a filter rule by its number (see /feed-filter list)",
+				Options: []*discordgo.ApplicationCommandOption{
+					feedOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "number",
+						Description: "The rule number shown by /feed-filter list",
+						Required:    true,
+					},
+					channelOption,
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List the filter rules",
+				Options: []*discordgo.ApplicationCommandOption{
+					feedOption,
+					channelOption,
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "clear",
+				Description: "Remove all filter rules",
+				Options: []*discordgo.ApplicationCommandOption{
+					feedOption,
+					channelOption,
+				},
+			},
+		},
+	}
+}
+
+// handleFeedFilter handles the /feed-filter command and routes its subcommands
+func (h *CommandHandler) handleFeedFilter(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.respondError(s, i, "This command can only be used in a server.")
+		return
+	}
+
+	member := i.Member
+	if member == nil || !h.hasManageServerPermission(member) {
+		h.respondError(s, i, "❌ You need the **Manage Server** permission to use this command.")
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		h.respondError(s, i, "❌ You need to specify a subcommand.")
+		return
+	}
+
+	subcommand := options[0]
+	args := optionsByName(subcommand.Options)
+
+	feedOpt, ok := args["feed"]
+	if !ok {
+		h.respondError(s, i, "❌ You need to specify a feed.")
+		return
+	}
+	feedID := feedOpt.StringValue()
+
+	// Rules of a whole feed apply to every server subscribed to it, so changing them needs
+	// ownership of the feed; rules of a channel subscription only need the feed to be visible
+	_, channelScoped := args["channel"]
+	manage := !channelScoped && subcommand.Name != "list"
+	if feed, message := h.lookupFeed(i, feedID, manage); feed == nil {
+		h.respondError(s, i, message)
+		return
+	}
+
+	// Optional channel scope: the channel must belong to this server and be subscribed to the feed
+	channelID := ""
+	scope := fmt.Sprintf("feed `%s`", feedID)
+	if channelOpt, ok := args["channel"]; ok {
+		channel := channelOpt.ChannelValue(s)
+		if channel == nil {
+			h.respondError(s, i, "❌ Invalid channel.")
+			return
+		}
+		if channel.GuildID != "" && channel.GuildID != i.GuildID {
+			h.respondError(s, i, "❌ Channel must be in this server.")
+			return
+		}
+
+		feeds, err := h.channelRepo.GetChannelFeeds(channel.ID)
+		if err != nil {
+			log.Printf("[FEED-FILTER] ERROR: Failed to get feeds for channel %s: %v", channel.ID, err)
+			h.respondError(s, i, "Error checking channel subscriptions.")
+			return
+		}
+		subscribed := false
+		for _, id := range feeds {
+			if id == feedID {
+				subscribed = true
+				break
+			}
+		}
+		if !subscribed {
+			h.respondError(s, i, fmt.Sprintf("❌ <#%s> is not subscribed to feed '%s'.", channel.ID, feedID))
+			return
+		}
+
+		channelID = channel.ID
+		scope = fmt.Sprintf("<#%s> subscription to feed `%s`", channelID, feedID)
+	}
+
+	switch subcommand.Name {
+	case "add":
+		h.handleFeedFilterAdd(s, i, args, feedID, channelID, scope)
+	case "remove":
+		h.handleFeedFilterRemove(s, i, args, feedID, channelID, scope)
+	case "list":
+		h.handleFeedFilterList(s, i, feedID, channelID, scope)
+	case "clear":
+		h.handleFeedFilterClear(s, i, feedID, channelID, scope)
+	default:
+		h.respondError(s, i, fmt.Sprintf("❌ Unknown subcommand '%s'.", subcommand.Name))
+	}
+}
+
+// handleFeedFilterAdd handles /feed-filter add
+func (h *CommandHandler) handleFeedFilterAdd(s *discordgo.Session, i *discordgo.InteractionCreate, args map[string]*discordgo.ApplicationCommandInteractionDataOption, feedID, channelID, scope string) {
+	rule := filter.Rule{}
+	if opt, ok := args["action"]; ok {
+		rule.Action = opt.StringValue()
+	}
+	if opt, ok := args["type"]; ok {
+		rule.Type = opt.StringValue()
+	}
+	if opt, ok := args["value"]; ok {
+		rule.Value = opt.StringValue()
+	}
+
+	if err := rule.Validate(); err != nil {
+		h.respondError(s, i, fmt.Sprintf("❌ %s", err.Error()))
+		return
+	}
+
+	if err := h.feedRepo.AddFilter(feedID, channelID, rule); err != nil {
+		log.Printf("[FEED-FILTER] ERROR: Failed to add filter to %s: %v", feedID, err)
+		h.respondError(s, i, fmt.Sprintf("❌ Error adding filter: %v", err))
+		return
+	}
+
+	h.respondSuccess(s, i, fmt.Sprintf("✅ **Filter added** to %s:\n%s", scope, rule.String()))
+	log.Printf("[FEED-FILTER] Added filter to feed %s (channel: %q): %s", feedID, channelID, rule.String())
+}
+
+// handleFeedFilterRemove handles /feed-filter remove
+func (h *CommandHandler) handleFeedFilterRemove(s *discordgo.Session, i *discordgo.InteractionCreate, args map[string]*discordgo.ApplicationCommandInteractionDataOption, feedID, channelID, scope string) {
+	opt, ok := args["number"]
+	if !ok {
+		h.respondError(s, i, "❌ You need to specify the rule number.")
+		return
+	}
+	position := int(opt.IntValue())
+
+	if err := h.feedRepo.RemoveFilter(feedID, channelID, position); err != nil {
+		h.respondError(s, i, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	h.respondSuccess(s, i, fmt.Sprintf("✅ Filter #%d removed from %s.", position, scope))
+	log.Printf("[FEED-FILTER] Removed filter #%d from feed %s (channel: %q)", position, feedID, channelID)
+}
+
+// handleFeedFilterList handles /feed-filter list
+func (h *CommandHandler) handleFeedFilterList(s *discordgo.Session, i *discordgo.InteractionCreate, feedID, channelID, scope string) {
+	rules, err := h.feedRepo.GetFilters(feedID, channelID)
+	if err != nil {
+		log.Printf("[FEED-FILTER] ERROR: Failed to get filters for %s: %v", feedID, err)
+		h.respondError(s, i, "Error listing filters.")
+		return
+	}
+
+	if len(rules) == 0 {
+		h.respondError(s, i, fmt.Sprintf("ℹ️ No filters configured for %s.", scope))
+		return
+	}
+
+	response := fmt.Sprintf("🔎 **Filters for %s**\n\n", scope)
+	for idx, rule := range rules {
+		response += fmt.Sprintf("%d. %s\n", idx+1, rule.String())
+	}
+	response += "\nExclude rules always win; when include rules exist, an article must match at least one."
+
+	h.respondSuccess(s, i, response)
+}
+
+// handleFeedFilterClear handles /feed-filter clear
+func (h *CommandHandler) handleFeedFilterClear(s *discordgo.Session, i *discordgo.InteractionCreate, feedID, channelID, scope string) {
+	if err := h.feedRepo.ClearFilters(feedID, channelID); err != nil {
+		log.Printf("[FEED-FILTER] ERROR: Failed to clear filters for %s: %v", feedID, err)
+		h.respondError(s, i, "Error clearing filters.")
+		return
+	}
+
+	h.respondSuccess(s, i, fmt.Sprintf("✅ All filters removed from %s.", scope))
+	log.Printf("[FEED-FILTER] Cleared filters for feed %s (channel: %q)", feedID, channelID)
+}
+
+// optionsByName indexes command options by name, for commands with optional arguments
+func optionsByName(options []*discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	byName := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		byName[opt.Name] = opt
+	}
+	return byName
+}
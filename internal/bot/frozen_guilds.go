@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"log"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+)
+
+// withoutFrozenGuilds drops the channels of guilds frozen by the bot owners before posting.
+// channelGuild returns the recorded guild of a channel; guilds are only looked up when some
+// guild is frozen, and channels without a recorded guild are kept.
+func withoutFrozenGuilds(guildRepo storage.GuildRepository, channels []string, channelGuild func(channelID string) (string, error)) []string {
+	if guildRepo == nil || len(channels) == 0 {
+		return channels
+	}
+
+	frozenGuilds, err := guildRepo.GetFrozenGuilds()
+	if err != nil {
+		log.Printf("WARNING: Failed to get frozen guilds: %v", err)
+		return channels
+	}
+	if len(frozenGuilds) == 0 {
+		return channels
+	}
+	frozen := make(map[string]bool, len(frozenGuilds))
+	for _, guildID := range frozenGuilds {
+		frozen[guildID] = true
+	}
+
+	kept := make([]string, 0, len(channels))
+	for _, channelID := range channels {
+		guildID, err := channelGuild(channelID)
+		if err != nil {
+			log.Printf("WARNING: Failed to get the guild of channel %s: %v", channelID, err)
+		}
+		if guildID != "" && frozen[guildID] {
+			log.Printf("Skipping channel %s of frozen guild %s", channelID, guildID)
+			continue
+		}
+		kept = append(kept, channelID)
+	}
+	return kept
+}
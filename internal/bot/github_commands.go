@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/GustavoLR548/godot-news-bot/internal/github"
+	"github.com/GustavoLR548/godot-news-bot/internal/schedule"
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
 	"github.com/bwmarrin/discordgo"
 	"log"
 )
@@ -74,6 +76,20 @@ func (h *CommandHandler) handleRegisterRepo(s *discordgo.Session, i *discordgo.I
 		return
 	}
 
+	// Repositories belong to this server unless a bot owner adds them to the public catalog
+	catalog := false
+	if opt, ok := optionMap["catalog"]; ok {
+		catalog = opt.BoolValue()
+	}
+	ownerGuildID, message := h.registrationOwner(i, catalog)
+	if message == "" {
+		message = h.checkQuota(ownerGuildID, storage.QuotaRepos)
+	}
+	if message != "" {
+		h.followUpError(s, i, message)
+		return
+	}
+
 	// Check if repository already exists
 	exists, err := h.githubRepo.HasRepository(repoID)
 	if err != nil {
@@ -81,7 +97,8 @@ func (h *CommandHandler) handleRegisterRepo(s *discordgo.Session, i *discordgo.I
 		return
 	}
 	if exists {
-		h.followUpError(s, i, fmt.Sprintf("❌ Repository `%s` is already registered.", repoID))
+		// Identifiers are global; don't tell whether another server registered it
+		h.followUpError(s, i, fmt.Sprintf("❌ The repository identifier `%s` is already taken. Choose another one.", repoID))
 		return
 	}
 
@@ -92,6 +109,7 @@ func (h *CommandHandler) handleRegisterRepo(s *discordgo.Session, i *discordgo.I
 		Name:         repoName,
 		TargetBranch: branch,
 		AddedAt:      time.Now(),
+		OwnerGuildID: ownerGuildID,
 	}
 
 	if err := h.githubRepo.RegisterRepository(repo); err != nil {
@@ -99,13 +117,14 @@ func (h *CommandHandler) handleRegisterRepo(s *discordgo.Session, i *discordgo.I
 		return
 	}
 
-	message := fmt.Sprintf("✅ **Repository Registered**\n"+
+	message = fmt.Sprintf("✅ **Repository Registered**\n"+
 		"📦 **ID:** `%s`\n"+
 		"👤 **Owner:** `%s`\n"+
 		"📁 **Repo:** `%s`\n"+
-		"🌿 **Branch:** `%s`\n\n"+
+		"🌿 **Branch:** `%s`\n"+
+		"🌐 **Available to:** %s\n\n"+
 		"Use `/setup-repo-channel` to subscribe channels to PR updates.",
-		repoID, owner, repoName, branch)
+		repoID, owner, repoName, branch, availabilityDisplay(ownerGuildID))
 
 	h.followUpSuccess(s, i, message)
 }
@@ -135,14 +154,9 @@ func (h *CommandHandler) handleUnregisterRepo(s *discordgo.Session, i *discordgo
 
 	repoID := options[0].StringValue()
 
-	// Check if repository exists
-	exists, err := h.githubRepo.HasRepository(repoID)
-	if err != nil {
-		h.followUpError(s, i, fmt.Sprintf("❌ Error checking repository: %v", err))
-		return
-	}
-	if !exists {
-		h.followUpError(s, i, fmt.Sprintf("❌ Repository `%s` not found.", repoID))
+	// Only the owner server (or the bot owners, for catalog repositories) can remove a repository
+	if repo, message := h.lookupRepo(i, repoID, true); repo == nil {
+		h.followUpError(s, i, message)
 		return
 	}
 
@@ -200,6 +214,15 @@ func (h *CommandHandler) handleListRepos(s *discordgo.Session, i *discordgo.Inte
 		return
 	}
 
+	// Only catalog repositories and repositories of this server are listed
+	visible := repos[:0]
+	for _, repo := range repos {
+		if repo.VisibleTo(i.GuildID) {
+			visible = append(visible, repo)
+		}
+	}
+	repos = visible
+
 	if len(repos) == 0 {
 		h.followUpSuccess(s, i, "📦 No GitHub repositories registered yet.\n\nUse `/register-repo` to add one!")
 		return
@@ -213,10 +236,14 @@ func (h *CommandHandler) handleListRepos(s *discordgo.Session, i *discordgo.Inte
 		pendingCount, _ := h.githubRepo.GetPendingCount(repo.ID)
 		lastChecked, _ := h.githubRepo.GetLastChecked(repo.ID)
 
-		response.WriteString(fmt.Sprintf("**%s** (`%s/%s`)\n", repo.ID, repo.Owner, repo.Name))
+		response.WriteString(fmt.Sprintf("**%s** (`%s/%s`) %s\n", repo.ID, repo.Owner, repo.Name, ownershipLabel(repo.OwnerGuildID)))
 		response.WriteString(fmt.Sprintf("  🌿 Branch: `%s`\n", repo.TargetBranch))
 		response.WriteString(fmt.Sprintf("  📢 Channels: %d\n", len(channels)))
 		response.WriteString(fmt.Sprintf("  ⏳ Pending PRs: %d\n", pendingCount))
+		if scheduleTimes, _ := h.githubRepo.GetSchedule(repo.ID); len(scheduleTimes) > 0 {
+			response.WriteString(fmt.Sprintf("  ⏰ Schedule: %s (%s)\n", strings.Join(scheduleTimes, "; "), timezoneDisplay(repo.Timezone)))
+			response.WriteString(fmt.Sprintf("  ⏭️ Next check: %s\n", nextRunDisplay(scheduleTimes, repo.Timezone)))
+		}
 		if !lastChecked.IsZero() {
 			response.WriteString(fmt.Sprintf("  🕒 Last checked: <t:%d:R>\n", lastChecked.Unix()))
 		}
@@ -270,26 +297,27 @@ func (h *CommandHandler) handleSetupRepoChannel(s *discordgo.Session, i *discord
 	channelID := channelValue.ID
 	repoID := options[1].StringValue()
 
-	// Check if repository exists
-	exists, err := h.githubRepo.HasRepository(repoID)
-	if err != nil {
-		h.followUpError(s, i, fmt.Sprintf("❌ Error checking repository: %v", err))
-		return
-	}
-	if !exists {
-		h.followUpError(s, i, fmt.Sprintf("❌ Repository `%s` not found. Use `/register-repo` first.", repoID))
+	// The repository must be a catalog repository or belong to this server
+	repo, message := h.lookupRepo(i, repoID, false)
+	if repo == nil {
+		h.followUpError(s, i, message)
 		return
 	}
 
-	// Get repository details
-	repo, err := h.githubRepo.GetRepository(repoID)
+	// New channels count against the server's channel quota, enforced while subscribing
+	maxGuildChannels, err := h.guildChannelQuota(i.GuildID)
 	if err != nil {
-		h.followUpError(s, i, fmt.Sprintf("❌ Failed to get repository details: %v", err))
+		log.Printf("[SETUP-REPO-CHANNEL] ERROR: %v", err)
+		h.followUpError(s, i, "❌ Error checking this server's quota.")
 		return
 	}
 
 	// Add channel to repository
-	if err := h.githubRepo.AddRepoChannel(repoID, channelID); err != nil {
+	if err := h.githubRepo.AddRepoChannel(i.GuildID, repoID, channelID, maxGuildChannels); err != nil {
+		if message := quotaExceededMessage(err); message != "" {
+			h.followUpError(s, i, message)
+			return
+		}
 		h.followUpError(s, i, fmt.Sprintf("❌ Failed to setup channel: %v", err))
 		return
 	}
@@ -303,7 +331,7 @@ func (h *CommandHandler) handleSetupRepoChannel(s *discordgo.Session, i *discord
 		}
 	}
 
-	message := fmt.Sprintf("✅ **Channel Configured**\n"+
+	message = fmt.Sprintf("✅ **Channel Configured**\n"+
 		"📢 <#%s> will now receive PR summaries from:\n"+
 		"📦 **%s** (`%s/%s`)\n\n",
 		channelID, repo.ID, repo.Owner, repo.Name)
@@ -376,25 +404,14 @@ func (h *CommandHandler) handleScheduleRepo(s *discordgo.Session, i *discordgo.I
 	repoID := options[0].StringValue()
 	timesStr := options[1].StringValue()
 
-	// Check if repository exists
-	exists, err := h.githubRepo.HasRepository(repoID)
-	if err != nil {
-		h.followUpError(s, i, fmt.Sprintf("❌ Error checking repository: %v", err))
-		return
-	}
-	if !exists {
-		h.followUpError(s, i, fmt.Sprintf("❌ Repository `%s` not found. Use `/register-repo` first.", repoID))
+	// Check if repository exists and this server can change it
+	if repo, message := h.lookupRepo(i, repoID, true); repo == nil {
+		h.followUpError(s, i, message)
 		return
 	}
 
-	// Parse times
-	var times []string
-	if timesStr != "" {
-		times = strings.Split(timesStr, ",")
-		for i := range times {
-			times[i] = strings.TrimSpace(times[i])
-		}
-	}
+	// Parse schedule entries (";"-separated, or a comma-separated list of times)
+	times := schedule.SplitSpecs(timesStr)
 
 	// Validate schedule times
 	if err := validateScheduleTimes(times); err != nil {
@@ -402,18 +419,43 @@ func (h *CommandHandler) handleScheduleRepo(s *discordgo.Session, i *discordgo.I
 		return
 	}
 
+	// Optional timezone the times are interpreted in
+	var timezone *string
+	if opt, ok := optionsByName(options)["timezone"]; ok {
+		tz, err := parseTimezoneOption(opt.StringValue())
+		if err != nil {
+			h.followUpError(s, i, fmt.Sprintf("❌ %s", err.Error()))
+			return
+		}
+		timezone = &tz
+	}
+
 	// Set schedule
 	if err := h.githubRepo.SetSchedule(repoID, times); err != nil {
 		h.followUpError(s, i, fmt.Sprintf("❌ Failed to set schedule: %v", err))
 		return
 	}
 
+	if timezone != nil {
+		if err := h.githubRepo.SetTimezone(repoID, *timezone); err != nil {
+			h.followUpError(s, i, fmt.Sprintf("❌ Failed to set timezone: %v", err))
+			return
+		}
+	}
+
 	if len(times) > 0 {
+		repo, err := h.githubRepo.GetRepository(repoID)
+		if err != nil {
+			h.followUpError(s, i, fmt.Sprintf("❌ Failed to read repository: %v", err))
+			return
+		}
+
 		h.followUpSuccess(s, i, fmt.Sprintf("✅ **Schedule Updated**\n"+
 			"📦 Repository: `%s`\n"+
-			"⏰ Check times: %s\n\n"+
-			"The bot will check for new PRs at these times daily.",
-			repoID, strings.Join(times, ", ")))
+			"⏰ Schedule: %s\n"+
+			"🌍 Timezone: %s\n"+
+			"⏭️ Next check: %s",
+			repoID, strings.Join(times, "; "), timezoneDisplay(repo.Timezone), nextRunDisplay(times, repo.Timezone)))
 	} else {
 		h.followUpSuccess(s, i, fmt.Sprintf("✅ **Schedule Cleared**\n"+
 			"📦 Repository: `%s`\n"+
@@ -446,21 +488,10 @@ func (h *CommandHandler) handleUpdateRepo(s *discordgo.Session, i *discordgo.Int
 	}
 	repoID := options[0].StringValue()
 
-	// Verify repository exists
-	exists, err := h.githubRepo.HasRepository(repoID)
-	if err != nil {
-		h.respondError(s, i, fmt.Sprintf("❌ Error checking repository: %v", err))
-		return
-	}
-	if !exists {
-		h.respondError(s, i, fmt.Sprintf("❌ Repository `%s` not found.\n\nUse `/list-repos` to see available repositories.", repoID))
-		return
-	}
-
-	// Get repository details
-	repo, err := h.githubRepo.GetRepository(repoID)
-	if err != nil {
-		h.respondError(s, i, fmt.Sprintf("❌ Failed to get repository details: %v", err))
+	// Verify repository exists and is visible to this server
+	repo, message := h.lookupRepo(i, repoID, false)
+	if repo == nil {
+		h.respondError(s, i, message)
 		return
 	}
 
@@ -471,7 +502,7 @@ func (h *CommandHandler) handleUpdateRepo(s *discordgo.Session, i *discordgo.Int
 	}
 
 	// Send initial response
-	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: fmt.Sprintf("🔄 Checking for updates from **%s/%s**...", repo.Owner, repo.Name),
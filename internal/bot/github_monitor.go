@@ -2,14 +2,17 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/GustavoLR548/godot-news-bot/internal/ai"
 	"github.com/GustavoLR548/godot-news-bot/internal/github"
+	"github.com/GustavoLR548/godot-news-bot/internal/schedule"
 	"github.com/GustavoLR548/godot-news-bot/internal/storage"
 	"github.com/bwmarrin/discordgo"
 )
@@ -22,6 +25,21 @@ type GitHubMonitor struct {
 	summarizer     ai.PRSummarizer
 	checkInterval  time.Duration
 	batchThreshold int
+	repoAttempts   attemptTracker // in-process repository check attempts
+	leases         *storage.LeaseManager
+	leader         *leaderElection
+	delivery       *deliverer // records per-channel deliveries and retries failed posts
+	prompts        promptResolver
+	guildRepo      storage.GuildRepository // frozen guilds, nil when disabled
+
+	// Repository runs in progress (the monitoring loop and manual triggers); Stop cancels
+	// runCtx and waits for them, so their leases and records are released before the
+	// storage is closed
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+	runsMu     sync.Mutex
+	stopping   bool
+	runs       sync.WaitGroup
 }
 
 // NewGitHubMonitor creates a new GitHub monitor
@@ -49,7 +67,7 @@ func NewGitHubMonitor(
 		}
 	}
 
-	return &GitHubMonitor{
+	m := &GitHubMonitor{
 		session:        session,
 		githubClient:   githubClient,
 		githubRepo:     githubRepo,
@@ -57,10 +75,64 @@ func NewGitHubMonitor(
 		checkInterval:  checkInterval,
 		batchThreshold: batchThreshold,
 	}
+	m.runCtx, m.cancelRuns = context.WithCancel(context.Background())
+	m.delivery = &deliverer{
+		kind: deliveryKindGitHub,
+		send: m.sendEmbed,
+		subscriptions: func(channelID string) ([]string, error) {
+			return m.githubRepo.GetChannelRepos(channelID)
+		},
+		frozen: func(channelID string) bool {
+			return len(withoutFrozenGuilds(m.guildRepo, []string{channelID}, m.githubRepo.GetChannelGuild)) == 0
+		},
+		unsubscribe: m.unsubscribeChannel,
+		notify: func(channelID, message string) {
+			notifyGuildOwner(m.session, channelID, message)
+		},
+	}
+	return m
+}
+
+// SetLeaseManager coordinates this monitor with other bot instances sharing the
+// same Redis (call before Start): repositories are locked while processed and
+// only the elected leader runs the scheduler
+func (m *GitHubMonitor) SetLeaseManager(leases *storage.LeaseManager) {
+	m.leases = leases
+	m.leader = newLeaderElection(leases, "github-scheduler")
+}
+
+// SetDeliveryRepository enables per-channel delivery records and retries of failed posts
+func (m *GitHubMonitor) SetDeliveryRepository(deliveries storage.DeliveryRepository) {
+	m.delivery.deliveries = deliveries
+}
+
+// SetPromptRepository enables custom PR prompt templates per guild
+func (m *GitHubMonitor) SetPromptRepository(prompts storage.PromptRepository) {
+	m.prompts.repo = prompts
+}
+
+// SetPromptTemplates replaces the built-in prompt templates used when a guild has none
+func (m *GitHubMonitor) SetPromptTemplates(templates map[ai.PromptKind]*ai.PromptTemplate) {
+	m.prompts.configured = templates
+}
+
+// SetGuildRepository stops posts to channels of guilds frozen by the bot owners
+func (m *GitHubMonitor) SetGuildRepository(guildRepo storage.GuildRepository) {
+	m.guildRepo = guildRepo
 }
 
 // Start begins monitoring repositories
 func (m *GitHubMonitor) Start(ctx context.Context) {
+	if !m.beginRun() {
+		return
+	}
+	defer m.runs.Done()
+
+	// Stop ends the loop too, even if ctx is never cancelled
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(m.runCtx, cancel)()
+
 	log.Printf("[GITHUB-MONITOR] Starting with check interval: %v, batch threshold: %d", m.checkInterval, m.batchThreshold)
 
 	// Check every minute to see if any schedules match current time
@@ -68,25 +140,70 @@ func (m *GitHubMonitor) Start(ctx context.Context) {
 	defer ticker.Stop()
 
 	// Run initial check immediately (for repos without schedules)
-	m.checkAllRepositories(ctx, false)
+	if m.leader.isLeader() {
+		m.checkAllRepositories(ctx, false)
+	}
+	lastTick := time.Now()
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("[GITHUB-MONITOR] Stopping...")
 			return
-		case <-ticker.C:
-			// Get current time in HH:MM format
-			now := time.Now().Format("15:04")
-			m.checkScheduledRepositories(ctx, now)
+		case now := <-ticker.C:
+			// Standby instances only track time; a new leader catches up from each repo's last check
+			if m.leader.isLeader() {
+				m.checkScheduledRepositories(ctx, lastTick, now)
+				m.delivery.retryDue(now)
+			}
+			lastTick = now
 		}
 	}
 }
 
+// Stop ends the monitoring loop and the manual runs, waits for the repositories being
+// processed, then hands over scheduler leadership so a standby instance can take over
+// right away
+func (m *GitHubMonitor) Stop() {
+	m.runsMu.Lock()
+	m.stopping = true
+	m.runsMu.Unlock()
+
+	log.Println("[GITHUB-MONITOR] Waiting for repositories being processed...")
+	m.cancelRuns()
+	m.runs.Wait()
+	m.leader.resign()
+}
+
+// beginRun registers a repository run Stop has to wait for; it returns false once the
+// monitor is stopping. Call runs.Done when the run ends.
+func (m *GitHubMonitor) beginRun() bool {
+	m.runsMu.Lock()
+	defer m.runsMu.Unlock()
+
+	if m.stopping {
+		return false
+	}
+	m.runs.Add(1)
+	return true
+}
+
+// goRun runs fn in the background as a run Stop waits for, with a context Stop cancels
+func (m *GitHubMonitor) goRun(fn func(ctx context.Context)) {
+	if !m.beginRun() {
+		log.Println("[GITHUB-MONITOR] Stopping, manual run skipped")
+		return
+	}
+	go func() {
+		defer m.runs.Done()
+		fn(m.runCtx)
+	}()
+}
+
 // CheckAllRepositoriesNow forces an immediate check of all repositories (for manual triggers)
 func (m *GitHubMonitor) CheckAllRepositoriesNow() {
 	log.Println("[GITHUB-MONITOR] Manual check triggered for all repositories")
-	go m.checkAllRepositories(context.Background(), false)
+	m.goRun(func(ctx context.Context) { m.checkAllRepositories(ctx, false) })
 }
 
 // CheckRepositoryNow forces an immediate check of a specific repository (for manual triggers)
@@ -99,12 +216,17 @@ func (m *GitHubMonitor) CheckRepositoryNow(repoID string) {
 		return
 	}
 	
-	go m.checkRepository(context.Background(), *repo)
+	m.goRun(func(ctx context.Context) { m.runRepository(ctx, *repo) })
 }
 
 // ProcessPendingPRsNow forces immediate processing of pending PRs for a repository
 func (m *GitHubMonitor) ProcessPendingPRsNow(repoID string) {
 	log.Printf("[GITHUB-MONITOR] Manual processing of pending PRs triggered for repository: %s", repoID)
+	if !m.beginRun() {
+		log.Printf("[GITHUB-MONITOR] Stopping, skipping pending PRs of %s", repoID)
+		return
+	}
+	defer m.runs.Done()
 	
 	repo, err := m.githubRepo.GetRepository(repoID)
 	if err != nil {
@@ -124,12 +246,24 @@ func (m *GitHubMonitor) ProcessPendingPRsNow(repoID string) {
 		return
 	}
 	
+	ctx, release, err := acquireProcessingLease(m.runCtx, m.leases, "repo:"+repoID)
+	if errors.Is(err, errLeaseHeld) {
+		log.Printf("[GITHUB-MONITOR] Repository %s is being processed by another instance, skipping", repoID)
+		return
+	}
+	if err != nil {
+		log.Printf("[GITHUB-MONITOR] ERROR: Failed to lock repository %s: %v", repoID, err)
+		return
+	}
+	defer release()
+
 	log.Printf("[GITHUB-MONITOR] Processing %d pending PRs for %s", pendingCount, repoID)
-	m.processBatch(context.Background(), *repo)
+	m.processBatch(ctx, *repo)
 }
 
-// checkScheduledRepositories checks repos whose schedules match the current time
-func (m *GitHubMonitor) checkScheduledRepositories(ctx context.Context, currentTime string) {
+// checkScheduledRepositories checks repos whose next scheduled run falls in the window
+// (since, now], evaluated in each repository's timezone
+func (m *GitHubMonitor) checkScheduledRepositories(ctx context.Context, since, now time.Time) {
 	repos, err := m.githubRepo.GetAllRepositories()
 	if err != nil {
 		log.Printf("[GITHUB-MONITOR] ERROR: Failed to get repositories: %v", err)
@@ -142,30 +276,70 @@ func (m *GitHubMonitor) checkScheduledRepositories(ctx context.Context, currentT
 			return
 		default:
 			// Get schedule for this repo
-			schedule, _ := m.githubRepo.GetSchedule(repo.ID)
+			times, _ := m.githubRepo.GetSchedule(repo.ID)
+
+			// Last successful check (also used as the PR lookback)
+			lastChecked, err := m.githubRepo.GetLastChecked(repo.ID)
+			if err != nil {
+				log.Printf("[GITHUB-MONITOR] WARNING: Failed to get last checked time for %s: %v", repo.ID, err)
+			}
 			
 			// If no schedule, check based on interval
-			if len(schedule) == 0 {
+			if len(times) == 0 {
 				// Check if enough time has passed since last check
-				lastChecked, _ := m.githubRepo.GetLastChecked(repo.ID)
-				if time.Since(lastChecked) >= m.checkInterval {
-					m.checkRepository(ctx, repo)
+				if time.Since(lastChecked) >= m.checkInterval && time.Since(m.repoAttempts.last(repo.ID)) >= m.checkInterval {
+					m.runRepository(ctx, repo)
 				}
 				continue
 			}
 			
-			// Check if current time matches any scheduled time
-			for _, scheduledTime := range schedule {
-				if scheduledTime == currentTime {
-					log.Printf("[GITHUB-MONITOR] Scheduled check for %s/%s at %s", repo.Owner, repo.Name, currentTime)
-					m.checkRepository(ctx, repo)
-					break
-				}
+			specs, err := schedule.ParseAll(times)
+			if err != nil {
+				log.Printf("[GITHUB-MONITOR] WARNING: Invalid schedule for %s/%s, skipping: %v", repo.Owner, repo.Name, err)
+				continue
 			}
+
+			loc, err := schedule.LoadLocation(repo.Timezone)
+			if err != nil {
+				log.Printf("[GITHUB-MONITOR] WARNING: Invalid timezone for %s/%s, using local time: %v", repo.Owner, repo.Name, err)
+				loc = time.Local
+			}
+
+			// Check if a scheduled run fell in the window since the last successful check,
+			// so slots missed during downtime or by a delayed tick run once
+			windowStart := scheduleWindowStart(lastChecked, m.repoAttempts.last(repo.ID), since, now)
+			if !schedule.Due(specs, loc, windowStart, now) {
+				continue
+			}
+			if schedule.Due(specs, loc, since, now) {
+				log.Printf("[GITHUB-MONITOR] Scheduled check for %s/%s at %s (%s)", repo.Owner, repo.Name, now.In(loc).Format("15:04"), loc)
+			} else {
+				log.Printf("[GITHUB-MONITOR] Catching up missed scheduled check for %s/%s (last checked: %s)", repo.Owner, repo.Name, lastChecked.Format(time.RFC3339))
+			}
+			m.runRepository(ctx, repo)
 		}
 	}
 }
 
+// runRepository checks a repository under its lease and records the attempt, so a failing
+// repository is retried at its next slot rather than on every tick. Repositories being
+// processed by another instance are skipped.
+func (m *GitHubMonitor) runRepository(ctx context.Context, repo github.Repository) {
+	ctx, release, err := acquireProcessingLease(ctx, m.leases, "repo:"+repo.ID)
+	if errors.Is(err, errLeaseHeld) {
+		log.Printf("[GITHUB-MONITOR] Repository %s/%s is being processed by another instance, skipping", repo.Owner, repo.Name)
+		return
+	}
+	if err != nil {
+		log.Printf("[GITHUB-MONITOR] ERROR: Failed to lock repository %s/%s: %v", repo.Owner, repo.Name, err)
+		return
+	}
+	defer release()
+
+	m.repoAttempts.record(repo.ID, time.Now())
+	m.checkRepository(ctx, repo)
+}
+
 // checkAllRepositories checks all registered repositories (legacy method for interval-based)
 func (m *GitHubMonitor) checkAllRepositories(ctx context.Context, respectSchedules bool) {
 	repos, err := m.githubRepo.GetAllRepositories()
@@ -190,7 +364,7 @@ func (m *GitHubMonitor) checkAllRepositories(ctx context.Context, respectSchedul
 			if respectSchedules && len(repo.Schedule) > 0 {
 				continue
 			}
-			m.checkRepository(ctx, repo)
+			m.runRepository(ctx, repo)
 		}
 	}
 }
@@ -369,6 +543,7 @@ func (m *GitHubMonitor) processBatch(ctx context.Context, repo github.Repository
 		log.Printf("[GITHUB-MONITOR] ERROR: Failed to get channels for %s: %v", repo.ID, err)
 		return
 	}
+	channels = withoutFrozenGuilds(m.guildRepo, channels, m.githubRepo.GetChannelGuild)
 
 	if len(channels) == 0 {
 		log.Printf("[GITHUB-MONITOR] No channels subscribed to %s yet, keeping %d PRs in queue for later", repo.ID, len(prs))
@@ -377,14 +552,27 @@ func (m *GitHubMonitor) processBatch(ctx context.Context, repo github.Repository
 
 	log.Printf("[GITHUB-MONITOR] Posting summary to %d channels", len(channels))
 
-	// Group channels by language for efficient AI generation
+	// Group channels by prompt template and language for efficient AI generation
 	channelsByLang := make(map[string][]string)
+	groups := newPromptGroups()
+	prompts := m.prompts.lookup(ai.PromptPRBatch, "")
 	guildLanguageCache := make(map[string]string)
 
 	for _, channelID := range channels {
+		// The guild is looked up upfront only when it may have its own prompt template
+		guildID := ""
+		if prompts.needsGuild() {
+			if channel, err := m.session.Channel(channelID); err == nil {
+				guildID = channel.GuildID
+			} else {
+				log.Printf("[GITHUB-MONITOR] WARNING: Failed to get channel info for %s: %v", channelID, err)
+			}
+		}
+
 		// Detect language for this channel
-		language := m.detectChannelLanguage(channelID, guildLanguageCache)
+		language := m.detectChannelLanguage(channelID, guildID, guildLanguageCache)
 		channelsByLang[language] = append(channelsByLang[language], channelID)
+		groups.add(prompts.template(guildID), ai.StyleStandard, language, channelID)
 		log.Printf("[GITHUB-MONITOR] Channel %s will receive summary in %s", channelID, language)
 	}
 
@@ -418,22 +606,53 @@ func (m *GitHubMonitor) processBatch(ctx context.Context, repo github.Repository
 
 	// Generate summary once per language and post to all channels in that language
 	repoName := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+	itemKey := prBatchItemKey(repo.ID, prs)
+	totalSuccess := 0
+
+	for _, group := range groups.order {
+		totalSuccess += m.postBatchSummaries(ctx, repo, repoName, prs, itemKey, group.channelsByLanguage, group.prompt)
+	}
+
+	log.Printf("[GITHUB-MONITOR] Posted summary to %d/%d channels total", totalSuccess, len(channels))
+
+	// Remove only the PRs we processed from the queue
+	if err := m.githubRepo.RemoveFromPendingQueue(repo.ID, len(prs)); err != nil {
+		log.Printf("[GITHUB-MONITOR] ERROR: Failed to remove processed PRs from queue: %v", err)
+	} else {
+		log.Printf("[GITHUB-MONITOR] Removed %d processed PRs from queue", len(prs))
+	}
+}
+
+// postBatchSummaries summarizes a PR batch in the languages of the channels with the prompt
+// template and posts it, returning how many channels received it
+func (m *GitHubMonitor) postBatchSummaries(ctx context.Context, repo github.Repository, repoName string, prs []github.PullRequest, itemKey string, channelsByLang map[string][]string, promptTemplate *ai.PromptTemplate) int {
 	totalSuccess := 0
 
+	// Skip channels that already received this batch (e.g. before a restart interrupted the posting)
+	pendingByLang := make(map[string][]string, len(channelsByLang))
 	for language, langChannels := range channelsByLang {
-		log.Printf("[GITHUB-MONITOR] Generating %s summary for %d PRs, %d channels", language, len(prs), len(langChannels))
+		langChannels = m.delivery.undelivered(itemKey, langChannels)
+		if len(langChannels) == 0 {
+			log.Printf("[GITHUB-MONITOR] %s summary already posted to every channel, skipping", language)
+			continue
+		}
+		pendingByLang[language] = langChannels
+	}
 
-		// Generate summary using AI
-		summaryText, err := m.summarizer.SummarizePRBatch(ctx, repoName, prs, language)
-		if err != nil {
-			log.Printf("[GITHUB-MONITOR] ERROR: Failed to generate %s summary: %v", language, err)
+	summaries := m.summarizeInLanguages(ctx, repoName, prs, getLanguageList(pendingByLang), promptTemplate)
+
+	for language, langChannels := range pendingByLang {
+		summaryText, ok := summaries[language]
+		if !ok {
 			continue
 		}
 
-		// Post to all channels in this language group
+		// Post to all channels in this language group; failed posts are queued for retry
+		embed := m.summaryEmbed(summaryText, repo, len(prs), language)
 		successCount := 0
 		for _, channelID := range langChannels {
-			if err := m.postSummaryToChannel(channelID, summaryText, repo, len(prs), language); err != nil {
+			log.Printf("[GITHUB-MONITOR] Posting %s summary to channel %s", language, channelID)
+			if err := m.delivery.deliver(itemKey, repo.ID, channelID, embed); err != nil {
 				log.Printf("[GITHUB-MONITOR] ERROR: Failed to post to channel %s: %v", channelID, err)
 				continue
 			}
@@ -443,19 +662,45 @@ func (m *GitHubMonitor) processBatch(ctx context.Context, repo github.Repository
 		log.Printf("[GITHUB-MONITOR] Posted %s summary to %d/%d channels", language, successCount, len(langChannels))
 		totalSuccess += successCount
 	}
+	return totalSuccess
+}
 
-	log.Printf("[GITHUB-MONITOR] Posted summary to %d/%d channels total", totalSuccess, len(channels))
+// summarizeInLanguages generates the summary of a PR batch in every language, with a single
+// request when the summarizer supports it. Languages missing from that response fall back to
+// one request each; languages that fail altogether are left out.
+func (m *GitHubMonitor) summarizeInLanguages(ctx context.Context, repoName string, prs []github.PullRequest, languages []string, promptTemplate *ai.PromptTemplate) map[string]string {
+	summaries := make(map[string]string, len(languages))
 
-	// Remove only the PRs we processed from the queue
-	if err := m.githubRepo.RemoveFromPendingQueue(repo.ID, len(prs)); err != nil {
-		log.Printf("[GITHUB-MONITOR] ERROR: Failed to remove processed PRs from queue: %v", err)
-	} else {
-		log.Printf("[GITHUB-MONITOR] Removed %d processed PRs from queue", len(prs))
+	if multi, ok := m.summarizer.(ai.MultiLanguagePRSummarizer); ok && len(languages) > 1 {
+		log.Printf("[GITHUB-MONITOR] Generating summary for %d PRs in %d languages with one request: %v", len(prs), len(languages), languages)
+		generated, err := multi.SummarizePRBatchInLanguages(ctx, repoName, prs, languages, promptTemplate)
+		if err != nil {
+			log.Printf("[GITHUB-MONITOR] ERROR: Failed to generate summaries in %v: %v", languages, err)
+		}
+		for language, summaryText := range generated {
+			summaries[language] = summaryText
+		}
+	}
+
+	for _, language := range languages {
+		if _, ok := summaries[language]; ok {
+			continue
+		}
+
+		log.Printf("[GITHUB-MONITOR] Generating %s summary for %d PRs", language, len(prs))
+		summaryText, err := m.summarizer.SummarizePRBatch(ctx, repoName, prs, language, promptTemplate)
+		if err != nil {
+			log.Printf("[GITHUB-MONITOR] ERROR: Failed to generate %s summary: %v", language, err)
+			continue
+		}
+		summaries[language] = summaryText
 	}
+	return summaries
 }
 
-// detectChannelLanguage detects the language for a channel using the same hierarchy as RSS feeds
-func (m *GitHubMonitor) detectChannelLanguage(channelID string, guildLanguageCache map[string]string) string {
+// detectChannelLanguage detects the language for a channel using the same hierarchy as RSS feeds.
+// guildID may be empty when the channel's guild wasn't looked up yet.
+func (m *GitHubMonitor) detectChannelLanguage(channelID, guildID string, guildLanguageCache map[string]string) string {
 	// Try to get channel-specific language
 	channelLang, err := m.githubRepo.GetChannelLanguage(channelID)
 	if err == nil && channelLang != "" {
@@ -463,17 +708,21 @@ func (m *GitHubMonitor) detectChannelLanguage(channelID string, guildLanguageCac
 	}
 
 	// Try to get guild language
-	channel, err := m.session.Channel(channelID)
-	if err == nil && channel.GuildID != "" {
+	if guildID == "" {
+		if channel, err := m.session.Channel(channelID); err == nil {
+			guildID = channel.GuildID
+		}
+	}
+	if guildID != "" {
 		// Check cache first
-		if cachedLang, ok := guildLanguageCache[channel.GuildID]; ok {
+		if cachedLang, ok := guildLanguageCache[guildID]; ok {
 			return cachedLang
 		}
 
 		// Fetch from storage
-		guildLang, err := m.githubRepo.GetGuildLanguage(channel.GuildID)
+		guildLang, err := m.githubRepo.GetGuildLanguage(guildID)
 		if err == nil && guildLang != "" {
-			guildLanguageCache[channel.GuildID] = guildLang
+			guildLanguageCache[guildID] = guildLang
 			return guildLang
 		}
 	}
@@ -482,8 +731,8 @@ func (m *GitHubMonitor) detectChannelLanguage(channelID string, guildLanguageCac
 	return "en"
 }
 
-// postSummaryToChannel posts a PR summary to a Discord channel
-func (m *GitHubMonitor) postSummaryToChannel(channelID string, summaryText string, repo github.Repository, prCount int, language string) error {
+// summaryEmbed builds the Discord embed of a PR summary
+func (m *GitHubMonitor) summaryEmbed(summaryText string, repo github.Repository, prCount int, language string) *discordgo.MessageEmbed {
 	// Localize title and footer based on language
 	var title, footerText string
 	langInfo := ai.GetLanguageInfo(language)
@@ -509,7 +758,7 @@ func (m *GitHubMonitor) postSummaryToChannel(channelID string, summaryText strin
 		footerText = fmt.Sprintf("Summarized %d merged PRs from %s branch", prCount, repo.TargetBranch)
 	}
 	
-	log.Printf("[GITHUB-MONITOR] Posting summary in %s (%s) to channel %s", langInfo.Name, language, channelID)
+	log.Printf("[GITHUB-MONITOR] Building summary embed in %s (%s)", langInfo.Name, language)
 	
 	// Discord embed description has a 6000 character limit
 	const maxEmbedDescriptionLength = 6000
@@ -519,7 +768,7 @@ func (m *GitHubMonitor) postSummaryToChannel(channelID string, summaryText strin
 		summaryText = summaryText[:maxEmbedDescriptionLength-100] + "\n\n...\n\n⚠️ Summary truncated due to length. Check GitHub for full details."
 	}
 	
-	embed := &discordgo.MessageEmbed{
+	return &discordgo.MessageEmbed{
 		Title:       title,
 		Description: summaryText,
 		Color:       0x6E5494, // GitHub purple
@@ -529,7 +778,34 @@ func (m *GitHubMonitor) postSummaryToChannel(channelID string, summaryText strin
 		Timestamp: time.Now().Format(time.RFC3339),
 		URL:       fmt.Sprintf("https://github.com/%s/%s/pulls?q=is:pr+is:merged", repo.Owner, repo.Name),
 	}
+}
+
+// sendEmbed sends an embed message to a specific channel
+func (m *GitHubMonitor) sendEmbed(channelID string, embed *discordgo.MessageEmbed) error {
+	if _, err := m.session.ChannelMessageSendEmbed(channelID, embed); err != nil {
+		return fmt.Errorf("failed to send message to channel %s: %w", channelID, err)
+	}
+	return nil
+}
+
+// unsubscribeChannel removes a channel from every repository it is subscribed to and returns the repository IDs
+func (m *GitHubMonitor) unsubscribeChannel(channelID string) ([]string, error) {
+	repoIDs, err := m.githubRepo.GetChannelRepos(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel repositories: %w", err)
+	}
+	for _, repoID := range repoIDs {
+		if err := m.githubRepo.RemoveRepoChannel(repoID, channelID); err != nil {
+			return nil, fmt.Errorf("failed to remove channel from repository %s: %w", repoID, err)
+		}
+	}
+	return repoIDs, nil
+}
 
-	_, err := m.session.ChannelMessageSendEmbed(channelID, embed)
-	return err
+// prBatchItemKey identifies a batch of PRs in delivery records
+func prBatchItemKey(repoID string, prs []github.PullRequest) string {
+	if len(prs) == 0 {
+		return fmt.Sprintf("%s:%s:", deliveryKindGitHub, repoID)
+	}
+	return fmt.Sprintf("%s:%s:%d-%d", deliveryKindGitHub, repoID, prs[0].Number, prs[len(prs)-1].Number)
 }
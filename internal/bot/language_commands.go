@@ -4,11 +4,12 @@ import (
 "fmt"
 "log"
 
+"github.com/GustavoLR548/godot-news-bot/internal/ai"
 "github.com/bwmarrin/discordgo"
 )
 
 // Language Configuration Commands
-// This file contains language setting command handlers
+// This file contains language and summary style setting command handlers
 func (h *CommandHandler) handleSetLanguage(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Printf("[SET-LANGUAGE] Command triggered by user %s in guild %s", i.Member.User.ID, i.GuildID)
 
@@ -138,6 +139,81 @@ func (h *CommandHandler) handleSetChannelLanguage(s *discordgo.Session, i *disco
 	h.followUpSuccess(s, i, fmt.Sprintf("✅ Channel <#%s> language set to: %s %s", channelID, languageFlag, languageCode))
 }
 
+// handleSetChannelStyle handles the /set-channel-style command
+func (h *CommandHandler) handleSetChannelStyle(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	log.Printf("[SET-CHANNEL-STYLE] Command triggered in guild %s", i.GuildID)
+
+	// Defer response immediately
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("[SET-CHANNEL-STYLE] ERROR: Failed to send deferred response: %v", err)
+		return
+	}
+
+	// Check if command was used in a guild
+	if i.GuildID == "" {
+		h.followUpError(s, i, "This command can only be used in a server.")
+		return
+	}
+
+	// Check permissions
+	if i.Member == nil || !h.hasManageServerPermission(i.Member) {
+		h.followUpError(s, i, "❌ You need the **Manage Server** permission to use this command.")
+		return
+	}
+
+	// Get parameters
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		h.followUpError(s, i, "❌ You need to specify a channel.")
+		return
+	}
+
+	channelValue := options[0].ChannelValue(s)
+	if channelValue == nil {
+		h.followUpError(s, i, "❌ Invalid channel.")
+		return
+	}
+
+	channelID := channelValue.ID
+
+	// Verify channel is in the same guild
+	if channelValue.GuildID != i.GuildID {
+		h.followUpError(s, i, "❌ Channel must be in this server.")
+		return
+	}
+
+	// Check if style is provided
+	if len(options) < 2 {
+		// Remove channel style (use the standard summary)
+		if err := h.channelRepo.SetChannelStyle(channelID, ""); err != nil {
+			log.Printf("[SET-CHANNEL-STYLE] ERROR: Failed to clear channel style: %v", err)
+			h.followUpError(s, i, fmt.Sprintf("❌ Error clearing channel style: %v", err))
+			return
+		}
+		h.followUpSuccess(s, i, fmt.Sprintf("✅ Channel <#%s> will now get standard summaries.", channelID))
+		return
+	}
+
+	style, err := ai.ParseSummaryStyle(options[1].StringValue())
+	if err != nil {
+		h.followUpError(s, i, fmt.Sprintf("❌ %s", err.Error()))
+		return
+	}
+	log.Printf("[SET-CHANNEL-STYLE] Setting channel %s summary style to: %s", channelID, style)
+
+	// Save channel summary style
+	if err := h.channelRepo.SetChannelStyle(channelID, string(style)); err != nil {
+		log.Printf("[SET-CHANNEL-STYLE] ERROR: Failed to save style: %v", err)
+		h.followUpError(s, i, fmt.Sprintf("❌ Error saving channel style: %v", err))
+		return
+	}
+
+	h.followUpSuccess(s, i, fmt.Sprintf("✅ Channel <#%s> summary style set to: **%s**\n%s.", channelID, style, style.Instructions()))
+}
+
 // getLanguageFlag returns the emoji flag for a language code
 func getLanguageFlag(code string) string {
 	flags := map[string]string{
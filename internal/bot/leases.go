@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+)
+
+const (
+	// processingLeaseTTL is how long a feed or repository stays locked if its instance dies mid-run
+	processingLeaseTTL = 2 * time.Minute
+	// leaderLeaseTTL is how long a scheduler stays leader if its instance dies
+	leaderLeaseTTL = 3 * time.Minute
+)
+
+// errLeaseHeld is returned when another instance is processing the same feed or repository
+var errLeaseHeld = errors.New("another instance is processing it")
+
+// acquireProcessingLease locks a feed or repository across bot instances for the
+// duration of a run. The returned context is cancelled if the lease is lost, and
+// release must be called once the run is done. A nil lease manager disables locking.
+func acquireProcessingLease(ctx context.Context, leases *storage.LeaseManager, name string) (context.Context, func(), error) {
+	if leases == nil {
+		return ctx, func() {}, nil
+	}
+
+	lease, err := leases.TryAcquire(name, processingLeaseTTL)
+	if errors.Is(err, storage.ErrLeaseHeld) {
+		return nil, nil, errLeaseHeld
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	lease.KeepAlive()
+
+	leaseCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-lease.Lost():
+			log.Printf("[LEASE] WARNING: Lost lease %s, aborting run", name)
+			cancel()
+		case <-leaseCtx.Done():
+		}
+	}()
+
+	release := func() {
+		cancel()
+		if err := lease.Release(); err != nil {
+			log.Printf("[LEASE] WARNING: %v", err)
+		}
+	}
+	return leaseCtx, release, nil
+}
+
+// leaderElection makes sure only one bot instance runs a scheduler at a time.
+// The leader keeps its lease alive in the background; other instances retry on
+// every tick and take over once the leader stops renewing.
+type leaderElection struct {
+	leases *storage.LeaseManager
+	name   string
+
+	mu       sync.Mutex
+	lease    *storage.Lease
+	resigned bool
+}
+
+// newLeaderElection creates an election for the named scheduler. A nil lease
+// manager makes this instance the leader unconditionally.
+func newLeaderElection(leases *storage.LeaseManager, name string) *leaderElection {
+	return &leaderElection{
+		leases: leases,
+		name:   "leader:" + name,
+	}
+}
+
+// isLeader reports whether this instance currently leads, trying to become leader if no one does
+func (e *leaderElection) isLeader() bool {
+	if e == nil || e.leases == nil {
+		return true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.resigned {
+		return false
+	}
+
+	if e.lease != nil {
+		select {
+		case <-e.lease.Lost():
+			log.Printf("[LEADER] Lost leadership of %s", e.name)
+			e.lease = nil
+		default:
+			return true
+		}
+	}
+
+	lease, err := e.leases.TryAcquire(e.name, leaderLeaseTTL)
+	if errors.Is(err, storage.ErrLeaseHeld) {
+		return false
+	}
+	if err != nil {
+		log.Printf("[LEADER] WARNING: Failed to acquire leadership of %s: %v", e.name, err)
+		return false
+	}
+
+	lease.KeepAlive()
+	e.lease = lease
+	log.Printf("[LEADER] Acquired leadership of %s", e.name)
+	return true
+}
+
+// resign gives up leadership so a standby instance can take over right away
+func (e *leaderElection) resign() {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.resigned = true
+	if e.lease == nil {
+		return
+	}
+	if err := e.lease.Release(); err != nil {
+		log.Printf("[LEADER] WARNING: Failed to resign leadership of %s: %v", e.name, err)
+	} else {
+		log.Printf("[LEADER] Resigned leadership of %s", e.name)
+	}
+	e.lease = nil
+}
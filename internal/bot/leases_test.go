@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestLeases(t *testing.T) (*miniredis.Miniredis, *storage.LeaseManager, *storage.LeaseManager) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return mr, storage.NewLeaseManager(client, "instance-a"), storage.NewLeaseManager(client, "instance-b")
+}
+
+func TestLeaderElection_SingleLeader(t *testing.T) {
+	_, leasesA, leasesB := setupTestLeases(t)
+	electionA := newLeaderElection(leasesA, "rss-scheduler")
+	electionB := newLeaderElection(leasesB, "rss-scheduler")
+
+	assert.True(t, electionA.isLeader())
+	assert.False(t, electionB.isLeader())
+
+	// Leadership is kept on later ticks
+	assert.True(t, electionA.isLeader())
+	assert.False(t, electionB.isLeader())
+
+	// Resigning hands leadership over to the standby
+	electionA.resign()
+	assert.False(t, electionA.isLeader())
+	assert.True(t, electionB.isLeader())
+	electionB.resign()
+}
+
+func TestLeaderElection_TakesOverExpiredLeader(t *testing.T) {
+	mr, leasesA, leasesB := setupTestLeases(t)
+	electionA := newLeaderElection(leasesA, "github-scheduler")
+	electionB := newLeaderElection(leasesB, "github-scheduler")
+
+	require.True(t, electionA.isLeader())
+
+	// The leader stops renewing (e.g. it crashed) and its lease expires
+	mr.FastForward(leaderLeaseTTL + time.Second)
+	assert.True(t, electionB.isLeader())
+	electionB.resign()
+}
+
+func TestLeaderElection_WithoutLeaseManager(t *testing.T) {
+	assert.True(t, newLeaderElection(nil, "rss-scheduler").isLeader())
+
+	var election *leaderElection
+	assert.True(t, election.isLeader())
+	election.resign()
+}
+
+func TestAcquireProcessingLease(t *testing.T) {
+	_, leasesA, leasesB := setupTestLeases(t)
+
+	ctx, release, err := acquireProcessingLease(context.Background(), leasesA, "feed:godot")
+	require.NoError(t, err)
+	assert.NoError(t, ctx.Err())
+
+	// Another instance can't process the same feed at the same time
+	_, _, err = acquireProcessingLease(context.Background(), leasesB, "feed:godot")
+	assert.ErrorIs(t, err, errLeaseHeld)
+
+	release()
+	assert.Error(t, ctx.Err())
+
+	_, releaseB, err := acquireProcessingLease(context.Background(), leasesB, "feed:godot")
+	require.NoError(t, err)
+	releaseB()
+}
+
+func TestAcquireProcessingLease_WithoutLeaseManager(t *testing.T) {
+	ctx, release, err := acquireProcessingLease(context.Background(), nil, "feed:godot")
+	require.NoError(t, err)
+	assert.NoError(t, ctx.Err())
+	release()
+}
+
+func TestBot_RunFeedSkipsFeedLockedByAnotherInstance(t *testing.T) {
+	_, leasesA, leasesB := setupTestLeases(t)
+
+	_, release, err := acquireProcessingLease(context.Background(), leasesA, "feed:godot-official")
+	require.NoError(t, err)
+	defer release()
+
+	bot := NewBot(nil, nil, nil, nil, nil, nil, time.Minute)
+	bot.SetLeaseManager(leasesB)
+
+	err = bot.runFeed(&storage.RSSFeed{ID: "godot-official"})
+	assert.ErrorIs(t, err, errLeaseHeld)
+	assert.True(t, bot.feedAttempts.last("godot-official").IsZero())
+}
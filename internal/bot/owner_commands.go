@@ -0,0 +1,487 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Bot Owner Commands
+// This file contains the /owner command group, restricted to the bot owners (OWNER_USER_IDS
+// and admins of the OWNER_GUILD_ID home guild). Guild admins manage their own server; the
+// bot owners manage the bot as a whole: which guilds use it, the AI rate limits, frozen and
+// purged guilds, quotas and stuck pending queues.
+
+// frozenGuildMessage is shown when a frozen guild runs a command
+const frozenGuildMessage = "❄️ This server has been frozen by the bot owners. Commands and posts are paused."
+
+// SetGuildRepository enables freezing and purging guilds with /owner
+func (h *CommandHandler) SetGuildRepository(guildRepo storage.GuildRepository) {
+	h.guildRepo = guildRepo
+}
+
+// SetDeliveryRepository lets guild purges drop the queued delivery retries of the guild's channels
+func (h *CommandHandler) SetDeliveryRepository(deliveries storage.DeliveryRepository) {
+	h.deliveries = deliveries
+}
+
+// isGuildFrozen reports whether the bot owners froze a guild
+func (h *CommandHandler) isGuildFrozen(guildID string) bool {
+	if h.guildRepo == nil || guildID == "" {
+		return false
+	}
+	frozen, err := h.guildRepo.IsGuildFrozen(guildID)
+	if err != nil {
+		log.Printf("[OWNER] ERROR: Failed to check whether guild %s is frozen: %v", guildID, err)
+		return false
+	}
+	return frozen
+}
+
+// ownerGuildOption returns the option naming the server an /owner subcommand applies to
+func ownerGuildOption(required bool) *discordgo.ApplicationCommandOption {
+	description := "Server ID (default: this server)"
+	if required {
+		description = "Server ID"
+	}
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "guild",
+		Description: description,
+		Required:    required,
+	}
+}
+
+// ownerTargetGuild returns the server an /owner subcommand applies to: the guild option, or
+// the server the command was run in
+func ownerTargetGuild(i *discordgo.InteractionCreate, args map[string]*discordgo.ApplicationCommandInteractionDataOption) (string, string) {
+	guildID := i.GuildID
+	if guildOpt, ok := args["guild"]; ok {
+		guildID = strings.TrimSpace(guildOpt.StringValue())
+	}
+	if guildID == "" {
+		return "", "❌ You need to specify a server ID outside of servers."
+	}
+	return guildID, ""
+}
+
+// ownerCommand returns the /owner command definition
+func ownerCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "owner",
+		Description: "Manage the bot across servers (Bot owners only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "guilds",
+				Description: "List the servers using the bot and their usage",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "ratelimit",
+				Description: "Show the AI rate limiting statistics",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "freeze",
+				Description: "Pause the commands and posts of a server",
+				Options:     []*discordgo.ApplicationCommandOption{ownerGuildOption(true)},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "unfreeze",
+				Description: "Resume the commands and posts of a frozen server",
+				Options:     []*discordgo.ApplicationCommandOption{ownerGuildOption(true)},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "purge",
+				Description: "Delete the subscriptions, feeds, repositories and settings of a server",
+				Options: []*discordgo.ApplicationCommandOption{
+					ownerGuildOption(true),
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "confirm",
+						Description: "Set to true to confirm; this can't be undone",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "clear-pending",
+				Description: "Empty the pending queue of a feed or repository",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "feed",
+						Description: "Feed identifier",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "repo",
+						Description: "Repository identifier",
+						Required:    false,
+					},
+				},
+			},
+			ownerQuotaCommandGroup(),
+		},
+	}
+}
+
+// handleOwner handles the /owner command and routes its subcommands
+func (h *CommandHandler) handleOwner(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !h.isBotOwner(i) {
+		h.respondError(s, i, "❌ Only the bot owners can use this command.")
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		h.respondError(s, i, "❌ You need to specify a subcommand.")
+		return
+	}
+
+	subcommand := options[0]
+	args := optionsByName(subcommand.Options)
+	log.Printf("[OWNER] /owner %s by user %s", subcommand.Name, interactionUserID(i))
+
+	switch subcommand.Name {
+	case "guilds":
+		h.respondSuccess(s, i, truncateMessage(h.guildsOverview(s.State.Guilds), 2000))
+	case "ratelimit":
+		h.handleOwnerRateLimit(s, i)
+	case "freeze", "unfreeze":
+		h.handleOwnerFreeze(s, i, args, subcommand.Name == "freeze")
+	case "purge":
+		h.handleOwnerPurge(s, i, args)
+	case "clear-pending":
+		h.handleOwnerClearPending(s, i, args)
+	case "quota":
+		h.handleOwnerQuota(s, i, subcommand)
+	default:
+		h.respondError(s, i, fmt.Sprintf("❌ Unknown subcommand '%s'.", subcommand.Name))
+	}
+}
+
+// guildsOverview lists the guilds the bot is in with their usage, frozen guilds first
+func (h *CommandHandler) guildsOverview(guilds []*discordgo.Guild) string {
+	if len(guilds) == 0 {
+		return "🌐 **The bot is not in any server.**"
+	}
+
+	sorted := make([]*discordgo.Guild, len(guilds))
+	copy(sorted, guilds)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].MemberCount > sorted[b].MemberCount
+	})
+
+	var lines []string
+	for _, guild := range sorted {
+		line := fmt.Sprintf("• **%s** (`%s`) - %d members", guild.Name, guild.ID, guild.MemberCount)
+		if usage, err := h.guildUsage(guild.ID); err == nil {
+			line += fmt.Sprintf(" - %d channels, %d feeds, %d repos", usage.Channels, usage.Feeds, usage.Repos)
+		} else {
+			log.Printf("[OWNER] ERROR: Failed to get usage of guild %s: %v", guild.ID, err)
+		}
+		if h.isGuildFrozen(guild.ID) {
+			line = "❄️ " + line
+		}
+		lines = append(lines, line)
+	}
+	return fmt.Sprintf("🌐 **Servers using the bot** (%d)\n\n%s", len(guilds), strings.Join(lines, "\n"))
+}
+
+// handleOwnerRateLimit handles /owner ratelimit
+func (h *CommandHandler) handleOwnerRateLimit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h.bot == nil {
+		h.respondError(s, i, "❌ Bot is not configured correctly.")
+		return
+	}
+
+	stats := ai.RateLimitStatistics(h.bot.aiSummarizer)
+	if len(stats) == 0 {
+		h.respondSuccess(s, i, "📈 **AI rate limits**\n\nNo AI backend with rate limits is configured.")
+		return
+	}
+	h.respondSuccess(s, i, "📈 **AI rate limits**\n\n"+rateLimitDisplay(stats))
+}
+
+// rateLimitDisplay formats the rate limiting statistics of each AI backend
+func rateLimitDisplay(stats []ai.BackendStatistics) string {
+	var blocks []string
+	for _, backend := range stats {
+		circuit := "🟢 closed"
+		if backend.Statistics.CircuitOpen {
+			circuit = "🔴 open"
+		}
+		blocks = append(blocks, fmt.Sprintf(
+			"**%s**\n• Current window: %d requests, %d tokens (resets in %ds)\n• Circuit breaker: %s\n• Total: %d requests, %d tokens, %d failures",
+			backend.Name,
+			backend.Statistics.CurrentWindowRequests,
+			backend.Statistics.CurrentWindowTokens,
+			int(backend.Statistics.WindowTimeRemaining.Seconds()),
+			circuit,
+			backend.Statistics.TotalRequests,
+			backend.Statistics.TotalTokens,
+			backend.Statistics.TotalFailures,
+		))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// handleOwnerFreeze handles /owner freeze and /owner unfreeze
+func (h *CommandHandler) handleOwnerFreeze(s *discordgo.Session, i *discordgo.InteractionCreate, args map[string]*discordgo.ApplicationCommandInteractionDataOption, frozen bool) {
+	if h.guildRepo == nil {
+		h.respondError(s, i, "❌ Freezing servers is not available.")
+		return
+	}
+
+	guildID, message := ownerTargetGuild(i, args)
+	if message != "" {
+		h.respondError(s, i, message)
+		return
+	}
+
+	if err := h.guildRepo.SetGuildFrozen(guildID, frozen); err != nil {
+		log.Printf("[OWNER] ERROR: Failed to update frozen state of guild %s: %v", guildID, err)
+		h.respondError(s, i, "❌ Error updating the server.")
+		return
+	}
+
+	if frozen {
+		h.respondSuccess(s, i, fmt.Sprintf("❄️ Server `%s` frozen. Its commands and posts are paused until `/owner unfreeze`.", guildID))
+		log.Printf("[OWNER] Froze guild %s", guildID)
+		return
+	}
+	h.respondSuccess(s, i, fmt.Sprintf("✅ Server `%s` unfrozen.", guildID))
+	log.Printf("[OWNER] Unfroze guild %s", guildID)
+}
+
+// handleOwnerPurge handles /owner purge
+func (h *CommandHandler) handleOwnerPurge(s *discordgo.Session, i *discordgo.InteractionCreate, args map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	if h.guildRepo == nil {
+		h.respondError(s, i, "❌ Purging servers is not available.")
+		return
+	}
+
+	guildID, message := ownerTargetGuild(i, args)
+	if message != "" {
+		h.respondError(s, i, message)
+		return
+	}
+	if confirmOpt, ok := args["confirm"]; !ok || !confirmOpt.BoolValue() {
+		h.respondError(s, i, "⚠️ Purging can't be undone. Run the command again with `confirm:true`.")
+		return
+	}
+
+	// Channels subscribed to repositories are only known to Discord
+	var discordChannels []string
+	if s.State != nil {
+		if guild, err := s.State.Guild(guildID); err == nil {
+			for _, channel := range guild.Channels {
+				discordChannels = append(discordChannels, channel.ID)
+			}
+		}
+	}
+
+	result, err := h.purgeGuild(guildID, discordChannels)
+	if err != nil {
+		log.Printf("[OWNER] ERROR: Failed to purge guild %s: %v", guildID, err)
+		h.respondError(s, i, fmt.Sprintf("❌ Error purging the server: %v", err))
+		return
+	}
+
+	h.respondSuccess(s, i, fmt.Sprintf(
+		"🧹 **Server `%s` purged**\n\n• %d subscriptions removed\n• %d feeds and %d repositories unregistered\n• %d settings deleted",
+		guildID, result.subscriptions, result.feeds, result.repos, result.settings,
+	))
+	log.Printf("[OWNER] Purged guild %s: %+v", guildID, result)
+}
+
+// purgeResult counts what purging a guild removed
+type purgeResult struct {
+	subscriptions int
+	feeds         int
+	repos         int
+	settings      int
+}
+
+// purgeGuild removes every subscription of the guild's channels (its recorded channels and
+// the given Discord channels), the feeds and repositories it owns and its settings
+func (h *CommandHandler) purgeGuild(guildID string, discordChannels []string) (purgeResult, error) {
+	var result purgeResult
+
+	channels, err := h.channelRepo.GetGuildChannels(guildID)
+	if err != nil {
+		return result, err
+	}
+	repoChannels, err := h.githubRepo.GetGuildRepoChannels(guildID)
+	if err != nil {
+		return result, err
+	}
+	channels = append(append(channels, repoChannels...), discordChannels...)
+
+	seen := make(map[string]bool, len(channels))
+	for _, channelID := range channels {
+		if seen[channelID] {
+			continue
+		}
+		seen[channelID] = true
+
+		feedIDs, err := h.channelRepo.GetChannelFeeds(channelID)
+		if err != nil {
+			return result, err
+		}
+		for _, feedID := range feedIDs {
+			if err := h.channelRepo.RemoveChannel(channelID, feedID); err != nil {
+				return result, err
+			}
+			result.subscriptions++
+		}
+
+		repoIDs, err := h.githubRepo.GetChannelRepos(channelID)
+		if err != nil {
+			return result, err
+		}
+		for _, repoID := range repoIDs {
+			if err := h.githubRepo.RemoveRepoChannel(repoID, channelID); err != nil {
+				return result, err
+			}
+			result.subscriptions++
+		}
+
+		if err := h.channelRepo.SetChannelStyle(channelID, ""); err != nil {
+			log.Printf("[OWNER] WARNING: Failed to clear summary style of channel %s: %v", channelID, err)
+		}
+		if err := h.channelRepo.SetChannelLanguage(channelID, ""); err != nil {
+			log.Printf("[OWNER] WARNING: Failed to clear language of channel %s: %v", channelID, err)
+		}
+		if h.deliveries != nil {
+			for _, kind := range []string{deliveryKindRSS, deliveryKindGitHub} {
+				if err := h.deliveries.RemoveChannelRetries(kind, channelID); err != nil {
+					log.Printf("[OWNER] WARNING: Failed to clear %s delivery retries of channel %s: %v", kind, channelID, err)
+				}
+			}
+		}
+	}
+
+	// Guild feeds and repositories are only visible to their guild; unregistering them also
+	// drops any subscription left
+	feeds, err := h.feedRepo.GetAllFeeds()
+	if err != nil {
+		return result, err
+	}
+	for _, feed := range feeds {
+		if feed.OwnerGuildID != guildID {
+			continue
+		}
+		if err := h.feedRepo.UnregisterFeed(feed.ID); err != nil {
+			return result, err
+		}
+		result.feeds++
+	}
+
+	repos, err := h.githubRepo.GetAllRepositories()
+	if err != nil {
+		return result, err
+	}
+	for _, repo := range repos {
+		if repo.OwnerGuildID != guildID {
+			continue
+		}
+		if err := h.githubRepo.UnregisterRepository(repo.ID); err != nil {
+			return result, err
+		}
+		result.repos++
+	}
+
+	result.settings, err = h.guildRepo.DeleteGuildSettings(guildID)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// handleOwnerClearPending handles /owner clear-pending
+func (h *CommandHandler) handleOwnerClearPending(s *discordgo.Session, i *discordgo.InteractionCreate, args map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	feedOpt, hasFeed := args["feed"]
+	repoOpt, hasRepo := args["repo"]
+	if hasFeed == hasRepo {
+		h.respondError(s, i, "❌ You need to specify either a feed or a repository.")
+		return
+	}
+
+	if hasFeed {
+		feedID := feedOpt.StringValue()
+		cleared, message := h.clearFeedPending(feedID)
+		if message != "" {
+			h.respondError(s, i, message)
+			return
+		}
+		h.respondSuccess(s, i, fmt.Sprintf("🧹 Cleared %d pending article(s) of feed `%s`.", cleared, feedID))
+		log.Printf("[OWNER] Cleared %d pending articles of feed %s", cleared, feedID)
+		return
+	}
+
+	repoID := repoOpt.StringValue()
+	cleared, message := h.clearRepoPending(repoID)
+	if message != "" {
+		h.respondError(s, i, message)
+		return
+	}
+	h.respondSuccess(s, i, fmt.Sprintf("🧹 Cleared %d pending pull request(s) of repository `%s`.", cleared, repoID))
+	log.Printf("[OWNER] Cleared %d pending PRs of repository %s", cleared, repoID)
+}
+
+// clearFeedPending empties the pending article queue of a feed
+func (h *CommandHandler) clearFeedPending(feedID string) (int, string) {
+	if h.bot == nil {
+		return 0, "❌ Bot is not configured correctly."
+	}
+	exists, err := h.feedRepo.HasFeed(feedID)
+	if err != nil {
+		log.Printf("[OWNER] ERROR: Failed to check feed %s: %v", feedID, err)
+		return 0, "❌ Error checking feed."
+	}
+	if !exists {
+		return 0, fmt.Sprintf("❌ Feed '%s' not found.", feedID)
+	}
+
+	cleared, err := h.bot.historyRepo.ClearPending(feedID)
+	if err != nil {
+		log.Printf("[OWNER] ERROR: Failed to clear pending queue of feed %s: %v", feedID, err)
+		return 0, "❌ Error clearing the pending queue."
+	}
+	return cleared, ""
+}
+
+// clearRepoPending empties the pending pull request queue of a repository
+func (h *CommandHandler) clearRepoPending(repoID string) (int, string) {
+	exists, err := h.githubRepo.HasRepository(repoID)
+	if err != nil {
+		log.Printf("[OWNER] ERROR: Failed to check repository %s: %v", repoID, err)
+		return 0, "❌ Error checking repository."
+	}
+	if !exists {
+		return 0, fmt.Sprintf("❌ Repository `%s` not found.", repoID)
+	}
+
+	cleared, err := h.githubRepo.GetPendingCount(repoID)
+	if err != nil {
+		log.Printf("[OWNER] ERROR: Failed to count pending PRs of repository %s: %v", repoID, err)
+		return 0, "❌ Error clearing the pending queue."
+	}
+	if err := h.githubRepo.ClearPendingQueue(repoID); err != nil {
+		log.Printf("[OWNER] ERROR: Failed to clear pending queue of repository %s: %v", repoID, err)
+		return 0, "❌ Error clearing the pending queue."
+	}
+	return cleared, ""
+}
@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/github"
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Feed and Repository Ownership
+// Feeds and repositories belong to the guild that registered them and are only visible there.
+// Catalog entries (no owner guild) are registered by the bot owners (OWNER_USER_IDS, and
+// admins of the OWNER_GUILD_ID home guild): every guild can subscribe to them, but only the
+// bot owners can change or remove them.
+
+// catalogOwnersOnly is shown when a guild admin tries to change a catalog entry
+const catalogOwnersOnly = "is part of the public catalog; only the bot owners can change it."
+
+// ParseOwnerUserIDs parses a comma-separated list of Discord user IDs (OWNER_USER_IDS)
+func ParseOwnerUserIDs(value string) []string {
+	var userIDs []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			userIDs = append(userIDs, id)
+		}
+	}
+	return userIDs
+}
+
+// SetOwnerUserIDs sets the Discord users allowed to manage catalog feeds and repositories
+func (h *CommandHandler) SetOwnerUserIDs(userIDs []string) {
+	h.owners = make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		h.owners[id] = true
+	}
+}
+
+// SetOwnerGuildID sets the bot's home guild, whose admins (Manage Server) are bot owners too
+func (h *CommandHandler) SetOwnerGuildID(guildID string) {
+	h.ownerGuildID = guildID
+}
+
+// interactionUserID returns the user who ran a command, in a guild or in DMs
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// isBotOwner reports whether the user who ran the command is a bot owner: a configured owner
+// user, or an admin of the home guild running the command there
+func (h *CommandHandler) isBotOwner(i *discordgo.InteractionCreate) bool {
+	if userID := interactionUserID(i); userID != "" && h.owners[userID] {
+		return true
+	}
+	return h.ownerGuildID != "" && i.GuildID == h.ownerGuildID &&
+		i.Member != nil && h.hasManageServerPermission(i.Member)
+}
+
+// canManage reports whether the user may change an entry owned by ownerGuildID: the owner
+// guild's admins for guild entries, the bot owners for catalog entries (empty owner)
+func (h *CommandHandler) canManage(i *discordgo.InteractionCreate, ownerGuildID string) bool {
+	if ownerGuildID == "" {
+		return h.isBotOwner(i)
+	}
+	return ownerGuildID == i.GuildID
+}
+
+// registrationOwner returns the owner guild of an entry registered by the command: the
+// command's guild, or none for catalog entries. It returns a message when the user may not
+// register catalog entries.
+func (h *CommandHandler) registrationOwner(i *discordgo.InteractionCreate, catalog bool) (string, string) {
+	if !catalog {
+		return i.GuildID, ""
+	}
+	if !h.isBotOwner(i) {
+		return "", "❌ Only the bot owners can add entries to the public catalog."
+	}
+	return "", ""
+}
+
+// lookupFeed returns a feed the command's guild can see or, when manage is set, change.
+// Otherwise it returns nil and the message to show; feeds of other guilds are reported as
+// not found, so their identifiers don't leak.
+func (h *CommandHandler) lookupFeed(i *discordgo.InteractionCreate, feedID string, manage bool) (*storage.RSSFeed, string) {
+	exists, err := h.feedRepo.HasFeed(feedID)
+	if err != nil {
+		log.Printf("Error checking feed %s: %v", feedID, err)
+		return nil, "❌ Error checking feed."
+	}
+	if !exists {
+		return nil, fmt.Sprintf("❌ Feed '%s' not found. Use `/list-feeds` to see available feeds.", feedID)
+	}
+
+	feed, err := h.feedRepo.GetFeed(feedID)
+	if err != nil {
+		log.Printf("Error getting feed %s: %v", feedID, err)
+		return nil, "❌ Error checking feed."
+	}
+	if !feed.VisibleTo(i.GuildID) {
+		return nil, fmt.Sprintf("❌ Feed '%s' not found. Use `/list-feeds` to see available feeds.", feedID)
+	}
+	if manage && !h.canManage(i, feed.OwnerGuildID) {
+		return nil, fmt.Sprintf("❌ Feed '%s' %s", feedID, catalogOwnersOnly)
+	}
+	return feed, ""
+}
+
+// lookupRepo returns a repository the command's guild can see or, when manage is set,
+// change. Otherwise it returns nil and the message to show, like lookupFeed.
+func (h *CommandHandler) lookupRepo(i *discordgo.InteractionCreate, repoID string, manage bool) (*github.Repository, string) {
+	exists, err := h.githubRepo.HasRepository(repoID)
+	if err != nil {
+		log.Printf("Error checking repository %s: %v", repoID, err)
+		return nil, fmt.Sprintf("❌ Error checking repository: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Sprintf("❌ Repository `%s` not found. Use `/list-repos` to see available repositories.", repoID)
+	}
+
+	repo, err := h.githubRepo.GetRepository(repoID)
+	if err != nil {
+		log.Printf("Error getting repository %s: %v", repoID, err)
+		return nil, fmt.Sprintf("❌ Failed to get repository details: %v", err)
+	}
+	if !repo.VisibleTo(i.GuildID) {
+		return nil, fmt.Sprintf("❌ Repository `%s` not found. Use `/list-repos` to see available repositories.", repoID)
+	}
+	if manage && !h.canManage(i, repo.OwnerGuildID) {
+		return nil, fmt.Sprintf("❌ Repository `%s` %s", repoID, catalogOwnersOnly)
+	}
+	return repo, ""
+}
+
+// availabilityDisplay describes which servers can subscribe to a newly registered entry
+func availabilityDisplay(ownerGuildID string) string {
+	if ownerGuildID == "" {
+		return "every server (public catalog)"
+	}
+	return "this server only"
+}
+
+// ownershipLabel describes who owns an entry, for listings
+func ownershipLabel(ownerGuildID string) string {
+	if ownerGuildID == "" {
+		return "📚 catalog"
+	}
+	return "🏠 this server"
+}
@@ -0,0 +1,280 @@
+package bot
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Prompt Template Commands
+// This file contains the /prompt-template command and its subcommands
+
+// maxTemplateAttachmentSize bounds the template files downloaded from Discord
+const maxTemplateAttachmentSize = 64 * 1024
+
+// promptVariables documents the variables available to each prompt kind
+var promptVariables = map[ai.PromptKind]string{
+	ai.PromptArticle: "`{{.Title}}` original title • `{{.Content}}` article text • " +
+		"`{{.Language}}` target language name • `{{.Instructions}}` language style instructions • " +
+		"`{{.Languages}}` target languages (`.Code`, `.Name`, `.NativeName`, `.Instructions`) • " +
+		"`{{.Style}}` channel summary style (tldr, standard, detailed, bullet) • `{{.StyleInstructions}}` what the style asks for",
+	ai.PromptPRBatch: "`{{.Repo}}` owner/name • `{{.PRs}}` pull requests (`.Number`, `.Title`, `.Author`, `.URL`, `.Body`, `.Labels`, `.Category`) • " +
+		"`{{.Categories}}` PRs by category (`.Name`, `.PRs`) • `{{.Language}}` • `{{.Instructions}}` • `{{.Languages}}`",
+}
+
+// promptTemplateCommand returns the /prompt-template command definition
+func promptTemplateCommand() *discordgo.ApplicationCommand {
+	kindOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "kind",
+		Description: "Which prompt to customize",
+		Required:    true,
+		Choices: []*discordgo.ApplicationCommandOptionChoice{
+			{Name: "article summaries", Value: string(ai.PromptArticle)},
+			{Name: "pull request summaries", Value: string(ai.PromptPRBatch)},
+		},
+	}
+	feedOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "feed",
+		Description: "Customize the prompt of this feed instead of the whole server (article prompts only)",
+		Required:    false,
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        "prompt-template",
+		Description: "Customize the AI prompts of this server or of a feed (Admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Show the prompt template in use and its variables",
+				Options:     []*discordgo.ApplicationCommandOption{kindOption, feedOption},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Set a custom Go text/template prompt",
+				Options: []*discordgo.ApplicationCommandOption{
+					kindOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionAttachment,
+						Name:        "file",
+						Description: "Template file (use this for multi-line templates)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "template",
+						Description: "Single-line template, e.g. Summarize {{.Title}} in {{.Language}}: {{.Content}}",
+						Required:    false,
+					},
+					feedOption,
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Go back to the default prompt",
+				Options:     []*discordgo.ApplicationCommandOption{kindOption, feedOption},
+			},
+		},
+	}
+}
+
+// handlePromptTemplate handles the /prompt-template command and routes its subcommands
+func (h *CommandHandler) handlePromptTemplate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.respondError(s, i, "This command can only be used in a server.")
+		return
+	}
+
+	member := i.Member
+	if member == nil || !h.hasManageServerPermission(member) {
+		h.respondError(s, i, "❌ You need the **Manage Server** permission to use this command.")
+		return
+	}
+
+	if h.promptRepo == nil {
+		h.respondError(s, i, "❌ Custom prompt templates are not available.")
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		h.respondError(s, i, "❌ You need to specify a subcommand.")
+		return
+	}
+
+	subcommand := options[0]
+	args := optionsByName(subcommand.Options)
+
+	kindOpt, ok := args["kind"]
+	if !ok {
+		h.respondError(s, i, "❌ You need to specify a prompt kind.")
+		return
+	}
+	kind, err := ai.ParsePromptKind(kindOpt.StringValue())
+	if err != nil {
+		h.respondError(s, i, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	// Templates apply to the whole server unless a feed is given
+	scope, scopeID := storage.PromptScopeGuild, i.GuildID
+	scopeName := "this server"
+	if feedOpt, ok := args["feed"]; ok {
+		feedID := feedOpt.StringValue()
+		if kind != ai.PromptArticle {
+			h.respondError(s, i, "❌ Feeds only have article prompts; pull request prompts are set for the whole server.")
+			return
+		}
+
+		// Feed templates apply to every server subscribed to the feed
+		if feed, message := h.lookupFeed(i, feedID, subcommand.Name != "show"); feed == nil {
+			h.respondError(s, i, message)
+			return
+		}
+
+		scope, scopeID = storage.PromptScopeFeed, feedID
+		scopeName = fmt.Sprintf("feed `%s`", feedID)
+	}
+
+	switch subcommand.Name {
+	case "show":
+		h.handlePromptTemplateShow(s, i, kind, scope, scopeID, scopeName)
+	case "set":
+		h.handlePromptTemplateSet(s, i, args, kind, scope, scopeID, scopeName)
+	case "reset":
+		h.handlePromptTemplateReset(s, i, kind, scope, scopeID, scopeName)
+	default:
+		h.respondError(s, i, fmt.Sprintf("❌ Unknown subcommand '%s'.", subcommand.Name))
+	}
+}
+
+// handlePromptTemplateShow handles /prompt-template show, attaching the template as a file
+func (h *CommandHandler) handlePromptTemplateShow(s *discordgo.Session, i *discordgo.InteractionCreate, kind ai.PromptKind, scope storage.PromptScope, scopeID, scopeName string) {
+	text, err := h.promptRepo.GetPromptTemplate(scope, scopeID, kind)
+	if err != nil {
+		log.Printf("[PROMPT-TEMPLATE] ERROR: Failed to get %s template of %s %s: %v", kind, scope, scopeID, err)
+		h.respondError(s, i, "Error getting prompt template.")
+		return
+	}
+
+	source := "custom"
+	if text == "" {
+		source = "default"
+		if scope == storage.PromptScopeFeed {
+			source = "not customized, the prompt of each server applies; showing the default"
+		}
+		text = h.defaultPromptTemplate(kind).Text
+	}
+
+	message := fmt.Sprintf("📝 **%s prompt of %s** (%s)\n\n**Variables:** %s", kind, scopeName, source, promptVariables[kind])
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: truncateMessage(message, 2000),
+			Files: []*discordgo.File{{
+				Name:        string(kind) + ".tmpl",
+				ContentType: "text/plain",
+				Reader:      strings.NewReader(text),
+			}},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to prompt-template show: %v", err)
+	}
+}
+
+// handlePromptTemplateSet handles /prompt-template set
+func (h *CommandHandler) handlePromptTemplateSet(s *discordgo.Session, i *discordgo.InteractionCreate, args map[string]*discordgo.ApplicationCommandInteractionDataOption, kind ai.PromptKind, scope storage.PromptScope, scopeID, scopeName string) {
+	var text string
+	if fileOpt, ok := args["file"]; ok {
+		var attachment *discordgo.MessageAttachment
+		if resolved := i.ApplicationCommandData().Resolved; resolved != nil {
+			attachmentID, _ := fileOpt.Value.(string)
+			attachment = resolved.Attachments[attachmentID]
+		}
+		if attachment == nil {
+			h.respondError(s, i, "❌ Invalid template file.")
+			return
+		}
+		downloaded, err := downloadTemplate(attachment.URL)
+		if err != nil {
+			log.Printf("[PROMPT-TEMPLATE] ERROR: Failed to download template file: %v", err)
+			h.respondError(s, i, "❌ Error downloading the template file.")
+			return
+		}
+		text = downloaded
+	} else if templateOpt, ok := args["template"]; ok {
+		text = templateOpt.StringValue()
+	} else {
+		h.respondError(s, i, "❌ You need to specify a template file or text.")
+		return
+	}
+
+	// Validate the template before storing it, so summaries never fail on it
+	template, err := ai.ParsePromptTemplate(kind, text)
+	if err != nil {
+		h.respondError(s, i, fmt.Sprintf("❌ %v\n\n**Variables:** %s", err, promptVariables[kind]))
+		return
+	}
+
+	if err := h.promptRepo.SetPromptTemplate(scope, scopeID, kind, text); err != nil {
+		log.Printf("[PROMPT-TEMPLATE] ERROR: Failed to set %s template of %s %s: %v", kind, scope, scopeID, err)
+		h.respondError(s, i, fmt.Sprintf("❌ Error saving prompt template: %v", err))
+		return
+	}
+
+	h.respondSuccess(s, i, fmt.Sprintf("✅ **%s prompt of %s updated** (version `%s`)\nNew summaries use it from now on.", kind, scopeName, template.Version))
+	log.Printf("[PROMPT-TEMPLATE] Set %s template of %s %s (version %s)", kind, scope, scopeID, template.Version)
+}
+
+// handlePromptTemplateReset handles /prompt-template reset
+func (h *CommandHandler) handlePromptTemplateReset(s *discordgo.Session, i *discordgo.InteractionCreate, kind ai.PromptKind, scope storage.PromptScope, scopeID, scopeName string) {
+	if err := h.promptRepo.DeletePromptTemplate(scope, scopeID, kind); err != nil {
+		log.Printf("[PROMPT-TEMPLATE] ERROR: Failed to reset %s template of %s %s: %v", kind, scope, scopeID, err)
+		h.respondError(s, i, "Error resetting prompt template.")
+		return
+	}
+
+	h.respondSuccess(s, i, fmt.Sprintf("✅ %s prompt of %s reset to the default.", kind, scopeName))
+	log.Printf("[PROMPT-TEMPLATE] Reset %s template of %s %s", kind, scope, scopeID)
+}
+
+// defaultPromptTemplate returns the template used when neither the feed nor the guild has one
+func (h *CommandHandler) defaultPromptTemplate(kind ai.PromptKind) *ai.PromptTemplate {
+	if h.bot != nil {
+		return h.bot.prompts.fallback(kind)
+	}
+	return ai.DefaultPromptTemplate(kind)
+}
+
+// downloadTemplate downloads a template file attached to a command
+func downloadTemplate(url string) (string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateAttachmentSize))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
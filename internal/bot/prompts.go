@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"log"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+)
+
+// promptResolver picks the prompt template of a summary: the feed's custom template, then the
+// guild's, then the configured one (PROMPT_TEMPLATES_DIR), then the built-in one
+type promptResolver struct {
+	repo       storage.PromptRepository             // custom feed and guild templates, nil when disabled
+	configured map[ai.PromptKind]*ai.PromptTemplate // templates loaded at startup
+}
+
+// stored returns the custom template of a feed or guild, or nil when there is none or it
+// can no longer be parsed
+func (r *promptResolver) stored(scope storage.PromptScope, id string, kind ai.PromptKind) *ai.PromptTemplate {
+	if r.repo == nil || id == "" {
+		return nil
+	}
+
+	text, err := r.repo.GetPromptTemplate(scope, id, kind)
+	if err != nil {
+		log.Printf("WARNING: Failed to get %s prompt template of %s %s: %v", kind, scope, id, err)
+		return nil
+	}
+	if text == "" {
+		return nil
+	}
+
+	t, err := ai.ParsePromptTemplate(kind, text)
+	if err != nil {
+		log.Printf("WARNING: Ignoring %s prompt template of %s %s: %v", kind, scope, id, err)
+		return nil
+	}
+	return t
+}
+
+// fallback returns the configured or built-in template of a kind
+func (r *promptResolver) fallback(kind ai.PromptKind) *ai.PromptTemplate {
+	if t, ok := r.configured[kind]; ok {
+		return t
+	}
+	return ai.DefaultPromptTemplate(kind)
+}
+
+// lookup starts resolving the templates of one item (an article of a feed, or a PR batch
+// when feedID is empty) for the guilds of its channels
+func (r *promptResolver) lookup(kind ai.PromptKind, feedID string) *promptLookup {
+	return &promptLookup{
+		resolver: r,
+		kind:     kind,
+		feed:     r.stored(storage.PromptScopeFeed, feedID, kind),
+		guilds:   make(map[string]*ai.PromptTemplate),
+	}
+}
+
+// promptLookup resolves the prompt templates of one item, caching the template of each guild
+type promptLookup struct {
+	resolver *promptResolver
+	kind     ai.PromptKind
+	feed     *ai.PromptTemplate
+	guilds   map[string]*ai.PromptTemplate
+}
+
+// needsGuild reports whether the template depends on the guild of the channel
+func (l *promptLookup) needsGuild() bool {
+	return l.feed == nil && l.resolver.repo != nil
+}
+
+// template returns the template for channels of the guild
+func (l *promptLookup) template(guildID string) *ai.PromptTemplate {
+	if l.feed != nil {
+		return l.feed
+	}
+
+	t, ok := l.guilds[guildID]
+	if !ok {
+		t = l.resolver.stored(storage.PromptScopeGuild, guildID, l.kind)
+		if t == nil {
+			t = l.resolver.fallback(l.kind)
+		}
+		l.guilds[guildID] = t
+	}
+	return t
+}
+
+// promptGroup holds the channels of one item that get summaries from the same template and
+// in the same summary style
+type promptGroup struct {
+	prompt             *ai.PromptTemplate
+	style              ai.SummaryStyle
+	channelsByLanguage map[string][]string
+}
+
+// promptGroups groups channels by prompt template (by version, so identical templates share
+// their summaries) and summary style, keeping the order in which groups were first seen
+type promptGroups struct {
+	byKey map[string]*promptGroup
+	order []*promptGroup
+}
+
+func newPromptGroups() *promptGroups {
+	return &promptGroups{byKey: make(map[string]*promptGroup)}
+}
+
+// add adds a channel to the group of the template, style and language
+func (g *promptGroups) add(prompt *ai.PromptTemplate, style ai.SummaryStyle, language, channelID string) {
+	key := prompt.Version + "/" + string(style)
+	group, ok := g.byKey[key]
+	if !ok {
+		group = &promptGroup{prompt: prompt, style: style, channelsByLanguage: make(map[string][]string)}
+		g.byKey[key] = group
+		g.order = append(g.order, group)
+	}
+	group.channelsByLanguage[language] = append(group.channelsByLanguage[language], channelID)
+}
@@ -0,0 +1,261 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Guild Quota Commands
+// This file contains per-guild quota checks and the bot owners' /owner quota subcommands.
+// Each guild may subscribe a number of channels and register a number of feeds and
+// repositories (catalog entries don't count); the limits default to GUILD_MAX_* and can
+// be overridden per guild by the bot owners.
+
+// SetQuotaRepository enables per-guild quotas and the /owner quota subcommands
+func (h *CommandHandler) SetQuotaRepository(quotaRepo storage.QuotaRepository) {
+	h.quotaRepo = quotaRepo
+}
+
+// guildUsage returns how many channels, feeds and repositories a guild uses
+func (h *CommandHandler) guildUsage(guildID string) (storage.Quotas, error) {
+	var usage storage.Quotas
+
+	// Channels subscribed to both feeds and repositories count once
+	feedChannels, err := h.channelRepo.GetGuildChannels(guildID)
+	if err != nil {
+		return usage, err
+	}
+	repoChannels, err := h.githubRepo.GetGuildRepoChannels(guildID)
+	if err != nil {
+		return usage, err
+	}
+	channels := make(map[string]bool, len(feedChannels)+len(repoChannels))
+	for _, channelID := range append(feedChannels, repoChannels...) {
+		channels[channelID] = true
+	}
+	usage.Channels = len(channels)
+
+	if usage.Feeds, err = h.feedRepo.GetGuildFeedCount(guildID); err != nil {
+		return usage, err
+	}
+	if usage.Repos, err = h.githubRepo.GetGuildRepoCount(guildID); err != nil {
+		return usage, err
+	}
+
+	return usage, nil
+}
+
+// checkQuota returns the message to show when the guild can't use one more of the resource,
+// or "" when it can (or quotas are disabled)
+func (h *CommandHandler) checkQuota(guildID string, resource storage.QuotaResource) string {
+	if h.quotaRepo == nil || guildID == "" {
+		return ""
+	}
+
+	quotas, err := h.quotaRepo.GetGuildQuotas(guildID)
+	if err != nil {
+		log.Printf("[QUOTA] ERROR: Failed to get quotas of guild %s: %v", guildID, err)
+		return "❌ Error checking this server's quota."
+	}
+	limit := quotas.Get(resource)
+	if limit == 0 {
+		return ""
+	}
+
+	usage, err := h.guildUsage(guildID)
+	if err != nil {
+		log.Printf("[QUOTA] ERROR: Failed to get usage of guild %s: %v", guildID, err)
+		return "❌ Error checking this server's quota."
+	}
+	if used := usage.Get(resource); used >= limit {
+		log.Printf("[QUOTA] Guild %s reached its %s quota (%d/%d)", guildID, resource, used, limit)
+		return quotaExceededMessage(&storage.QuotaExceededError{Resource: resource, Used: used, Limit: limit})
+	}
+	return ""
+}
+
+// guildChannelQuota returns the channel quota the storage enforces when a channel of the guild
+// is subscribed (0 = unlimited or quotas disabled)
+func (h *CommandHandler) guildChannelQuota(guildID string) (int, error) {
+	if h.quotaRepo == nil || guildID == "" {
+		return 0, nil
+	}
+
+	quotas, err := h.quotaRepo.GetGuildQuotas(guildID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get quotas of guild %s: %w", guildID, err)
+	}
+	return quotas.Channels, nil
+}
+
+// quotaExceededMessage returns the message to show when err is a quota overrun, or "" otherwise
+func quotaExceededMessage(err error) string {
+	var quotaErr *storage.QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		return ""
+	}
+	return fmt.Sprintf("❌ This server reached its quota of %s (%d/%d). Remove some first or ask the bot owners for more.",
+		quotaErr.Resource, quotaErr.Used, quotaErr.Limit)
+}
+
+// quotaUsageDisplay formats the usage of each resource against the guild's quotas
+func quotaUsageDisplay(usage, quotas storage.Quotas) string {
+	var lines []string
+	for _, resource := range storage.QuotaResources() {
+		limit := "unlimited"
+		if quotas.Get(resource) > 0 {
+			limit = fmt.Sprintf("%d", quotas.Get(resource))
+		}
+		lines = append(lines, fmt.Sprintf("• %s: %d/%s", resource, usage.Get(resource), limit))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ownerQuotaCommandGroup returns the /owner quota subcommand group
+func ownerQuotaCommandGroup() *discordgo.ApplicationCommandOption {
+	var resourceChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, resource := range storage.QuotaResources() {
+		resourceChoices = append(resourceChoices, &discordgo.ApplicationCommandOptionChoice{Name: string(resource), Value: string(resource)})
+	}
+	resourceOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "resource",
+		Description: "What the quota limits",
+		Required:    true,
+		Choices:     resourceChoices,
+	}
+	minLimit := 0.0
+
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "quota",
+		Description: "View or change the quotas of a server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Show the quotas and usage of a server",
+				Options:     []*discordgo.ApplicationCommandOption{ownerGuildOption(false)},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Override a quota of a server",
+				Options: []*discordgo.ApplicationCommandOption{
+					resourceOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "limit",
+						Description: "New limit (0 = unlimited)",
+						Required:    true,
+						MinValue:    &minLimit,
+					},
+					ownerGuildOption(false),
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Go back to the default quota",
+				Options:     []*discordgo.ApplicationCommandOption{resourceOption, ownerGuildOption(false)},
+			},
+		},
+	}
+}
+
+// handleOwnerQuota handles the /owner quota subcommands
+func (h *CommandHandler) handleOwnerQuota(s *discordgo.Session, i *discordgo.InteractionCreate, group *discordgo.ApplicationCommandInteractionDataOption) {
+	if h.quotaRepo == nil {
+		h.respondError(s, i, "❌ Guild quotas are not available.")
+		return
+	}
+
+	if len(group.Options) == 0 {
+		h.respondError(s, i, "❌ You need to specify a subcommand.")
+		return
+	}
+
+	subcommand := group.Options[0]
+	args := optionsByName(subcommand.Options)
+
+	guildID, message := ownerTargetGuild(i, args)
+	if message != "" {
+		h.respondError(s, i, message)
+		return
+	}
+
+	if subcommand.Name != "show" {
+		resourceOpt, ok := args["resource"]
+		if !ok {
+			h.respondError(s, i, "❌ You need to specify a resource.")
+			return
+		}
+		resource, err := storage.ParseQuotaResource(resourceOpt.StringValue())
+		if err != nil {
+			h.respondError(s, i, fmt.Sprintf("❌ %v", err))
+			return
+		}
+
+		switch subcommand.Name {
+		case "set":
+			limitOpt, ok := args["limit"]
+			if !ok {
+				h.respondError(s, i, "❌ You need to specify a limit.")
+				return
+			}
+			err = h.quotaRepo.SetGuildQuota(guildID, resource, int(limitOpt.IntValue()))
+		case "reset":
+			err = h.quotaRepo.ResetGuildQuota(guildID, resource)
+		default:
+			h.respondError(s, i, fmt.Sprintf("❌ Unknown subcommand '%s'.", subcommand.Name))
+			return
+		}
+		if err != nil {
+			log.Printf("[QUOTA] ERROR: Failed to %s %s quota of guild %s: %v", subcommand.Name, resource, guildID, err)
+			h.respondError(s, i, fmt.Sprintf("❌ Error updating quota: %v", err))
+			return
+		}
+		log.Printf("[QUOTA] %s %s quota of guild %s", subcommand.Name, resource, guildID)
+	}
+
+	message, err := h.guildQuotaSummary(guildID)
+	if err != nil {
+		log.Printf("[QUOTA] ERROR: Failed to get quotas of guild %s: %v", guildID, err)
+		h.respondError(s, i, "❌ Error getting quotas.")
+		return
+	}
+	h.respondSuccess(s, i, message)
+}
+
+// guildQuotaSummary describes the quotas and usage of a guild, marking overridden quotas
+func (h *CommandHandler) guildQuotaSummary(guildID string) (string, error) {
+	quotas, err := h.quotaRepo.GetGuildQuotas(guildID)
+	if err != nil {
+		return "", err
+	}
+	overrides, err := h.quotaRepo.GetQuotaOverrides(guildID)
+	if err != nil {
+		return "", err
+	}
+	usage, err := h.guildUsage(guildID)
+	if err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("📊 **Quotas of server `%s`**\n\n%s", guildID, quotaUsageDisplay(usage, quotas))
+	if len(overrides) > 0 {
+		var names []string
+		for _, resource := range storage.QuotaResources() {
+			if _, ok := overrides[resource]; ok {
+				names = append(names, string(resource))
+			}
+		}
+		message += fmt.Sprintf("\n\nOverridden: %s (others use the defaults)", strings.Join(names, ", "))
+	}
+	return message, nil
+}
@@ -5,6 +5,7 @@ import (
 "log"
 "time"
 
+"github.com/GustavoLR548/godot-news-bot/internal/schedule"
 "github.com/GustavoLR548/godot-news-bot/internal/storage"
 "github.com/bwmarrin/discordgo"
 )
@@ -83,23 +84,13 @@ func (h *CommandHandler) handleSetupNews(s *discordgo.Session, i *discordgo.Inte
 		return
 	}
 
-	// Now do the work
+	// Now do the work: the feed must be a catalog feed or belong to this server
 	log.Printf("[SETUP-FEED-CHANNEL] Checking if feed exists: %s", feedID)
-	hasFeed, err := h.feedRepo.HasFeed(feedID)
-	if err != nil {
-		log.Printf("[SETUP-FEED-CHANNEL] ERROR: Failed to check feed existence: %v", err)
+	feed, message := h.lookupFeed(i, feedID, false)
+	if feed == nil {
+		log.Printf("[SETUP-FEED-CHANNEL] ERROR: Feed %s unavailable in guild %s", feedID, i.GuildID)
 		if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-			Content: "❌ Error checking feed.",
-			Flags:   discordgo.MessageFlagsEphemeral,
-		}); err != nil {
-			log.Printf("[SETUP-FEED-CHANNEL] ERROR: Failed to send followup message: %v", err)
-		}
-		return
-	}
-	if !hasFeed {
-		log.Printf("[SETUP-FEED-CHANNEL] ERROR: Feed not found: %s", feedID)
-		if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-			Content: fmt.Sprintf("❌ Feed '%s' not found. Use `/list-feeds` to see available feeds.", feedID),
+			Content: message,
 			Flags:   discordgo.MessageFlagsEphemeral,
 		}); err != nil {
 			log.Printf("[SETUP-FEED-CHANNEL] ERROR: Failed to send followup message: %v", err)
@@ -163,7 +154,7 @@ func (h *CommandHandler) handleSetupNews(s *discordgo.Session, i *discordgo.Inte
 	}
 	log.Printf("[SETUP-FEED-CHANNEL] Current channel count: %d, Max: %d, New channel: %v", count, h.maxLimit, len(feeds) == 0)
 
-	if count >= h.maxLimit && len(feeds) == 0 {
+	if h.maxLimit > 0 && count >= h.maxLimit && len(feeds) == 0 {
 		// Only enforce limit for new channels, not for adding feeds to existing channels
 		log.Printf("[SETUP-FEED-CHANNEL] ERROR: Channel limit reached (%d/%d)", count, h.maxLimit)
 		if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
@@ -175,12 +166,29 @@ func (h *CommandHandler) handleSetupNews(s *discordgo.Session, i *discordgo.Inte
 		return
 	}
 
+	// New channels count against the server's channel quota, enforced while subscribing
+	maxGuildChannels, err := h.guildChannelQuota(i.GuildID)
+	if err != nil {
+		log.Printf("[SETUP-FEED-CHANNEL] ERROR: %v", err)
+		if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: "❌ Error checking this server's quota.",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}); err != nil {
+			log.Printf("[SETUP-FEED-CHANNEL] ERROR: Failed to send followup message: %v", err)
+		}
+		return
+	}
+
 	// Add the channel-feed association
 	log.Printf("[SETUP-FEED-CHANNEL] Adding channel %s for feed %s", channelID, feedID)
-	if err := h.channelRepo.AddChannel(channelID, feedID); err != nil {
+	if err := h.channelRepo.AddChannel(i.GuildID, channelID, feedID, maxGuildChannels); err != nil {
 		log.Printf("[SETUP-FEED-CHANNEL] ERROR: Failed to add channel: %v", err)
+		message := quotaExceededMessage(err)
+		if message == "" {
+			message = "❌ Error registering channel."
+		}
 		if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-			Content: "❌ Error registering channel.",
+			Content: message,
 			Flags:   discordgo.MessageFlagsEphemeral,
 		}); err != nil {
 			log.Printf("[SETUP-FEED-CHANNEL] ERROR: Failed to send followup message: %v", err)
@@ -189,14 +197,6 @@ func (h *CommandHandler) handleSetupNews(s *discordgo.Session, i *discordgo.Inte
 	}
 	log.Printf("[SETUP-FEED-CHANNEL] SUCCESS: Channel added")
 
-	// Get feed info for response
-	feed, err := h.feedRepo.GetFeed(feedID)
-	if err != nil {
-		log.Printf("[SETUP-FEED-CHANNEL] Warning: Failed to get feed details: %v", err)
-		// Continue anyway, feed exists
-		feed = &storage.RSSFeed{ID: feedID, Title: feedID}
-	}
-
 	// Send success message
 	if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
 		Content: fmt.Sprintf("✅ **Channel configured successfully!**\n\n<#%s> will now receive news from **%s** (%s).", channelID, feed.Title, feedID),
@@ -331,42 +331,57 @@ func (h *CommandHandler) handleListChannels(s *discordgo.Session, i *discordgo.I
 		return
 	}
 
-	// Get all RSS channels
-	rssChannels, err := h.channelRepo.GetAllChannels()
+	// Only list this server's channels
+	response, err := h.guildChannelsList(i.GuildID)
 	if err != nil {
-		log.Printf("Error getting RSS channels: %v", err)
+		log.Printf("Error getting channels of guild %s: %v", i.GuildID, err)
 		h.respondError(s, i, "Error fetching channels.")
 		return
 	}
 
-	// Get all GitHub repositories and their channels
-	githubRepos, err := h.githubRepo.GetAllRepositories()
-	if err != nil {
-		log.Printf("Error getting GitHub repos: %v", err)
-		// Continue even if GitHub query fails
-		githubRepos = nil
+	// Check if we have any subscriptions
+	if response == "" {
+		h.respondSuccess(s, i, "📋 **No registered channels**\n\nUse `/setup-news` for RSS feeds or `/setup-repo-channel` for GitHub repositories.")
+		return
 	}
+	response = "📋 **Registered Channels**\n\n" + response
 
-	// Build channel map for GitHub repos
-	githubChannelMap := make(map[string][]string) // channelID -> []repoIDs
-	for _, repo := range githubRepos {
-		channels, err := h.githubRepo.GetRepoChannels(repo.ID)
+	// Report this server's usage of its quotas
+	if h.quotaRepo != nil {
+		quotas, err := h.quotaRepo.GetGuildQuotas(i.GuildID)
 		if err == nil {
-			for _, channelID := range channels {
-				githubChannelMap[channelID] = append(githubChannelMap[channelID], repo.ID)
+			var usage storage.Quotas
+			usage, err = h.guildUsage(i.GuildID)
+			if err == nil {
+				response += fmt.Sprintf("**📊 This server's quotas:**\n%s\n\n", quotaUsageDisplay(usage, quotas))
 			}
 		}
+		if err != nil {
+			log.Printf("Error getting quota usage of guild %s: %v", i.GuildID, err)
+		}
 	}
 
-	// Check if we have any subscriptions
-	if len(rssChannels) == 0 && len(githubChannelMap) == 0 {
-		h.respondSuccess(s, i, "📋 **No registered channels**\n\nUse `/setup-news` for RSS feeds or `/setup-repo-channel` for GitHub repositories.")
-		return
+	response += "💡 Use `/remove-feed-channel` for RSS or `/remove-repo-channel` for GitHub subscriptions."
+
+	h.respondSuccess(s, i, response)
+
+	log.Printf("Channel list requested by user in guild %s", i.GuildID)
+}
+
+// guildChannelsList lists the guild's channels subscribed to feeds and to repositories,
+// or returns "" when it has none
+func (h *CommandHandler) guildChannelsList(guildID string) (string, error) {
+	rssChannels, err := h.channelRepo.GetGuildChannels(guildID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get RSS channels: %w", err)
+	}
+
+	githubChannels, err := h.githubRepo.GetGuildRepoChannels(guildID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get GitHub channels: %w", err)
 	}
 
-	// Build response
 	var response string
-	response = "📋 **Registered Channels**\n\n"
 
 	// List RSS channels
 	if len(rssChannels) > 0 {
@@ -377,22 +392,20 @@ func (h *CommandHandler) handleListChannels(s *discordgo.Session, i *discordgo.I
 		response += "\n"
 	}
 
-	// List GitHub channels
-	if len(githubChannelMap) > 0 {
-		response += fmt.Sprintf("**🐙 GitHub PRs** (%d channels):\n", len(githubChannelMap))
-		idx := 1
-		for channelID, repoIDs := range githubChannelMap {
-			response += fmt.Sprintf("%d. <#%s> (repos: %s)\n", idx, channelID, joinStrings(repoIDs, ", "))
-			idx++
+	// List GitHub channels with their repositories
+	if len(githubChannels) > 0 {
+		response += fmt.Sprintf("**🐙 GitHub PRs** (%d channels):\n", len(githubChannels))
+		for i, channelID := range githubChannels {
+			repoIDs, err := h.githubRepo.GetChannelRepos(channelID)
+			if err != nil {
+				return "", fmt.Errorf("failed to get repositories of channel %s: %w", channelID, err)
+			}
+			response += fmt.Sprintf("%d. <#%s> (repos: %s)\n", i+1, channelID, joinStrings(repoIDs, ", "))
 		}
 		response += "\n"
 	}
 
-	response += "💡 Use `/remove-feed-channel` for RSS or `/remove-repo-channel` for GitHub subscriptions."
-
-	h.respondSuccess(s, i, response)
-
-	log.Printf("Channel list requested by user in guild %s", i.GuildID)
+	return response, nil
 }
 
 // joinStrings is a simple helper to join strings with a separator
@@ -441,15 +454,10 @@ func (h *CommandHandler) handleUpdateNews(s *discordgo.Session, i *discordgo.Int
 		feedID = options[0].StringValue()
 	}
 
-	// Verify feed exists
-	hasFeed, err := h.feedRepo.HasFeed(feedID)
-	if err != nil {
-		log.Printf("Error checking feed: %v", err)
-		h.respondError(s, i, "❌ Error checking feed.")
-		return
-	}
-	if !hasFeed {
-		h.respondError(s, i, fmt.Sprintf("❌ Feed not found: %s\n\nUse `/list-feeds` to see available feeds.", feedID))
+	// Verify feed exists and is visible to this server
+	feed, message := h.lookupFeed(i, feedID, false)
+	if feed == nil {
+		h.respondError(s, i, message)
 		return
 	}
 
@@ -472,13 +480,6 @@ func (h *CommandHandler) handleUpdateNews(s *discordgo.Session, i *discordgo.Int
 		return
 	}
 
-	// Get feed info
-	feed, err := h.feedRepo.GetFeed(feedID)
-	if err != nil {
-		log.Printf("Error getting feed: %v", err)
-		// Continue anyway
-	}
-
 	// Send initial response
 	respondErr := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -619,23 +620,39 @@ func (h *CommandHandler) handleRegisterFeed(s *discordgo.Session, i *discordgo.I
 	}
 
 	// Get parameters
-	options := i.ApplicationCommandData().Options
-	if len(options) < 2 {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	idOpt, hasID := options["identifier"]
+	urlOpt, hasURL := options["url"]
+	if !hasID || !hasURL {
 		h.respondError(s, i, "❌ You need to specify identifier and url.")
 		return
 	}
 
-	feedID := options[0].StringValue()
-	feedURL := options[1].StringValue()
+	feedID := idOpt.StringValue()
+	feedURL := urlOpt.StringValue()
 	
 	title := feedID
-	if len(options) > 2 && options[2].StringValue() != "" {
-		title = options[2].StringValue()
+	if opt, ok := options["title"]; ok && opt.StringValue() != "" {
+		title = opt.StringValue()
 	}
 	
 	description := ""
-	if len(options) > 3 {
-		description = options[3].StringValue()
+	if opt, ok := options["description"]; ok {
+		description = opt.StringValue()
+	}
+
+	// Feeds belong to this server unless a bot owner adds them to the public catalog
+	catalog := false
+	if opt, ok := options["catalog"]; ok {
+		catalog = opt.BoolValue()
+	}
+	ownerGuildID, message := h.registrationOwner(i, catalog)
+	if message == "" {
+		message = h.checkQuota(ownerGuildID, storage.QuotaFeeds)
+	}
+	if message != "" {
+		h.respondError(s, i, message)
+		return
 	}
 
 	// Validate feed ID
@@ -671,17 +688,19 @@ func (h *CommandHandler) handleRegisterFeed(s *discordgo.Session, i *discordgo.I
 	}
 
 	if exists {
-		h.respondError(s, i, fmt.Sprintf("❌ Feed '%s' is already registered.", feedID))
+		// Identifiers are global; don't tell whether another server registered it
+		h.respondError(s, i, fmt.Sprintf("❌ The feed identifier '%s' is already taken. Choose another one.", feedID))
 		return
 	}
 
 	// Register feed
 	feed := storage.RSSFeed{
-		ID:          feedID,
-		URL:         feedURL,
-		Title:       title,
-		Description: description,
-		AddedAt:     time.Now(),
+		ID:           feedID,
+		URL:          feedURL,
+		Title:        title,
+		Description:  description,
+		AddedAt:      time.Now(),
+		OwnerGuildID: ownerGuildID,
 	}
 
 	if err := h.feedRepo.RegisterFeed(feed); err != nil {
@@ -691,11 +710,11 @@ func (h *CommandHandler) handleRegisterFeed(s *discordgo.Session, i *discordgo.I
 	}
 
 	h.respondSuccess(s, i, fmt.Sprintf(
-		"✅ **Feed registered successfully!**\n\n**ID:** %s\n**Title:** %s\n**URL:** %s",
-		feedID, title, feedURL,
+		"✅ **Feed registered successfully!**\n\n**ID:** %s\n**Title:** %s\n**URL:** %s\n**Available to:** %s",
+		feedID, title, feedURL, availabilityDisplay(ownerGuildID),
 	))
 
-	log.Printf("Feed %s registered in guild %s", feedID, i.GuildID)
+	log.Printf("Feed %s registered in guild %s (catalog: %v)", feedID, i.GuildID, catalog)
 }
 
 // handleUnregisterFeed handles the /unregister-feed command
@@ -722,16 +741,9 @@ func (h *CommandHandler) handleUnregisterFeed(s *discordgo.Session, i *discordgo
 
 	feedID := options[0].StringValue()
 
-	// Check if feed exists
-	exists, err := h.feedRepo.HasFeed(feedID)
-	if err != nil {
-		log.Printf("Error checking feed: %v", err)
-		h.respondError(s, i, "Error checking feed.")
-		return
-	}
-
-	if !exists {
-		h.respondError(s, i, fmt.Sprintf("❌ Feed '%s' not found.", feedID))
+	// Only the owner server (or the bot owners, for catalog feeds) can remove a feed
+	if feed, message := h.lookupFeed(i, feedID, true); feed == nil {
+		h.respondError(s, i, message)
 		return
 	}
 
@@ -741,27 +753,20 @@ func (h *CommandHandler) handleUnregisterFeed(s *discordgo.Session, i *discordgo
 		log.Printf("Error getting feed channels: %v", err)
 	}
 
-	// Unregister feed
+	// Unregister feed (this also unsubscribes its channels)
 	if err := h.feedRepo.UnregisterFeed(feedID); err != nil {
 		log.Printf("Error unregistering feed: %v", err)
 		h.respondError(s, i, "Error removing feed.")
 		return
 	}
 
-	// Cleanup: Remove channel associations and orphaned data
-	if len(channels) > 0 {
-		log.Printf("Cleaning up %d channel associations for feed %s", len(channels), feedID)
-		for _, chID := range channels {
-			if err := h.channelRepo.RemoveChannel(chID, feedID); err != nil {
-				log.Printf("Error removing feed %s from channel %s: %v", feedID, chID, err)
-			}
+	// Clear pending articles
+	if h.bot != nil {
+		if _, err := h.bot.historyRepo.ClearPending(feedID); err != nil {
+			log.Printf("Error clearing pending articles for feed %s: %v", feedID, err)
 		}
 	}
 
-	// Clear pending queue - feedRepo.ClearPending doesn't exist, so we'll leave queue as is
-	// The queue will naturally clear when articles are fetched next time
-	// or can be cleared manually via Redis CLI if needed
-
 	h.respondSuccess(s, i, fmt.Sprintf(
 		"✅ **Feed removed successfully!**\n\nFeed '%s' was removed%s.",
 		feedID,
@@ -786,6 +791,15 @@ func (h *CommandHandler) handleListFeeds(s *discordgo.Session, i *discordgo.Inte
 		return
 	}
 
+	// Only catalog feeds and feeds of this server are listed
+	visible := feeds[:0]
+	for _, feed := range feeds {
+		if feed.VisibleTo(i.GuildID) {
+			visible = append(visible, feed)
+		}
+	}
+	feeds = visible
+
 	if len(feeds) == 0 {
 		h.respondError(s, i, "ℹ️ No feeds registered.")
 		return
@@ -794,7 +808,7 @@ func (h *CommandHandler) handleListFeeds(s *discordgo.Session, i *discordgo.Inte
 	// Build response
 	response := "📰 **Registered Feeds**\n\n"
 	for _, feed := range feeds {
-		response += fmt.Sprintf("**%s** (`%s`)\n", feed.Title, feed.ID)
+		response += fmt.Sprintf("**%s** (`%s`) %s\n", feed.Title, feed.ID, ownershipLabel(feed.OwnerGuildID))
 		response += fmt.Sprintf("└ URL: %s\n", feed.URL)
 		
 		// Show schedule if any
@@ -802,11 +816,18 @@ func (h *CommandHandler) handleListFeeds(s *discordgo.Session, i *discordgo.Inte
 			times := ""
 			for idx, t := range feed.Schedule {
 				if idx > 0 {
-					times += ", "
+					times += "; "
 				}
 				times += t
 			}
-			response += fmt.Sprintf("└ Schedule: %s\n", times)
+			response += fmt.Sprintf("└ Schedule: %s (%s)\n", times, timezoneDisplay(feed.Timezone))
+			response += fmt.Sprintf("└ Next check: %s\n", nextRunDisplay(feed.Schedule, feed.Timezone))
+		}
+		if feed.SkipScrape {
+			response += "└ Scraping: off (feed content only)\n"
+		}
+		if feed.Extractive {
+			response += "└ Summaries: extractive (no AI)\n"
 		}
 		
 		// Show channel count
@@ -845,26 +866,14 @@ func (h *CommandHandler) handleScheduleFeed(s *discordgo.Session, i *discordgo.I
 	feedID := options[0].StringValue()
 	timesStr := options[1].StringValue()
 
-	// Check if feed exists
-	exists, err := h.feedRepo.HasFeed(feedID)
-	if err != nil {
-		log.Printf("Error checking feed: %v", err)
-		h.respondError(s, i, "Error checking feed.")
+	// Check if feed exists and this server can change it
+	if feed, message := h.lookupFeed(i, feedID, true); feed == nil {
+		h.respondError(s, i, message)
 		return
 	}
 
-	if !exists {
-		h.respondError(s, i, fmt.Sprintf("❌ Feed '%s' not found.", feedID))
-		return
-	}
-
-	// Parse times (comma-separated)
-	times := []string{}
-	for _, t := range splitAndTrim(timesStr, ",") {
-		if t != "" {
-			times = append(times, t)
-		}
-	}
+	// Parse schedule entries (";"-separated, or a comma-separated list of times)
+	times := schedule.SplitSpecs(timesStr)
 
 	// Validate schedule times
 	if err := validateScheduleTimes(times); err != nil {
@@ -872,6 +881,17 @@ func (h *CommandHandler) handleScheduleFeed(s *discordgo.Session, i *discordgo.I
 		return
 	}
 
+	// Optional timezone the times are interpreted in
+	var timezone *string
+	if opt, ok := optionsByName(options)["timezone"]; ok {
+		tz, err := parseTimezoneOption(opt.StringValue())
+		if err != nil {
+			h.respondError(s, i, fmt.Sprintf("❌ %s", err.Error()))
+			return
+		}
+		timezone = &tz
+	}
+
 	// Set schedule
 	if err := h.feedRepo.SetSchedule(feedID, times); err != nil {
 		log.Printf("Error setting schedule: %v", err)
@@ -879,69 +899,107 @@ func (h *CommandHandler) handleScheduleFeed(s *discordgo.Session, i *discordgo.I
 		return
 	}
 
+	if timezone != nil {
+		if err := h.feedRepo.SetTimezone(feedID, *timezone); err != nil {
+			log.Printf("Error setting timezone: %v", err)
+			h.respondError(s, i, fmt.Sprintf("❌ Error setting timezone: %v", err))
+			return
+		}
+	}
+
+	feed, err := h.feedRepo.GetFeed(feedID)
+	if err != nil {
+		log.Printf("Error getting feed: %v", err)
+		h.respondError(s, i, "Error reading the updated feed.")
+		return
+	}
+
+	if len(times) == 0 {
+		h.respondSuccess(s, i, fmt.Sprintf(
+			"✅ **Schedule cleared!**\n\nFeed '%s' will be checked on the default interval (`CHECK_INTERVAL_MINUTES`).",
+			feedID,
+		))
+		log.Printf("Schedule cleared for feed %s", feedID)
+		return
+	}
+
 	timesDisplay := ""
 	for idx, t := range times {
 		if idx > 0 {
-			timesDisplay += ", "
+			timesDisplay += "; "
 		}
 		timesDisplay += t
 	}
 
 	h.respondSuccess(s, i, fmt.Sprintf(
-		"✅ **Schedule configured!**\n\nFeed '%s' will be checked at the following times:\n%s",
-		feedID, timesDisplay,
+		"✅ **Schedule configured!**\n\nFeed '%s' will be checked on this schedule (%s):\n%s\n\nNext check: %s",
+		feedID, timezoneDisplay(feed.Timezone), timesDisplay, nextRunDisplay(times, feed.Timezone),
 	))
 
-	log.Printf("Schedule set for feed %s: %v", feedID, times)
+	log.Printf("Schedule set for feed %s: %v (timezone: %q)", feedID, times, feed.Timezone)
 }
 
-// splitAndTrim splits a string and trims each part
-func splitAndTrim(s, sep string) []string {
-	parts := []string{}
-	for _, part := range splitString(s, sep) {
-		trimmed := trimString(part)
-		if trimmed != "" {
-			parts = append(parts, trimmed)
-		}
+// handleFeedSettings handles the /feed-settings command
+func (h *CommandHandler) handleFeedSettings(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Check guild
+	if i.GuildID == "" {
+		h.respondError(s, i, "This command can only be used in a server.")
+		return
 	}
-	return parts
-}
 
-// splitString splits a string by separator
-func splitString(s, sep string) []string {
-	if s == "" {
-		return []string{}
+	// Check permissions
+	member := i.Member
+	if member == nil || !h.hasManageServerPermission(member) {
+		h.respondError(s, i, "❌ You need the **Manage Server** permission to use this command.")
+		return
 	}
-	
-	parts := []string{}
-	current := ""
-	
-	for i := 0; i < len(s); i++ {
-		if i+len(sep) <= len(s) && s[i:i+len(sep)] == sep {
-			parts = append(parts, current)
-			current = ""
-			i += len(sep) - 1
-		} else {
-			current += string(s[i])
+
+	options := optionsByName(i.ApplicationCommandData().Options)
+	feedOpt, ok := options["feed"]
+	if !ok {
+		h.respondError(s, i, "❌ You need to specify a feed.")
+		return
+	}
+	feedID := feedOpt.StringValue()
+
+	// Showing the settings only needs the feed to be visible; changing them needs ownership
+	_, changeScrape := options["skip-scrape"]
+	_, changeExtractive := options["extractive"]
+	feed, message := h.lookupFeed(i, feedID, changeScrape || changeExtractive)
+	if feed == nil {
+		h.respondError(s, i, message)
+		return
+	}
+
+	if opt, ok := options["skip-scrape"]; ok {
+		if err := h.feedRepo.SetSkipScrape(feedID, opt.BoolValue()); err != nil {
+			log.Printf("Error updating settings of feed %s: %v", feedID, err)
+			h.respondError(s, i, fmt.Sprintf("❌ Error updating settings: %v", err))
+			return
 		}
+		feed.SkipScrape = opt.BoolValue()
+		log.Printf("Skip scrape set for feed %s: %v", feedID, feed.SkipScrape)
 	}
-	parts = append(parts, current)
-	
-	return parts
-}
 
-// trimString removes leading and trailing whitespace
-func trimString(s string) string {
-	start := 0
-	end := len(s)
-	
-	for start < end && (s[start] == ' ' || s[start] == '\t' || s[start] == '\n' || s[start] == '\r') {
-		start++
+	if opt, ok := options["extractive"]; ok {
+		if err := h.feedRepo.SetExtractive(feedID, opt.BoolValue()); err != nil {
+			log.Printf("Error updating settings of feed %s: %v", feedID, err)
+			h.respondError(s, i, fmt.Sprintf("❌ Error updating settings: %v", err))
+			return
+		}
+		feed.Extractive = opt.BoolValue()
+		log.Printf("Extractive summaries set for feed %s: %v", feedID, feed.Extractive)
 	}
-	
-	for end > start && (s[end-1] == ' ' || s[end-1] == '\t' || s[end-1] == '\n' || s[end-1] == '\r') {
-		end--
+
+	scraping := "on (article pages are scraped when the feed content is missing or short)"
+	if feed.SkipScrape {
+		scraping = "off (only the content provided by the feed is used)"
+	}
+
+	summaries := "AI (translated to the channel language)"
+	if feed.Extractive {
+		summaries = "extractive (key sentences picked offline, in the article's language)"
 	}
 
-return s[start:end]
+	h.respondSuccess(s, i, fmt.Sprintf("⚙️ **Settings for feed '%s'**\n\n└ Scraping: %s\n└ Summaries: %s", feedID, scraping, summaries))
 }
@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCatchUpWindow bounds how far back missed schedule slots are caught up after downtime
+const maxCatchUpWindow = 7 * 24 * time.Hour
+
+// scheduleWindowStart returns the start of the window (start, now] in which a scheduled run is due.
+//
+// Slots missed since the last successful run (bot restart, delayed tick) fall inside the window,
+// so they are run once; the catch-up is bounded by maxCatchUpWindow. Items that never ran only
+// look at the previous tick. A run attempted after the last success (e.g. a failed fetch) moves
+// the window forward, so failures are retried at the next slot instead of on every tick.
+func scheduleWindowStart(lastRun, lastAttempt, lastTick, now time.Time) time.Time {
+	start := lastTick
+	if !lastRun.IsZero() {
+		start = lastRun
+		if limit := now.Add(-maxCatchUpWindow); start.Before(limit) {
+			start = limit
+		}
+	}
+	if lastAttempt.After(start) {
+		start = lastAttempt
+	}
+	return start
+}
+
+// attemptTracker remembers when each feed or repository was last attempted in this process
+type attemptTracker struct {
+	mu       sync.Mutex
+	attempts map[string]time.Time
+}
+
+// record stores an attempt for the given feed or repository
+func (t *attemptTracker) record(id string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.attempts == nil {
+		t.attempts = make(map[string]time.Time)
+	}
+	t.attempts[id] = at
+}
+
+// last returns the last attempt for the given feed or repository (zero if none)
+func (t *attemptTracker) last(id string) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.attempts[id]
+}
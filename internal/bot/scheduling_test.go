@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleWindowStart(t *testing.T) {
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+	lastTick := now.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		lastRun     time.Time
+		lastAttempt time.Time
+		expected    time.Time
+	}{
+		{
+			name:     "never run uses the previous tick",
+			expected: lastTick,
+		},
+		{
+			name:     "catches up since the last successful run",
+			lastRun:  now.Add(-5 * time.Hour),
+			expected: now.Add(-5 * time.Hour),
+		},
+		{
+			name:     "catch-up is bounded",
+			lastRun:  now.Add(-30 * 24 * time.Hour),
+			expected: now.Add(-maxCatchUpWindow),
+		},
+		{
+			name:        "failed attempt waits for the next slot",
+			lastRun:     now.Add(-5 * time.Hour),
+			lastAttempt: now.Add(-2 * time.Minute),
+			expected:    now.Add(-2 * time.Minute),
+		},
+		{
+			name:        "older attempt is ignored",
+			lastRun:     now.Add(-time.Hour),
+			lastAttempt: now.Add(-2 * time.Hour),
+			expected:    now.Add(-time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, scheduleWindowStart(tt.lastRun, tt.lastAttempt, lastTick, now))
+		})
+	}
+}
+
+func TestScheduleWindowStart_MissedSlotRunsOnce(t *testing.T) {
+	specs, err := schedule.ParseAll([]string{"09:00"})
+	require.NoError(t, err)
+
+	// Last run yesterday evening; the bot was down at 09:00 and comes back at 11:00
+	lastRun := time.Date(2025, 6, 3, 18, 0, 0, 0, time.UTC)
+	restart := time.Date(2025, 6, 4, 11, 0, 0, 0, time.UTC)
+
+	since := scheduleWindowStart(lastRun, time.Time{}, restart.Add(-time.Minute), restart)
+	assert.True(t, schedule.Due(specs, time.UTC, since, restart))
+
+	// After the catch-up run, the next tick no longer sees the slot
+	next := restart.Add(time.Minute)
+	since = scheduleWindowStart(restart, restart, restart, next)
+	assert.False(t, schedule.Due(specs, time.UTC, since, next))
+}
+
+func TestAttemptTracker(t *testing.T) {
+	var tracker attemptTracker
+	assert.True(t, tracker.last("feed").IsZero())
+
+	at := time.Now()
+	tracker.record("feed", at)
+	assert.Equal(t, at, tracker.last("feed"))
+	assert.True(t, tracker.last("other").IsZero())
+}
@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/schedule"
 )
 
 // Validation helper functions for command input validation
@@ -94,22 +96,8 @@ func isValidGitHubName(name string) error {
 	return nil
 }
 
-// isValidTimeFormat validates time in HH:MM format (24-hour)
-func isValidTimeFormat(timeStr string) error {
-	if timeStr == "" {
-		return fmt.Errorf("time cannot be empty")
-	}
-
-	// Strict HH:MM format validation
-	timePattern := regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
-	if !timePattern.MatchString(timeStr) {
-		return fmt.Errorf("invalid time format, use HH:MM (24-hour format, e.g., 09:00, 13:30)")
-	}
-
-	return nil
-}
-
-// validateScheduleTimes validates a list of schedule times
+// validateScheduleTimes validates a list of schedule entries
+// (HH:MM, "every <duration>" or cron, optionally restricted to weekdays)
 func validateScheduleTimes(times []string) error {
 	if len(times) == 0 {
 		return nil // Empty schedule is valid (uses fallback interval)
@@ -122,20 +110,44 @@ func validateScheduleTimes(times []string) error {
 	seen := make(map[string]bool)
 	for _, timeStr := range times {
 		// Validate format
-		if err := isValidTimeFormat(timeStr); err != nil {
+		spec, err := schedule.Parse(timeStr)
+		if err != nil {
 			return err
 		}
 
 		// Check for duplicates
-		if seen[timeStr] {
+		if seen[spec.String()] {
 			return fmt.Errorf("duplicate time in schedule: %s", timeStr)
 		}
-		seen[timeStr] = true
+		seen[spec.String()] = true
 	}
 
 	return nil
 }
 
+// parseTimezoneOption validates a timezone given to a schedule command and returns the
+// name to store. "local" resets the schedule to the bot's local time (stored as empty).
+func parseTimezoneOption(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return "", nil
+	}
+
+	loc, err := schedule.LoadLocation(name)
+	if err != nil {
+		return "", err
+	}
+	return loc.String(), nil
+}
+
+// timezoneDisplay returns a human-readable name for a stored schedule timezone
+func timezoneDisplay(name string) string {
+	if name == "" {
+		return "bot local time"
+	}
+	return name
+}
+
 // isValidLanguageCode validates language code against supported languages
 func isValidLanguageCode(code string) error {
 	validLanguages := map[string]bool{
@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule actions
+const (
+	ActionInclude = "include" // Item must match at least one include rule
+	ActionExclude = "exclude" // Item is dropped when it matches any exclude rule
+)
+
+// Rule types
+const (
+	TypeKeyword  = "keyword"  // Case-insensitive substring of the title or description
+	TypeCategory = "category" // Case-insensitive match against the item's categories
+	TypeRegex    = "regex"    // Regular expression matched against the title and description
+)
+
+// maxValueLength limits the size of a rule value
+const maxValueLength = 200
+
+// Rule is a single include/exclude rule attached to a feed or a channel subscription
+type Rule struct {
+	Action string `json:"action"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+
+	re *regexp.Regexp // compiled Value of regex rules, set by Compile
+}
+
+// Item is the part of an article that rules are evaluated against
+type Item struct {
+	Title       string
+	Description string
+	Categories  []string
+}
+
+// Validate checks that the rule is well formed and compiles its regex
+func (r *Rule) Validate() error {
+	if r.Action != ActionInclude && r.Action != ActionExclude {
+		return fmt.Errorf("invalid filter action %q (expected include or exclude)", r.Action)
+	}
+
+	if strings.TrimSpace(r.Value) == "" {
+		return fmt.Errorf("filter value cannot be empty")
+	}
+
+	if len(r.Value) > maxValueLength {
+		return fmt.Errorf("filter value too long (max %d characters)", maxValueLength)
+	}
+
+	switch r.Type {
+	case TypeKeyword, TypeCategory:
+		return nil
+	case TypeRegex:
+		return r.Compile()
+	default:
+		return fmt.Errorf("invalid filter type %q (expected keyword, category or regex)", r.Type)
+	}
+}
+
+// Compile compiles the regex of a regex rule once, so matching doesn't compile it for every
+// article; rules loaded from storage must be compiled before they are matched
+func (r *Rule) Compile() error {
+	if r.Type != TypeRegex {
+		return nil
+	}
+	re, err := regexp.Compile(r.Value)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+	r.re = re
+	return nil
+}
+
+// String returns a human readable representation of the rule
+func (r Rule) String() string {
+	return fmt.Sprintf("%s %s `%s`", r.Action, r.Type, r.Value)
+}
+
+// Matches reports whether the rule matches the item, regardless of its action. Regex rules
+// only match once compiled.
+func (r Rule) Matches(item Item) bool {
+	switch r.Type {
+	case TypeKeyword:
+		keyword := strings.ToLower(r.Value)
+		return strings.Contains(strings.ToLower(item.Title), keyword) ||
+			strings.Contains(strings.ToLower(item.Description), keyword)
+	case TypeCategory:
+		for _, category := range item.Categories {
+			if strings.EqualFold(strings.TrimSpace(category), strings.TrimSpace(r.Value)) {
+				return true
+			}
+		}
+		return false
+	case TypeRegex:
+		if r.re == nil {
+			return false
+		}
+		return r.re.MatchString(item.Title) || r.re.MatchString(item.Description)
+	default:
+		return false
+	}
+}
+
+// Allows reports whether the item passes the rules: it must not match any exclude
+// rule and, when include rules exist, it must match at least one of them
+func Allows(rules []Rule, item Item) bool {
+	hasInclude := false
+	included := false
+
+	for _, rule := range rules {
+		switch rule.Action {
+		case ActionExclude:
+			if rule.Matches(item) {
+				return false
+			}
+		case ActionInclude:
+			hasInclude = true
+			if !included && rule.Matches(item) {
+				included = true
+			}
+		}
+	}
+
+	return !hasInclude || included
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{"valid include keyword", Rule{Action: ActionInclude, Type: TypeKeyword, Value: "release"}, false},
+		{"valid exclude category", Rule{Action: ActionExclude, Type: TypeCategory, Value: "Showcase"}, false},
+		{"valid regex", Rule{Action: ActionInclude, Type: TypeRegex, Value: `(?i)godot \d\.\d`}, false},
+		{"invalid action", Rule{Action: "drop", Type: TypeKeyword, Value: "x"}, true},
+		{"invalid type", Rule{Action: ActionInclude, Type: "author", Value: "x"}, true},
+		{"empty value", Rule{Action: ActionInclude, Type: TypeKeyword, Value: "  "}, true},
+		{"broken regex", Rule{Action: ActionExclude, Type: TypeRegex, Value: "(unclosed"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	item := Item{
+		Title:       "Godot 4.3 Released",
+		Description: "The new release brings many rendering improvements",
+		Categories:  []string{"Release", "Rendering"},
+	}
+
+	tests := []struct {
+		name     string
+		rule     Rule
+		expected bool
+	}{
+		{"keyword in title is case-insensitive", Rule{Type: TypeKeyword, Value: "godot"}, true},
+		{"keyword in description", Rule{Type: TypeKeyword, Value: "rendering improvements"}, true},
+		{"keyword missing", Rule{Type: TypeKeyword, Value: "showcase"}, false},
+		{"category is case-insensitive", Rule{Type: TypeCategory, Value: "release"}, true},
+		{"category must match exactly", Rule{Type: TypeCategory, Value: "Rend"}, false},
+		{"regex on title", Rule{Type: TypeRegex, Value: `\d\.\d Released$`}, true},
+		{"regex no match", Rule{Type: TypeRegex, Value: `^Dev snapshot`}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.rule.Compile())
+			assert.Equal(t, tt.expected, tt.rule.Matches(item))
+		})
+	}
+
+	// Regex rules don't match until compiled
+	uncompiled := Rule{Type: TypeRegex, Value: `Released$`}
+	assert.False(t, uncompiled.Matches(item))
+}
+
+func TestAllows(t *testing.T) {
+	release := Item{Title: "Godot 4.3 Released", Categories: []string{"Release"}}
+	showcase := Item{Title: "Showcase: a new game", Categories: []string{"Showcase"}}
+
+	tests := []struct {
+		name     string
+		rules    []Rule
+		item     Item
+		expected bool
+	}{
+		{"no rules allows everything", nil, showcase, true},
+		{"exclude match drops the item", []Rule{{Action: ActionExclude, Type: TypeCategory, Value: "showcase"}}, showcase, false},
+		{"exclude without match keeps the item", []Rule{{Action: ActionExclude, Type: TypeCategory, Value: "showcase"}}, release, true},
+		{"include match keeps the item", []Rule{{Action: ActionInclude, Type: TypeKeyword, Value: "released"}}, release, true},
+		{"include without match drops the item", []Rule{{Action: ActionInclude, Type: TypeKeyword, Value: "released"}}, showcase, false},
+		{
+			"any include is enough",
+			[]Rule{
+				{Action: ActionInclude, Type: TypeKeyword, Value: "released"},
+				{Action: ActionInclude, Type: TypeCategory, Value: "showcase"},
+			},
+			showcase,
+			true,
+		},
+		{
+			"exclude wins over include",
+			[]Rule{
+				{Action: ActionInclude, Type: TypeKeyword, Value: "godot"},
+				{Action: ActionExclude, Type: TypeRegex, Value: `4\.3`},
+			},
+			release,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.rules {
+				require.NoError(t, tt.rules[i].Compile())
+			}
+			assert.Equal(t, tt.expected, Allows(tt.rules, tt.item))
+		})
+	}
+}
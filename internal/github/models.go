@@ -42,7 +42,19 @@ type Repository struct {
 	TargetBranch string    `json:"target_branch"` // Branch to monitor (default: main/master)
 	AddedAt      time.Time `json:"added_at"`
 	LastChecked  time.Time `json:"last_checked,omitempty"`
-	Schedule     []string  `json:"schedule,omitempty"` // Check times in HH:MM format (e.g., ["09:00", "13:00", "18:00"])
+	Schedule     []string  `json:"schedule,omitempty"` // Schedule entries: "HH:MM", "every <duration>" (both optionally restricted to weekdays) or cron (e.g., ["09:00 mon-fri", "every 6h"])
+	Timezone     string    `json:"timezone,omitempty"` // IANA timezone the schedule is evaluated in (empty = bot's local time)
+	OwnerGuildID string    `json:"owner_guild_id,omitempty"` // Guild that registered the repository (empty = public catalog entry)
+}
+
+// IsCatalog reports whether the repository is a public catalog entry, managed by the bot owners
+func (r Repository) IsCatalog() bool {
+	return r.OwnerGuildID == ""
+}
+
+// VisibleTo reports whether channels of the guild can see and subscribe to the repository
+func (r Repository) VisibleTo(guildID string) bool {
+	return r.IsCatalog() || r.OwnerGuildID == guildID
 }
 
 // FilterConfig defines high-value filtering criteria
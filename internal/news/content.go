@@ -0,0 +1,57 @@
+package news
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// HTMLToText extracts the readable text of an HTML fragment (e.g. an item's
+// content:encoded), keeping paragraph breaks and dropping scripts and styles
+func HTMLToText(fragment string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+
+	var b strings.Builder
+	skipDepth := 0
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// io.EOF or malformed markup: keep what was extracted so far
+			return cleanText(b.String())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			tag := atom.Lookup(name)
+			if tag == atom.Script || tag == atom.Style {
+				skipDepth++
+			}
+			if isBlockElement(tag) {
+				b.WriteByte('\n')
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			tag := atom.Lookup(name)
+			if (tag == atom.Script || tag == atom.Style) && skipDepth > 0 {
+				skipDepth--
+			}
+			if isBlockElement(tag) {
+				b.WriteByte('\n')
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				b.Write(tokenizer.Text())
+			}
+		}
+	}
+}
+
+// isBlockElement reports whether an element starts a new line in the extracted text
+func isBlockElement(tag atom.Atom) bool {
+	switch tag {
+	case atom.P, atom.Br, atom.Div, atom.Li, atom.Ul, atom.Ol, atom.Blockquote, atom.Pre,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Tr, atom.Table, atom.Section, atom.Article, atom.Figure, atom.Figcaption, atom.Hr:
+		return true
+	}
+	return false
+}
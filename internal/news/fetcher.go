@@ -1,6 +1,7 @@
 package news
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -16,10 +17,18 @@ type Article struct {
 	Title       string
 	Link        string
 	Description string
-	Content     string // Cleaned article content
+	Content     string // Cleaned content provided by the feed (content:encoded or Atom <content>), if any
+	Categories  []string
 	PublishDate time.Time
 }
 
+// ErrNotModified is returned when the feed server answers 304 Not Modified
+// to a conditional request
+var ErrNotModified = errors.New("feed not modified since last fetch")
+
+// userAgent identifies the bot to the feed servers it polls
+const userAgent = "GuaraBot/1.0 (+https://github.com/GustavoLR548/guara-bot)"
+
 // NewsFetcher defines the interface for fetching and processing news
 type NewsFetcher interface {
 	// FetchLatestArticle fetches the most recent article from the RSS feed
@@ -32,9 +41,11 @@ type NewsFetcher interface {
 
 // RSSFetcher implements NewsFetcher using RSS feeds
 type RSSFetcher struct {
-	rssURL     string
-	httpClient *http.Client
-	parser     *gofeed.Parser
+	rssURL       string
+	httpClient   *http.Client
+	parser       *gofeed.Parser
+	etag         string // ETag validator sent as If-None-Match
+	lastModified string // Last-Modified validator sent as If-Modified-Since
 }
 
 // NewRSSFetcher creates a new RSS-based news fetcher
@@ -48,44 +59,79 @@ func NewRSSFetcher(rssURL string) *RSSFetcher {
 	}
 }
 
-// FetchLatestArticle fetches the most recent article from the RSS feed
-func (f *RSSFetcher) FetchLatestArticle() (*Article, error) {
-	feed, err := f.parser.ParseURL(f.rssURL)
+// SetValidators sets the HTTP cache validators sent with the next feed request.
+// When the server answers 304 Not Modified, the fetch methods return ErrNotModified.
+func (f *RSSFetcher) SetValidators(etag, lastModified string) {
+	f.etag = etag
+	f.lastModified = lastModified
+}
+
+// Validators returns the HTTP cache validators from the last successful feed response
+func (f *RSSFetcher) Validators() (etag, lastModified string) {
+	return f.etag, f.lastModified
+}
+
+// fetchFeed downloads and parses the RSS feed, sending conditional request headers
+// when validators are known and remembering the validators returned by the server
+func (f *RSSFetcher) fetchFeed() (*gofeed.Feed, error) {
+	req, err := http.NewRequest(http.MethodGet, f.rssURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
 	}
+	req.Header.Set("User-Agent", userAgent)
+	if f.etag != "" {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+	if f.lastModified != "" {
+		req.Header.Set("If-Modified-Since", f.lastModified)
+	}
 
-	if len(feed.Items) == 0 {
-		return nil, fmt.Errorf("no articles found in RSS feed")
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
 	}
+	defer resp.Body.Close()
 
-	// Get the first (most recent) item
-	item := feed.Items[0]
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
 
-	article := &Article{
-		GUID:        item.GUID,
-		Title:       item.Title,
-		Link:        item.Link,
-		Description: item.Description,
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to parse RSS feed: unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Parse publish date
-	if item.PublishedParsed != nil {
-		article.PublishDate = *item.PublishedParsed
-	} else if item.UpdatedParsed != nil {
-		article.PublishDate = *item.UpdatedParsed
-	} else {
-		article.PublishDate = time.Now()
+	feed, err := f.parser.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+	}
+
+	f.etag = resp.Header.Get("ETag")
+	f.lastModified = resp.Header.Get("Last-Modified")
+
+	return feed, nil
+}
+
+// FetchLatestArticle fetches the most recent article from the RSS feed
+func (f *RSSFetcher) FetchLatestArticle() (*Article, error) {
+	feed, err := f.fetchFeed()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(feed.Items) == 0 {
+		return nil, fmt.Errorf("no articles found in RSS feed")
 	}
 
-	return article, nil
+	// Get the first (most recent) item
+	article := articleFromItem(feed.Items[0])
+	return &article, nil
 }
 
 // FetchArticles fetches all articles from the RSS feed
 func (f *RSSFetcher) FetchArticles() ([]Article, error) {
-	feed, err := f.parser.ParseURL(f.rssURL)
+	feed, err := f.fetchFeed()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+		return nil, err
 	}
 
 	if len(feed.Items) == 0 {
@@ -94,26 +140,41 @@ func (f *RSSFetcher) FetchArticles() ([]Article, error) {
 
 	articles := make([]Article, 0, len(feed.Items))
 	for _, item := range feed.Items {
-		article := Article{
-			GUID:        item.GUID,
-			Title:       item.Title,
-			Link:        item.Link,
-			Description: item.Description,
-		}
+		articles = append(articles, articleFromItem(item))
+	}
 
-		// Parse publish date
-		if item.PublishedParsed != nil {
-			article.PublishDate = *item.PublishedParsed
-		} else if item.UpdatedParsed != nil {
-			article.PublishDate = *item.UpdatedParsed
-		} else {
-			article.PublishDate = time.Now()
-		}
+	return articles, nil
+}
 
-		articles = append(articles, article)
+// articleFromItem converts a parsed feed item into an Article
+func articleFromItem(item *gofeed.Item) Article {
+	article := Article{
+		GUID:        itemGUID(item),
+		Title:       item.Title,
+		Link:        item.Link,
+		Description: item.Description,
+		Content:     HTMLToText(item.Content),
+		Categories:  item.Categories,
 	}
 
-	return articles, nil
+	// Parse publish date
+	if item.PublishedParsed != nil {
+		article.PublishDate = *item.PublishedParsed
+	} else if item.UpdatedParsed != nil {
+		article.PublishDate = *item.UpdatedParsed
+	} else {
+		article.PublishDate = time.Now()
+	}
+
+	return article
+}
+
+// itemGUID returns the item's GUID, falling back to its link for feeds that omit GUIDs
+func itemGUID(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	return item.Link
 }
 
 // ScrapeArticleContent fetches and cleans the full article content
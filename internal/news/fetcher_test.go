@@ -403,3 +403,199 @@ func TestRSSFetcher_Integration(t *testing.T) {
 	assert.Equal(t, "https://godotengine.org/article/godot-4-3-released", article.Link)
 	assert.Contains(t, article.Description, "Godot 4.3")
 }
+
+// TestRSSFetcher_ConditionalFetch tests ETag/Last-Modified handling
+func TestRSSFetcher_ConditionalFetch(t *testing.T) {
+	rssContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Test Feed</title>
+    <item>
+      <guid>article-1</guid>
+      <title>Article 1</title>
+      <link>https://example.com/1</link>
+    </item>
+  </channel>
+</rss>`
+	const etag = `"v1"`
+	const lastModified = "Mon, 01 Jan 2024 12:00:00 GMT"
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		if _, err := w.Write([]byte(rssContent)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	// First fetch has no validators and receives the full feed
+	fetcher := NewRSSFetcher(server.URL)
+	articles, err := fetcher.FetchArticles()
+	require.NoError(t, err)
+	assert.Len(t, articles, 1)
+
+	gotETag, gotLastModified := fetcher.Validators()
+	assert.Equal(t, etag, gotETag)
+	assert.Equal(t, lastModified, gotLastModified)
+
+	// A new fetcher primed with the stored validators gets a 304
+	fetcher = NewRSSFetcher(server.URL)
+	fetcher.SetValidators(gotETag, gotLastModified)
+	_, err = fetcher.FetchArticles()
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	_, err = fetcher.FetchLatestArticle()
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	// Validators are kept after a 304
+	gotETag, _ = fetcher.Validators()
+	assert.Equal(t, etag, gotETag)
+	assert.Equal(t, 3, requests)
+}
+
+// TestRSSFetcher_FetchArticles_HTTPError tests non-2xx feed responses
+func TestRSSFetcher_FetchArticles_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	fetcher := NewRSSFetcher(server.URL)
+	_, err := fetcher.FetchArticles()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 403")
+}
+
+// TestRSSFetcher_FetchArticles_FeedContent tests that feed-provided content fills Article.Content
+func TestRSSFetcher_FetchArticles_FeedContent(t *testing.T) {
+	tests := []struct {
+		name            string
+		contentType     string
+		feedContent     string
+		expectedContent string
+	}{
+		{
+			name:        "RSS content:encoded",
+			contentType: "application/rss+xml",
+			feedContent: `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <title>Test Feed</title>
+    <item>
+      <guid>item-1</guid>
+      <title>Release</title>
+      <link>https://example.com/release</link>
+      <description>Short teaser</description>
+      <content:encoded><![CDATA[<p>First paragraph &amp; more.</p><p>Second paragraph.</p>]]></content:encoded>
+    </item>
+  </channel>
+</rss>`,
+			expectedContent: "First paragraph & more.\nSecond paragraph.",
+		},
+		{
+			name:        "Atom content",
+			contentType: "application/atom+xml",
+			feedContent: `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Test Feed</title>
+  <entry>
+    <id>entry-1</id>
+    <title>Release</title>
+    <link href="https://example.com/release"/>
+    <summary>Short teaser</summary>
+    <content type="html">&lt;h2&gt;Highlights&lt;/h2&gt;&lt;ul&gt;&lt;li&gt;Faster builds&lt;/li&gt;&lt;/ul&gt;</content>
+  </entry>
+</feed>`,
+			expectedContent: "Highlights\nFaster builds",
+		},
+		{
+			name:        "no feed content",
+			contentType: "application/rss+xml",
+			feedContent: `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Test Feed</title>
+    <item>
+      <guid>item-1</guid>
+      <title>Release</title>
+      <link>https://example.com/release</link>
+      <description>Short teaser</description>
+    </item>
+  </channel>
+</rss>`,
+			expectedContent: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				if _, err := w.Write([]byte(tt.feedContent)); err != nil {
+					t.Errorf("Failed to write response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			fetcher := NewRSSFetcher(server.URL)
+			articles, err := fetcher.FetchArticles()
+			require.NoError(t, err)
+			require.Len(t, articles, 1)
+			assert.Equal(t, "Short teaser", articles[0].Description)
+			assert.Equal(t, tt.expectedContent, articles[0].Content)
+		})
+	}
+}
+
+// TestHTMLToText tests text extraction from feed-provided HTML
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "paragraphs and line breaks",
+			input:    "<p>Line 1<br>Line 2</p><p>Line 3</p>",
+			expected: "Line 1\nLine 2\nLine 3",
+		},
+		{
+			name:     "inline elements stay on the line",
+			input:    "<p>Read <a href=\"/notes\">the <strong>release</strong> notes</a>.</p>",
+			expected: "Read the release notes.",
+		},
+		{
+			name:     "scripts and styles are dropped",
+			input:    "<style>p { color: red; }</style><p>Visible</p><script>alert(1)</script>",
+			expected: "Visible",
+		},
+		{
+			name:     "entities are decoded",
+			input:    "<p>Godot &amp; friends &lt;3</p>",
+			expected: "Godot & friends <3",
+		},
+		{
+			name:     "plain text",
+			input:    "Just text",
+			expected: "Just text",
+		},
+		{
+			name:     "empty input",
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, HTMLToText(tt.input))
+		})
+	}
+}
@@ -154,6 +154,122 @@ func (m *Manager) WaitForCapacity(ctx context.Context, estimatedTokens int) erro
 	}
 }
 
+// Reservation is capacity reserved for a single API request by Reserve
+type Reservation struct {
+	manager     *Manager
+	tokens      int
+	windowStart time.Time
+	done        bool
+}
+
+// Reserve blocks until a request fits within the rate limits and reserves its capacity.
+// Unlike CanMakeRequest followed by RecordRequest, checking and counting happen atomically,
+// so concurrent callers cannot all pass the check before any of them is recorded.
+// The caller must finish the reservation with Complete or Fail.
+func (m *Manager) Reserve(ctx context.Context, estimatedTokens int) (*Reservation, error) {
+	// A request above the per-request limit can never be satisfied
+	if estimatedTokens > m.config.MaxTokensPerRequest {
+		return nil, fmt.Errorf("request exceeds max tokens per request (%d > %d)",
+			estimatedTokens, m.config.MaxTokensPerRequest)
+	}
+
+	for {
+		reservation, waitTime := m.tryReserve(estimatedTokens)
+		if reservation != nil {
+			return reservation, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(waitTime):
+			// Continue loop to check again
+		}
+	}
+}
+
+// tryReserve reserves capacity if available, otherwise returns how long to wait
+func (m *Manager) tryReserve(estimatedTokens int) (*Reservation, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check circuit breaker
+	if m.circuitOpen {
+		if remaining := m.config.CircuitBreakerTimeout - time.Since(m.lastFailureTime); remaining > 0 {
+			return nil, remaining
+		}
+	}
+
+	// Reset window if needed
+	if time.Since(m.windowStart) >= time.Minute {
+		m.requestCount = 0
+		m.tokenCount = 0
+		m.windowStart = time.Now()
+	}
+
+	if m.requestCount >= m.config.MaxRequestsPerMinute ||
+		m.tokenCount+estimatedTokens > m.config.MaxTokensPerMinute {
+		waitTime := time.Minute - time.Since(m.windowStart)
+		if waitTime <= 0 {
+			waitTime = 10 * time.Millisecond
+		}
+		return nil, waitTime
+	}
+
+	m.requestCount++
+	m.tokenCount += estimatedTokens
+
+	return &Reservation{
+		manager:     m,
+		tokens:      estimatedTokens,
+		windowStart: m.windowStart,
+	}, 0
+}
+
+// Complete records a successful request, replacing the reserved token estimate
+// with the actual usage, and resets the circuit breaker
+func (r *Reservation) Complete(actualTokens int) {
+	m := r.manager
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.done {
+		return
+	}
+	r.done = true
+
+	// Only adjust the window the reservation was counted in
+	if m.windowStart.Equal(r.windowStart) {
+		m.tokenCount += actualTokens - r.tokens
+	}
+	m.totalRequests++
+	m.totalTokens += int64(actualTokens)
+
+	m.failureCount = 0
+	m.circuitOpen = false
+}
+
+// Fail records a failed request for the circuit breaker. The request still counts
+// against the rate limits, since it was sent to the API.
+func (r *Reservation) Fail() {
+	m := r.manager
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.done {
+		return
+	}
+	r.done = true
+
+	m.failureCount++
+	m.totalFailures++
+	m.lastFailureTime = time.Now()
+
+	if m.failureCount >= m.config.CircuitBreakerThreshold {
+		m.circuitOpen = true
+	}
+}
+
 // getWaitTime calculates how long to wait before retrying
 func (m *Manager) getWaitTime() time.Duration {
 	m.mu.RLock()
@@ -3,6 +3,7 @@ package ratelimit
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -432,3 +433,77 @@ func TestManager_CircuitBreakerRecovery(t *testing.T) {
 	require.NotNil(t, stats)
 	assert.False(t, stats.CircuitOpen)
 }
+
+func TestManager_Reserve(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxRequestsPerMinute = 2
+	manager := NewManager(config)
+
+	ctx := context.Background()
+
+	first, err := manager.Reserve(ctx, 1000)
+	require.NoError(t, err)
+	second, err := manager.Reserve(ctx, 1000)
+	require.NoError(t, err)
+
+	// Both requests are counted as soon as they are reserved
+	stats := manager.GetStatistics()
+	assert.Equal(t, 2, stats.CurrentWindowRequests)
+	assert.Equal(t, 2000, stats.CurrentWindowTokens)
+
+	// The window is full: a third reservation waits until the context gives up
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = manager.Reserve(timeoutCtx, 1000)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Completing replaces the estimate with the actual usage
+	first.Complete(1500)
+	second.Fail()
+	second.Fail() // finishing twice has no effect
+
+	stats = manager.GetStatistics()
+	assert.Equal(t, 2, stats.CurrentWindowRequests)
+	assert.Equal(t, 2500, stats.CurrentWindowTokens)
+	assert.Equal(t, int64(1), stats.TotalRequests)
+	assert.Equal(t, int64(1500), stats.TotalTokens)
+	assert.Equal(t, int64(1), stats.TotalFailures)
+}
+
+func TestManager_Reserve_ExceedsPerRequestLimit(t *testing.T) {
+	manager := NewManager(DefaultConfig())
+
+	_, err := manager.Reserve(context.Background(), 5000)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max tokens per request")
+}
+
+func TestManager_Reserve_Concurrent(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxRequestsPerMinute = 5
+	manager := NewManager(config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Many concurrent callers may only get as many reservations as the limit allows
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	granted := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if reservation, err := manager.Reserve(ctx, 100); err == nil {
+				mu.Lock()
+				granted++
+				mu.Unlock()
+				reservation.Complete(100)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 5, granted)
+	assert.Equal(t, 5, manager.GetStatistics().CurrentWindowRequests)
+}
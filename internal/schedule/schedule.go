@@ -0,0 +1,80 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the IANA timezone database so schedules work on images without tzdata
+	_ "time/tzdata"
+)
+
+// LoadLocation resolves an IANA timezone name (e.g. "America/Sao_Paulo").
+// An empty name means the bot's local timezone.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q (use an IANA name such as America/Sao_Paulo or Europe/Berlin)", name)
+	}
+	return loc, nil
+}
+
+// SlotTime returns the instant a daily HH:MM slot fires on the given date in loc.
+// DST transitions are handled so that every slot fires exactly once per day:
+//   - a wall time repeated when clocks go back fires at its first occurrence
+//   - a wall time skipped when clocks go forward fires right after the jump
+func SlotTime(year int, month time.Month, day, hour, min int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, min, 0, 0, loc)
+
+	if wallClockIs(t, year, month, day, hour, min) {
+		// Prefer the earliest instant showing this wall time (clocks going back)
+		for _, shift := range []time.Duration{time.Hour, 30 * time.Minute} {
+			if earlier := t.Add(-shift); wallClockIs(earlier, year, month, day, hour, min) {
+				return earlier
+			}
+		}
+		return t
+	}
+
+	// The wall time does not exist that day (clocks going forward): fire at the
+	// first minute whose wall time is past the requested one
+	target := time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	for m := t.Add(-3 * time.Hour).Truncate(time.Minute); m.Before(t.Add(3 * time.Hour)); m = m.Add(time.Minute) {
+		local := m.In(loc)
+		wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), 0, 0, time.UTC)
+		if !wall.Before(target) {
+			return m
+		}
+	}
+	return t
+}
+
+// wallClockIs reports whether t shows the given date and HH:MM in its location
+func wallClockIs(t time.Time, year int, month time.Month, day, hour, min int) bool {
+	y, mo, d := t.Date()
+	return y == year && mo == month && d == day && t.Hour() == hour && t.Minute() == min
+}
+
+// parseClock parses a 24-hour HH:MM time
+func parseClock(s string) (int, int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, 0, fmt.Errorf("invalid time %q (expected HH:MM)", s)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid hour in %q", s)
+	}
+	min, err := strconv.Atoi(parts[1])
+	if err != nil || min < 0 || min > 59 {
+		return 0, 0, fmt.Errorf("invalid minute in %q", s)
+	}
+
+	return hour, min, nil
+}
@@ -0,0 +1,46 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("")
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, loc)
+
+	loc, err = LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	assert.Equal(t, "America/Sao_Paulo", loc.String())
+
+	_, err = LoadLocation("Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
+
+func TestSlotTime_DSTTransitions(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	// 2024-03-31: clocks jump from 02:00 to 03:00, so 02:30 fires right after the jump
+	slot := SlotTime(2024, time.March, 31, 2, 30, berlin)
+	assert.Equal(t, time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC), slot.UTC())
+	assert.Equal(t, "03:00", slot.In(berlin).Format("15:04"))
+
+	// 2024-10-27: clocks go back from 03:00 to 02:00, so 02:30 fires at its first occurrence
+	slot = SlotTime(2024, time.October, 27, 2, 30, berlin)
+	assert.Equal(t, time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC), slot.UTC())
+
+	// Times away from the transition are unaffected
+	slot = SlotTime(2024, time.October, 27, 9, 0, berlin)
+	assert.Equal(t, time.Date(2024, 10, 27, 8, 0, 0, 0, time.UTC), slot.UTC())
+}
@@ -0,0 +1,368 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minutesPerDay = 24 * 60
+
+	// maxSearchDays bounds the search for the next run; long enough for
+	// specs that only fire on February 29th
+	maxSearchDays = 8*366 + 1
+)
+
+var (
+	monthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	weekdayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+// Spec is a parsed schedule entry. Supported forms:
+//   - "HH:MM" fires daily at that wall-clock time, e.g. "09:00"
+//   - "every <duration>" fires every interval counted from midnight, e.g. "every 30m", "every 2h"
+//   - a 5-field cron expression (minute hour day-of-month month day-of-week), e.g. "*/30 9-18 * * 1-5"
+//
+// The first two forms accept an optional weekday restriction such as "mon-fri" or "sat,sun".
+type Spec struct {
+	expr    string
+	minutes [minutesPerDay]bool // minute-of-day mask
+
+	dom     [32]bool
+	months  [13]bool
+	dow     [7]bool
+	domStar bool
+	dowStar bool
+}
+
+// Parse parses a single schedule entry
+func Parse(expr string) (*Spec, error) {
+	fields := strings.Fields(strings.ToLower(expr))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("schedule cannot be empty")
+	}
+
+	spec := &Spec{expr: strings.Join(fields, " ")}
+	var err error
+
+	switch {
+	case fields[0] == "every":
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid schedule %q: expected \"every <duration> [weekdays]\"", expr)
+		}
+		err = spec.parseInterval(fields[1], fields[2:])
+	case len(fields) == 5:
+		err = spec.parseCron(fields)
+	case len(fields) <= 2:
+		err = spec.parseDaily(fields[0], fields[1:])
+	default:
+		err = fmt.Errorf("expected HH:MM, \"every <duration>\" or a 5-field cron expression")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", expr, err)
+	}
+
+	// Reject specs that can never fire (e.g. "0 0 31 2 *")
+	if _, ok := spec.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.UTC); !ok {
+		return nil, fmt.Errorf("invalid schedule %q: never fires", expr)
+	}
+
+	return spec, nil
+}
+
+// ParseAll parses a list of schedule entries
+func ParseAll(exprs []string) ([]*Spec, error) {
+	specs := make([]*Spec, 0, len(exprs))
+	for _, expr := range exprs {
+		spec, err := Parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// Every returns a spec firing every d, counted from midnight
+func Every(d time.Duration) (*Spec, error) {
+	if d%time.Hour == 0 {
+		return Parse(fmt.Sprintf("every %dh", d/time.Hour))
+	}
+	return Parse(fmt.Sprintf("every %dm", d/time.Minute))
+}
+
+// String returns the normalized expression
+func (s *Spec) String() string {
+	return s.expr
+}
+
+// Next returns the first time strictly after the given instant at which the spec fires,
+// with wall-clock times interpreted in loc. DST is handled as described in SlotTime.
+func (s *Spec) Next(after time.Time, loc *time.Location) (time.Time, bool) {
+	year, month, day := after.In(loc).Date()
+
+	// Start one day early in case a DST shift moved a slot of the previous day past midnight
+	for i := -1; i < maxSearchDays; i++ {
+		date := time.Date(year, month, day+i, 12, 0, 0, 0, loc)
+		y, m, d := date.Date()
+		if !s.matchesDay(y, m, d, date.Weekday()) {
+			continue
+		}
+
+		for minute := 0; minute < minutesPerDay; minute++ {
+			if !s.minutes[minute] {
+				continue
+			}
+			if t := SlotTime(y, m, d, minute/60, minute%60, loc); t.After(after) {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Due reports whether any of the specs fires in the window (since, now]
+func Due(specs []*Spec, loc *time.Location, since, now time.Time) bool {
+	for _, spec := range specs {
+		if next, ok := spec.Next(since, loc); ok && !next.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextRun returns the earliest time after the given instant at which any of the specs fires
+func NextRun(specs []*Spec, loc *time.Location, after time.Time) (time.Time, bool) {
+	var earliest time.Time
+	found := false
+	for _, spec := range specs {
+		if next, ok := spec.Next(after, loc); ok && (!found || next.Before(earliest)) {
+			earliest = next
+			found = true
+		}
+	}
+	return earliest, found
+}
+
+// SplitSpecs splits user input into schedule entries. Entries are separated by ";".
+// Without a ";", a comma-separated list of entries (e.g. "09:00,13:00") is split on commas,
+// while input whose comma-separated parts are not valid on their own is kept as a single
+// entry, so cron lists like "0 9,13 * * *" work unquoted.
+func SplitSpecs(input string) []string {
+	sep := ";"
+	if !strings.Contains(input, ";") {
+		sep = ","
+		for _, part := range strings.Split(input, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			if _, err := Parse(part); err != nil {
+				sep = ""
+				break
+			}
+		}
+	}
+
+	var parts []string
+	if sep == "" {
+		parts = []string{input}
+	} else {
+		parts = strings.Split(input, sep)
+	}
+
+	entries := []string{}
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			entries = append(entries, part)
+		}
+	}
+	return entries
+}
+
+// parseDaily parses "HH:MM [weekdays]"
+func (s *Spec) parseDaily(clock string, rest []string) error {
+	hour, min, err := parseClock(clock)
+	if err != nil {
+		return err
+	}
+	s.minutes[hour*60+min] = true
+	return s.parseWeekdays(rest)
+}
+
+// parseInterval parses the duration and optional weekdays of "every <duration> [weekdays]"
+func (s *Spec) parseInterval(value string, rest []string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q (e.g. 30m, 2h, 1h30m)", value)
+	}
+	if d < time.Minute || d > 24*time.Hour || d%time.Minute != 0 {
+		return fmt.Errorf("interval must be a whole number of minutes between 1m and 24h")
+	}
+
+	step := int(d / time.Minute)
+	for minute := 0; minute < minutesPerDay; minute += step {
+		s.minutes[minute] = true
+	}
+	return s.parseWeekdays(rest)
+}
+
+// parseWeekdays parses an optional weekday restriction such as "mon-fri"
+func (s *Spec) parseWeekdays(rest []string) error {
+	s.domStar = true
+	for day := 1; day <= 31; day++ {
+		s.dom[day] = true
+	}
+	for month := 1; month <= 12; month++ {
+		s.months[month] = true
+	}
+
+	if len(rest) == 0 {
+		s.dowStar = true
+		for day := range s.dow {
+			s.dow[day] = true
+		}
+		return nil
+	}
+
+	return s.setWeekdays(rest[0])
+}
+
+// parseCron parses the five fields of a cron expression
+func (s *Spec) parseCron(fields []string) error {
+	minutes, _, err := parseField(fields[0], 0, 59, nil)
+	if err != nil {
+		return fmt.Errorf("minute: %w", err)
+	}
+	hours, _, err := parseField(fields[1], 0, 23, nil)
+	if err != nil {
+		return fmt.Errorf("hour: %w", err)
+	}
+	for _, hour := range hours {
+		for _, minute := range minutes {
+			s.minutes[hour*60+minute] = true
+		}
+	}
+
+	days, star, err := parseField(fields[2], 1, 31, nil)
+	if err != nil {
+		return fmt.Errorf("day of month: %w", err)
+	}
+	s.domStar = star
+	for _, day := range days {
+		s.dom[day] = true
+	}
+
+	months, _, err := parseField(fields[3], 1, 12, monthNames)
+	if err != nil {
+		return fmt.Errorf("month: %w", err)
+	}
+	for _, month := range months {
+		s.months[month] = true
+	}
+
+	return s.setWeekdays(fields[4])
+}
+
+// setWeekdays parses a day-of-week field (0-7 or sun-sat, where 7 is Sunday)
+func (s *Spec) setWeekdays(field string) error {
+	days, star, err := parseField(field, 0, 7, weekdayNames)
+	if err != nil {
+		return fmt.Errorf("day of week: %w", err)
+	}
+	s.dowStar = star
+	for _, day := range days {
+		s.dow[day%7] = true
+	}
+	return nil
+}
+
+// matchesDay reports whether the spec fires on the given date. As in cron, when both
+// day-of-month and day-of-week are restricted, either one matching is enough.
+func (s *Spec) matchesDay(year int, month time.Month, day int, weekday time.Weekday) bool {
+	if !s.months[month] {
+		return false
+	}
+
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return s.dow[weekday]
+	case s.dowStar:
+		return s.dom[day]
+	default:
+		return s.dom[day] || s.dow[weekday]
+	}
+}
+
+// parseField parses a cron field ("*", "*/15", "1-5", "mon,wed", "9-18/2", ...) into its values.
+// star reports whether the field is unrestricted ("*" or "*/n").
+func parseField(field string, min, max int, names map[string]int) (values []int, star bool, err error) {
+	set := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return nil, false, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		low, high := min, max
+		switch {
+		case rangePart == "*":
+			star = star || part == field
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			if low, err = parseValue(bounds[0], min, max, names); err != nil {
+				return nil, false, err
+			}
+			if high, err = parseValue(bounds[1], min, max, names); err != nil {
+				return nil, false, err
+			}
+			if low > high {
+				return nil, false, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			if low, err = parseValue(rangePart, min, max, names); err != nil {
+				return nil, false, err
+			}
+			if step == 1 {
+				high = low
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			set[v] = true
+		}
+	}
+
+	for v := min; v <= max; v++ {
+		if set[v] {
+			values = append(values, v)
+		}
+	}
+	return values, star, nil
+}
+
+// parseValue parses a single cron value, accepting names when provided
+func parseValue(value string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[value]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", value, min, max)
+	}
+	return v, nil
+}
@@ -0,0 +1,240 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParse(t *testing.T, expr string) *Spec {
+	t.Helper()
+	spec, err := Parse(expr)
+	require.NoError(t, err)
+	return spec
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr  string
+		valid bool
+	}{
+		{"09:00", true},
+		{"23:59", true},
+		{"09:00 mon-fri", true},
+		{"18:30 sat,sun", true},
+		{"every 30m", true},
+		{"every 2h", true},
+		{"every 1h30m", true},
+		{"Every 2h Mon-Fri", true},
+		{"*/30 9-18 * * 1-5", true},
+		{"0 9,13,18 * * *", true},
+		{"0 12 1 jan-mar *", true},
+		{"0 8 * * sun", true},
+		{"0 8 * * 7", true},
+		{"", false},
+		{"9:00", false},
+		{"24:00", false},
+		{"12:60", false},
+		{"09:00 someday", false},
+		{"every", false},
+		{"every 30s", false},
+		{"every 90s", false},
+		{"every 25h", false},
+		{"every soon", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"0 9 * * 8", false},
+		{"0 9 * *", false},
+		{"0 9-5 * * *", false},
+		{"*/0 * * * *", false},
+		{"0 0 31 2 *", false}, // never fires
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSpec_Next(t *testing.T) {
+	utc := time.UTC
+	saoPaulo := mustLoad(t, "America/Sao_Paulo")
+
+	// Wednesday 2025-06-04
+	wednesday := time.Date(2025, 6, 4, 10, 7, 0, 0, utc)
+
+	tests := []struct {
+		name     string
+		expr     string
+		loc      *time.Location
+		after    time.Time
+		expected time.Time
+	}{
+		{
+			name:     "daily time later today",
+			expr:     "18:00",
+			loc:      utc,
+			after:    wednesday,
+			expected: time.Date(2025, 6, 4, 18, 0, 0, 0, utc),
+		},
+		{
+			name:     "daily time already passed",
+			expr:     "09:00",
+			loc:      utc,
+			after:    wednesday,
+			expected: time.Date(2025, 6, 5, 9, 0, 0, 0, utc),
+		},
+		{
+			name:     "strictly after",
+			expr:     "09:00",
+			loc:      utc,
+			after:    time.Date(2025, 6, 4, 9, 0, 0, 0, utc),
+			expected: time.Date(2025, 6, 5, 9, 0, 0, 0, utc),
+		},
+		{
+			name:     "weekday restriction skips the weekend",
+			expr:     "09:00 mon-fri",
+			loc:      utc,
+			after:    time.Date(2025, 6, 6, 12, 0, 0, 0, utc), // Friday
+			expected: time.Date(2025, 6, 9, 9, 0, 0, 0, utc),  // Monday
+		},
+		{
+			name:     "interval counted from midnight",
+			expr:     "every 2h",
+			loc:      utc,
+			after:    wednesday,
+			expected: time.Date(2025, 6, 4, 12, 0, 0, 0, utc),
+		},
+		{
+			name:     "interval not dividing the day restarts at midnight",
+			expr:     "every 7h",
+			loc:      utc,
+			after:    time.Date(2025, 6, 4, 21, 30, 0, 0, utc),
+			expected: time.Date(2025, 6, 5, 0, 0, 0, 0, utc),
+		},
+		{
+			name:     "cron step within working hours",
+			expr:     "*/30 9-18 * * 1-5",
+			loc:      utc,
+			after:    wednesday,
+			expected: time.Date(2025, 6, 4, 10, 30, 0, 0, utc),
+		},
+		{
+			name:     "cron outside working hours",
+			expr:     "*/30 9-18 * * 1-5",
+			loc:      utc,
+			after:    time.Date(2025, 6, 6, 18, 45, 0, 0, utc), // Friday evening
+			expected: time.Date(2025, 6, 9, 9, 0, 0, 0, utc),
+		},
+		{
+			name:     "cron day-of-month or day-of-week",
+			expr:     "0 12 15 * sun",
+			loc:      utc,
+			after:    wednesday,
+			expected: time.Date(2025, 6, 8, 12, 0, 0, 0, utc), // Sunday before the 15th
+		},
+		{
+			name:     "evaluated in the schedule's timezone",
+			expr:     "09:00",
+			loc:      saoPaulo,
+			after:    wednesday,
+			expected: time.Date(2025, 6, 4, 12, 0, 0, 0, utc),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := mustParse(t, tt.expr).Next(tt.after, tt.loc)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, next.UTC())
+		})
+	}
+}
+
+func TestSpec_Next_DST(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	// Every 15 minutes across the spring-forward gap: the 02:xx slots collapse into one run at 03:00
+	spec := mustParse(t, "*/15 * * * *")
+	next, ok := spec.Next(time.Date(2024, 3, 31, 0, 50, 0, 0, time.UTC), berlin) // 01:50 CET
+	require.True(t, ok)
+	assert.Equal(t, "03:00", next.In(berlin).Format("15:04"))
+	next, ok = spec.Next(next, berlin)
+	require.True(t, ok)
+	assert.Equal(t, "03:15", next.In(berlin).Format("15:04"))
+
+	// A daily slot in the repeated hour fires only once
+	spec = mustParse(t, "02:30")
+	first, ok := spec.Next(time.Date(2024, 10, 26, 12, 0, 0, 0, time.UTC), berlin)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC), first.UTC())
+	second, ok := spec.Next(first, berlin)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 10, 28, 1, 30, 0, 0, time.UTC), second.UTC())
+}
+
+func TestDue(t *testing.T) {
+	specs, err := ParseAll([]string{"09:00", "every 6h"})
+	require.NoError(t, err)
+
+	base := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+
+	// The window is (since, now]
+	assert.True(t, Due(specs, time.UTC, base.Add(8*time.Hour+59*time.Minute), base.Add(9*time.Hour)))
+	assert.True(t, Due(specs, time.UTC, base.Add(5*time.Hour+59*time.Minute+30*time.Second), base.Add(6*time.Hour+30*time.Second)))
+	assert.False(t, Due(specs, time.UTC, base.Add(9*time.Hour), base.Add(9*time.Hour+time.Minute)))
+	assert.False(t, Due(nil, time.UTC, base, base.Add(24*time.Hour)))
+}
+
+func TestNextRun(t *testing.T) {
+	specs, err := ParseAll([]string{"18:00", "09:00 mon-fri"})
+	require.NoError(t, err)
+
+	next, ok := NextRun(specs, time.UTC, time.Date(2025, 6, 4, 10, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 6, 4, 18, 0, 0, 0, time.UTC), next)
+
+	_, ok = NextRun(nil, time.UTC, time.Now())
+	assert.False(t, ok)
+}
+
+func TestEvery(t *testing.T) {
+	spec, err := Every(2 * time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "every 2h", spec.String())
+
+	spec, err = Every(15 * time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "every 15m", spec.String())
+
+	_, err = Every(48 * time.Hour)
+	assert.Error(t, err)
+}
+
+func TestSplitSpecs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"09:00,13:00, 18:00", []string{"09:00", "13:00", "18:00"}},
+		{"0 9,13 * * *", []string{"0 9,13 * * *"}},
+		{"09:00 mon,fri", []string{"09:00 mon,fri"}},
+		{"0 9,13 * * 1-5; every 6h sat,sun", []string{"0 9,13 * * 1-5", "every 6h sat,sun"}},
+		{"every 2h", []string{"every 2h"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SplitSpecs(tt.input))
+		})
+	}
+}
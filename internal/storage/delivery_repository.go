@@ -0,0 +1,237 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	deliveriesKey      = "deliveries:%s"            // deliveries:{itemKey} (HASH channelID -> delivered at)
+	deliverySourceKey  = "deliveries:%s:source"     // deliveries:{itemKey}:source (STRING, where the posted content came from)
+	deliveryRetryKey   = "deliveries:retry:%s"      // deliveries:retry:{kind} (ZSET retryID -> next attempt)
+	deliveryRetryData  = "deliveries:retry:%s:data" // deliveries:retry:{kind}:data (HASH retryID -> JSON)
+	deliveryRecordTTL  = 30 * 24 * time.Hour
+	deliveryRetryIDSep = "|"
+)
+
+// DeliveryRetry is a failed post of an item to a channel waiting to be sent again
+type DeliveryRetry struct {
+	Kind        string    `json:"kind"`      // "rss" or "github"
+	ItemKey     string    `json:"item_key"`  // identifies the posted item (article or PR batch)
+	SourceID    string    `json:"source_id"` // feed or repository ID
+	ChannelID   string    `json:"channel_id"`
+	Payload     []byte    `json:"payload"`  // the message to send (JSON encoded embed)
+	Attempts    int       `json:"attempts"` // failed attempts so far
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error"`
+}
+
+// DeliveryRepository tracks which items were posted to which channels and queues failed posts for retry
+type DeliveryRepository interface {
+	// IsDelivered checks if an item was already posted to a channel
+	IsDelivered(itemKey, channelID string) (bool, error)
+	// MarkDelivered records that an item was posted to a channel
+	MarkDelivered(itemKey, channelID string) error
+	// SetContentSource records where the posted content of an item came from (e.g. scraped page or title only)
+	SetContentSource(itemKey, source string) error
+	// GetContentSource returns where the posted content of an item came from, or "" when unknown
+	GetContentSource(itemKey string) (string, error)
+	// ScheduleRetry queues (or reschedules) a failed post for retry at retry.NextAttempt
+	ScheduleRetry(retry DeliveryRetry) error
+	// GetDueRetries returns up to limit retries of the given kind due at or before now, oldest first
+	GetDueRetries(kind string, now time.Time, limit int) ([]DeliveryRetry, error)
+	// RemoveRetry removes a retry from the queue
+	RemoveRetry(kind, itemKey, channelID string) error
+	// RemoveChannelRetries removes every queued retry of the given kind for a channel
+	RemoveChannelRetries(kind, channelID string) error
+}
+
+// RedisDeliveryRepository implements DeliveryRepository using Redis
+type RedisDeliveryRepository struct {
+	client *redis.Client
+}
+
+// NewRedisDeliveryRepository creates a new Redis-based delivery repository
+func NewRedisDeliveryRepository(client *redis.Client) *RedisDeliveryRepository {
+	return &RedisDeliveryRepository{
+		client: client,
+	}
+}
+
+// IsDelivered checks if an item was already posted to a channel
+func (r *RedisDeliveryRepository) IsDelivered(itemKey, channelID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	delivered, err := r.client.HExists(ctx, fmt.Sprintf(deliveriesKey, itemKey), channelID).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check delivery: %w", err)
+	}
+	return delivered, nil
+}
+
+// MarkDelivered records that an item was posted to a channel. Records expire after 30 days.
+func (r *RedisDeliveryRepository) MarkDelivered(itemKey, channelID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	key := fmt.Sprintf(deliveriesKey, itemKey)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, channelID, time.Now().Unix())
+	pipe.Expire(ctx, key, deliveryRecordTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record delivery: %w", err)
+	}
+	return nil
+}
+
+// SetContentSource records where the posted content of an item came from. Records expire
+// with the delivery records.
+func (r *RedisDeliveryRepository) SetContentSource(itemKey, source string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := r.client.Set(ctx, fmt.Sprintf(deliverySourceKey, itemKey), source, deliveryRecordTTL).Err(); err != nil {
+		return fmt.Errorf("failed to record content source: %w", err)
+	}
+	return nil
+}
+
+// GetContentSource returns where the posted content of an item came from, or "" when unknown
+func (r *RedisDeliveryRepository) GetContentSource(itemKey string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	source, err := r.client.Get(ctx, fmt.Sprintf(deliverySourceKey, itemKey)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get content source: %w", err)
+	}
+	return source, nil
+}
+
+// ScheduleRetry queues (or reschedules) a failed post for retry at retry.NextAttempt
+func (r *RedisDeliveryRepository) ScheduleRetry(retry DeliveryRetry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(retry)
+	if err != nil {
+		return fmt.Errorf("failed to serialize retry: %w", err)
+	}
+
+	id := deliveryRetryID(retry.ItemKey, retry.ChannelID)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, fmt.Sprintf(deliveryRetryData, retry.Kind), id, data)
+	pipe.ZAdd(ctx, fmt.Sprintf(deliveryRetryKey, retry.Kind), redis.Z{
+		Score:  float64(retry.NextAttempt.Unix()),
+		Member: id,
+	})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to schedule retry: %w", err)
+	}
+	return nil
+}
+
+// GetDueRetries returns up to limit retries of the given kind due at or before now, oldest first
+func (r *RedisDeliveryRepository) GetDueRetries(kind string, now time.Time, limit int) ([]DeliveryRetry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	ids, err := r.client.ZRangeByScore(ctx, fmt.Sprintf(deliveryRetryKey, kind), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due retries: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values, err := r.client.HMGet(ctx, fmt.Sprintf(deliveryRetryData, kind), ids...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retry data: %w", err)
+	}
+
+	retries := make([]DeliveryRetry, 0, len(values))
+	for idx, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// Data is missing, drop the orphaned queue entry
+			r.client.ZRem(ctx, fmt.Sprintf(deliveryRetryKey, kind), ids[idx])
+			continue
+		}
+
+		var retry DeliveryRetry
+		if err := json.Unmarshal([]byte(data), &retry); err != nil {
+			return nil, fmt.Errorf("failed to parse retry %s: %w", ids[idx], err)
+		}
+		retries = append(retries, retry)
+	}
+
+	return retries, nil
+}
+
+// RemoveRetry removes a retry from the queue
+func (r *RedisDeliveryRepository) RemoveRetry(kind, itemKey, channelID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	id := deliveryRetryID(itemKey, channelID)
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, fmt.Sprintf(deliveryRetryKey, kind), id)
+	pipe.HDel(ctx, fmt.Sprintf(deliveryRetryData, kind), id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove retry: %w", err)
+	}
+	return nil
+}
+
+// RemoveChannelRetries removes every queued retry of the given kind for a channel
+func (r *RedisDeliveryRepository) RemoveChannelRetries(kind, channelID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	ids, err := r.client.HKeys(ctx, fmt.Sprintf(deliveryRetryData, kind)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list retries: %w", err)
+	}
+
+	var retryIDs []string
+	for _, id := range ids {
+		if strings.HasSuffix(id, deliveryRetryIDSep+channelID) {
+			retryIDs = append(retryIDs, id)
+		}
+	}
+	if len(retryIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(retryIDs))
+	for idx, id := range retryIDs {
+		members[idx] = id
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, fmt.Sprintf(deliveryRetryKey, kind), members...)
+	pipe.HDel(ctx, fmt.Sprintf(deliveryRetryData, kind), retryIDs...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove channel retries: %w", err)
+	}
+	return nil
+}
+
+// deliveryRetryID identifies the retry of an item for a channel (channel IDs never contain the separator)
+func deliveryRetryID(itemKey, channelID string) string {
+	return itemKey + deliveryRetryIDSep + channelID
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisDeliveryRepository_Delivered(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	repo := NewRedisDeliveryRepository(client)
+
+	delivered, err := repo.IsDelivered("rss:godot:guid-1", "channel1")
+	require.NoError(t, err)
+	assert.False(t, delivered)
+
+	require.NoError(t, repo.MarkDelivered("rss:godot:guid-1", "channel1"))
+
+	delivered, err = repo.IsDelivered("rss:godot:guid-1", "channel1")
+	require.NoError(t, err)
+	assert.True(t, delivered)
+
+	// Other channels and items are independent
+	delivered, err = repo.IsDelivered("rss:godot:guid-1", "channel2")
+	require.NoError(t, err)
+	assert.False(t, delivered)
+	delivered, err = repo.IsDelivered("rss:godot:guid-2", "channel1")
+	require.NoError(t, err)
+	assert.False(t, delivered)
+
+	// The content source is kept with the delivery records
+	source, err := repo.GetContentSource("rss:godot:guid-1")
+	require.NoError(t, err)
+	assert.Empty(t, source)
+	require.NoError(t, repo.SetContentSource("rss:godot:guid-1", "title only"))
+	source, err = repo.GetContentSource("rss:godot:guid-1")
+	require.NoError(t, err)
+	assert.Equal(t, "title only", source)
+
+	// Records expire
+	mr.FastForward(deliveryRecordTTL + time.Hour)
+	delivered, err = repo.IsDelivered("rss:godot:guid-1", "channel1")
+	require.NoError(t, err)
+	assert.False(t, delivered)
+	source, err = repo.GetContentSource("rss:godot:guid-1")
+	require.NoError(t, err)
+	assert.Empty(t, source)
+}
+
+func TestRedisDeliveryRepository_Retries(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisDeliveryRepository(client)
+
+	now := time.Now()
+	due := DeliveryRetry{
+		Kind:        "rss",
+		ItemKey:     "rss:godot:guid-1",
+		SourceID:    "godot",
+		ChannelID:   "channel1",
+		Payload:     []byte(`{"title":"Article"}`),
+		Attempts:    1,
+		NextAttempt: now.Add(-time.Minute),
+		LastError:   "HTTP 500",
+	}
+	later := due
+	later.ChannelID = "channel2"
+	later.NextAttempt = now.Add(time.Hour)
+	otherKind := due
+	otherKind.Kind = "github"
+
+	require.NoError(t, repo.ScheduleRetry(due))
+	require.NoError(t, repo.ScheduleRetry(later))
+	require.NoError(t, repo.ScheduleRetry(otherKind))
+
+	retries, err := repo.GetDueRetries("rss", now, 10)
+	require.NoError(t, err)
+	require.Len(t, retries, 1)
+	assert.Equal(t, "channel1", retries[0].ChannelID)
+	assert.Equal(t, "godot", retries[0].SourceID)
+	assert.Equal(t, []byte(`{"title":"Article"}`), retries[0].Payload)
+	assert.Equal(t, 1, retries[0].Attempts)
+	assert.Equal(t, "HTTP 500", retries[0].LastError)
+
+	// Rescheduling replaces the queued retry
+	due.Attempts = 2
+	due.NextAttempt = now.Add(30 * time.Minute)
+	require.NoError(t, repo.ScheduleRetry(due))
+
+	retries, err = repo.GetDueRetries("rss", now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, retries)
+
+	retries, err = repo.GetDueRetries("rss", now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, retries, 2)
+	assert.Equal(t, "channel1", retries[0].ChannelID)
+	assert.Equal(t, 2, retries[0].Attempts)
+
+	// The limit bounds the batch
+	retries, err = repo.GetDueRetries("rss", now.Add(2*time.Hour), 1)
+	require.NoError(t, err)
+	assert.Len(t, retries, 1)
+
+	require.NoError(t, repo.RemoveRetry("rss", due.ItemKey, due.ChannelID))
+	retries, err = repo.GetDueRetries("rss", now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, retries, 1)
+	assert.Equal(t, "channel2", retries[0].ChannelID)
+
+	// Retries of other kinds are untouched
+	retries, err = repo.GetDueRetries("github", now, 10)
+	require.NoError(t, err)
+	assert.Len(t, retries, 1)
+}
+
+func TestRedisDeliveryRepository_RemoveChannelRetries(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisDeliveryRepository(client)
+
+	now := time.Now()
+	for _, retry := range []DeliveryRetry{
+		{Kind: "rss", ItemKey: "rss:godot:guid-1", ChannelID: "channel1", NextAttempt: now},
+		{Kind: "rss", ItemKey: "rss:godot:guid-2", ChannelID: "channel1", NextAttempt: now},
+		{Kind: "rss", ItemKey: "rss:godot:guid-1", ChannelID: "channel11", NextAttempt: now},
+	} {
+		require.NoError(t, repo.ScheduleRetry(retry))
+	}
+
+	require.NoError(t, repo.RemoveChannelRetries("rss", "channel1"))
+
+	retries, err := repo.GetDueRetries("rss", now, 10)
+	require.NoError(t, err)
+	require.Len(t, retries, 1)
+	assert.Equal(t, "channel11", retries[0].ChannelID)
+
+	// Nothing queued for the channel
+	assert.NoError(t, repo.RemoveChannelRetries("rss", "channel2"))
+}
@@ -20,6 +20,9 @@ const (
 	repoPendingPrefix   = "github:repos:%s:pending"    // github:repos:{repoID}:pending (LIST)
 	repoChannelsPrefix  = "github:repos:%s:channels"   // github:repos:{repoID}:channels (SET)
 	channelReposPrefix  = "github:channels:%s:repos"   // github:channels:{channelID}:repos (SET)
+	channelGuildPrefix  = "github:channels:%s:guild"   // github:channels:{channelID}:guild (guild of the channel)
+	guildChannelsPrefix = "github:guilds:%s:channels"  // github:guilds:{guildID}:channels (SET, channels subscribed to any repository)
+	guildReposPrefix    = "github:guilds:%s:repos"     // github:guilds:{guildID}:repos (SET, repositories registered by the guild)
 	repoLastCheckedKey  = "github:repos:%s:last_checked" // github:repos:{repoID}:last_checked
 	repoScheduleKey     = "github:repos:%s:schedule"    // github:repos:{repoID}:schedule (LIST)
 )
@@ -34,17 +37,25 @@ type GitHubRepository interface {
 	GetRepository(repoID string) (*github.Repository, error)
 	// GetAllRepositories returns all registered repositories
 	GetAllRepositories() ([]github.Repository, error)
+	// GetGuildRepoCount returns the number of repositories registered by a guild (catalog repositories excluded)
+	GetGuildRepoCount(guildID string) (int, error)
 	// HasRepository checks if a repository is registered
 	HasRepository(repoID string) (bool, error)
 	
 	// Schedule management
 	SetSchedule(repoID string, times []string) error
 	GetSchedule(repoID string) ([]string, error)
+	SetTimezone(repoID, timezone string) error
 	
-	// AddRepoChannel associates a Discord channel with a repository
-	AddRepoChannel(repoID, channelID string) error
+	// AddRepoChannel associates a Discord channel of a guild with a repository if the guild's
+	// channel quota (maxGuildChannels, 0 = unlimited) is not exceeded
+	AddRepoChannel(guildID, repoID, channelID string, maxGuildChannels int) error
 	// RemoveRepoChannel removes channel association from repository
 	RemoveRepoChannel(repoID, channelID string) error
+	// GetGuildRepoChannels returns the channels of a guild subscribed to any repository
+	GetGuildRepoChannels(guildID string) ([]string, error)
+	// GetChannelGuild returns the guild of a channel subscribed to any repository, or "" when unknown
+	GetChannelGuild(channelID string) (string, error)
 	// GetRepoChannels returns all channels subscribed to a repository
 	GetRepoChannels(repoID string) ([]string, error)
 	// GetChannelRepos returns all repositories a channel is subscribed to
@@ -96,8 +107,20 @@ func (r *RedisGitHubRepository) RegisterRepository(repo github.Repository) error
 		"target_branch": repo.TargetBranch,
 		"added_at":      repo.AddedAt.Format(time.RFC3339),
 	}
+	if repo.Timezone != "" {
+		data["timezone"] = repo.Timezone
+	}
+	if repo.OwnerGuildID != "" {
+		data["owner_guild"] = repo.OwnerGuildID
+	}
 	
-	if err := r.client.HSet(ctx, key, data).Err(); err != nil {
+	// Store the repository along with its guild's index, which the repository quota counts
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, data)
+	if repo.OwnerGuildID != "" {
+		pipe.SAdd(ctx, fmt.Sprintf(guildReposPrefix, repo.OwnerGuildID), repo.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("failed to register repository: %w", err)
 	}
 	
@@ -118,9 +141,18 @@ func (r *RedisGitHubRepository) UnregisterRepository(repoID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 	
-	// Delete repository metadata
+	// Delete repository metadata and its guild's index entry
 	key := fmt.Sprintf("%s%s", repoPrefix, repoID)
-	if err := r.client.Del(ctx, key).Err(); err != nil {
+	ownerGuildID, err := r.client.HGet(ctx, key, "owner_guild").Result()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to get repository owner: %w", err)
+	}
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if ownerGuildID != "" {
+		pipe.SRem(ctx, fmt.Sprintf(guildReposPrefix, ownerGuildID), repoID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("failed to unregister repository: %w", err)
 	}
 	
@@ -139,6 +171,18 @@ func (r *RedisGitHubRepository) UnregisterRepository(repoID string) error {
 	return nil
 }
 
+// GetGuildRepoCount returns the number of repositories registered by a guild (catalog repositories excluded)
+func (r *RedisGitHubRepository) GetGuildRepoCount(guildID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	
+	count, err := r.client.SCard(ctx, fmt.Sprintf(guildReposPrefix, guildID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count guild repositories: %w", err)
+	}
+	return int(count), nil
+}
+
 // GetRepository returns repository details
 func (r *RedisGitHubRepository) GetRepository(repoID string) (*github.Repository, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
@@ -162,6 +206,8 @@ func (r *RedisGitHubRepository) GetRepository(repoID string) (*github.Repository
 		Name:         data["name"],
 		TargetBranch: data["target_branch"],
 		AddedAt:      addedAt,
+		Timezone:     data["timezone"],
+		OwnerGuildID: data["owner_guild"],
 	}, nil
 }
 
@@ -219,20 +265,38 @@ func (r *RedisGitHubRepository) HasRepository(repoID string) (bool, error) {
 }
 
 // AddRepoChannel associates a Discord channel with a repository
-func (r *RedisGitHubRepository) AddRepoChannel(repoID, channelID string) error {
+func (r *RedisGitHubRepository) AddRepoChannel(guildID, repoID, channelID string, maxGuildChannels int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 	
 	repoChannelsKey := fmt.Sprintf(repoChannelsPrefix, repoID)
 	channelReposKey := fmt.Sprintf(channelReposPrefix, channelID)
 	
-	// Use pipeline for atomic operation
-	pipe := r.client.Pipeline()
-	pipe.SAdd(ctx, repoChannelsKey, channelID)
-	pipe.SAdd(ctx, channelReposKey, repoID)
-	
-	if _, err := pipe.Exec(ctx); err != nil {
-		return fmt.Errorf("failed to add channel to repository: %w", err)
+	// Check the guild's quota and subscribe in one transaction, retried when another
+	// subscription changes the guild's channels in between
+	err := watchTx(ctx, r.client, func(tx *redis.Tx) error {
+		if err := checkGuildChannelQuota(ctx, tx, guildID, channelID, maxGuildChannels); err != nil {
+			return err
+		}
+		
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SAdd(ctx, repoChannelsKey, channelID)
+			pipe.SAdd(ctx, channelReposKey, repoID)
+			
+			// Record the channel's guild, which per-guild quotas count against
+			if guildID != "" {
+				pipe.Set(ctx, fmt.Sprintf(channelGuildPrefix, channelID), guildID, 0)
+				pipe.SAdd(ctx, fmt.Sprintf(guildChannelsPrefix, guildID), channelID)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add channel to repository: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	
 	log.Printf("Added channel %s to repository %s", channelID, repoID)
@@ -255,10 +319,44 @@ func (r *RedisGitHubRepository) RemoveRepoChannel(repoID, channelID string) erro
 		return fmt.Errorf("failed to remove channel from repository: %w", err)
 	}
 	
+	// A channel left without repositories no longer counts against its guild
+	remaining, err := r.client.SCard(ctx, channelReposKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check remaining repositories: %w", err)
+	}
+	if remaining == 0 {
+		channelGuildKey := fmt.Sprintf(channelGuildPrefix, channelID)
+		guildID, err := r.client.Get(ctx, channelGuildKey).Result()
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("failed to get channel guild: %w", err)
+		}
+		pipe := r.client.Pipeline()
+		pipe.Del(ctx, channelGuildKey)
+		if guildID != "" {
+			pipe.SRem(ctx, fmt.Sprintf(guildChannelsPrefix, guildID), channelID)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to remove channel guild: %w", err)
+		}
+	}
+	
 	log.Printf("Removed channel %s from repository %s", channelID, repoID)
 	return nil
 }
 
+// GetGuildRepoChannels returns the channels of a guild subscribed to any repository
+func (r *RedisGitHubRepository) GetGuildRepoChannels(guildID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	
+	channels, err := r.client.SMembers(ctx, fmt.Sprintf(guildChannelsPrefix, guildID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild repository channels: %w", err)
+	}
+	
+	return channels, nil
+}
+
 // GetRepoChannels returns all channels subscribed to a repository
 func (r *RedisGitHubRepository) GetRepoChannels(repoID string) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
@@ -287,6 +385,22 @@ func (r *RedisGitHubRepository) GetChannelRepos(channelID string) ([]string, err
 	return repos, nil
 }
 
+// GetChannelGuild returns the guild of a channel subscribed to any repository, or "" when unknown
+func (r *RedisGitHubRepository) GetChannelGuild(channelID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	
+	guildID, err := r.client.Get(ctx, fmt.Sprintf(channelGuildPrefix, channelID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get channel guild: %w", err)
+	}
+	
+	return guildID, nil
+}
+
 // IsProcessed checks if a PR has already been processed
 func (r *RedisGitHubRepository) IsProcessed(repoID string, prID int64) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
@@ -452,10 +566,10 @@ func (r *RedisGitHubRepository) SetSchedule(repoID string, times []string) error
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 	
-	// Validate time format (HH:MM)
+	// Validate schedule entries (HH:MM, "every <duration>" or cron)
 	for _, t := range times {
-		if !isValidTimeFormat(t) {
-			return fmt.Errorf("invalid time format: %s (expected HH:MM)", t)
+		if !isValidScheduleSpec(t) {
+			return fmt.Errorf("invalid time format: %s (expected HH:MM, \"every <duration>\" or a cron expression)", t)
 		}
 	}
 	
@@ -497,10 +611,31 @@ func (r *RedisGitHubRepository) GetSchedule(repoID string) ([]string, error) {
 	return times, nil
 }
 
-// isValidTimeFormat checks if time string is in HH:MM format
-func isValidTimeFormat(timeStr string) bool {
-	_, err := time.Parse("15:04", timeStr)
-	return err == nil
+// SetTimezone sets the IANA timezone the repository's schedule is evaluated in (empty = bot's local time)
+func (r *RedisGitHubRepository) SetTimezone(repoID, timezone string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	key := fmt.Sprintf("%s%s", repoPrefix, repoID)
+
+	exists, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check repository: %w", err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("repository not found: %s", repoID)
+	}
+
+	if timezone == "" {
+		err = r.client.HDel(ctx, key, "timezone").Err()
+	} else {
+		err = r.client.HSet(ctx, key, "timezone", timezone).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set timezone: %w", err)
+	}
+
+	return nil
 }
 
 // GetChannelLanguage retrieves the language preference for a channel
@@ -48,6 +48,38 @@ func TestGitHubRepository_RegisterAndGet(t *testing.T) {
 	assert.Equal(t, testRepo.Owner, retrieved.Owner)
 	assert.Equal(t, testRepo.Name, retrieved.Name)
 	assert.Equal(t, testRepo.TargetBranch, retrieved.TargetBranch)
+	assert.True(t, retrieved.IsCatalog())
+}
+
+func TestGitHubRepository_OwnerGuild(t *testing.T) {
+	client, mr := setupGitHubTestRedis(t)
+	defer mr.Close()
+	defer client.Close()
+
+	repo := NewRedisGitHubRepository(client)
+	require.NoError(t, repo.RegisterRepository(github.Repository{
+		ID: "guild-repo", Owner: "owner", Name: "repo", TargetBranch: "main", AddedAt: time.Now(), OwnerGuildID: "guild-1",
+	}))
+
+	retrieved, err := repo.GetRepository("guild-repo")
+	require.NoError(t, err)
+	assert.Equal(t, "guild-1", retrieved.OwnerGuildID)
+	assert.False(t, retrieved.IsCatalog())
+	assert.True(t, retrieved.VisibleTo("guild-1"))
+	assert.False(t, retrieved.VisibleTo("guild-2"))
+
+	// Only the guild's own repositories count against its quota
+	require.NoError(t, repo.RegisterRepository(github.Repository{
+		ID: "catalog-repo", Owner: "owner", Name: "catalog", TargetBranch: "main", AddedAt: time.Now(),
+	}))
+	count, err := repo.GetGuildRepoCount("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	require.NoError(t, repo.UnregisterRepository("guild-repo"))
+	count, err = repo.GetGuildRepoCount("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
 }
 
 func TestGitHubRepository_HasRepository(t *testing.T) {
@@ -98,7 +130,7 @@ func TestGitHubRepository_UnregisterRepository(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add a channel
-	err = repo.AddRepoChannel("to-delete", "channel123")
+	err = repo.AddRepoChannel("guild1", "to-delete", "channel123", 0)
 	require.NoError(t, err)
 
 	// Unregister
@@ -169,11 +201,11 @@ func TestGitHubRepository_ChannelAssociations(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add channels
-	err = repo.AddRepoChannel("test-repo", "channel1")
+	err = repo.AddRepoChannel("guild1", "test-repo", "channel1", 0)
 	require.NoError(t, err)
-	err = repo.AddRepoChannel("test-repo", "channel2")
+	err = repo.AddRepoChannel("guild1", "test-repo", "channel2", 0)
 	require.NoError(t, err)
-	err = repo.AddRepoChannel("test-repo", "channel3")
+	err = repo.AddRepoChannel("guild1", "test-repo", "channel3", 0)
 	require.NoError(t, err)
 
 	// Get repo channels
@@ -198,6 +230,24 @@ func TestGitHubRepository_ChannelAssociations(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, channels, 2)
 	assert.NotContains(t, channels, "channel2")
+
+	// Channels count against their guild while subscribed to any repository
+	guildChannels, err := repo.GetGuildRepoChannels("guild1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"channel1", "channel3"}, guildChannels)
+	assert.False(t, mr.Exists("github:channels:channel2:guild"))
+
+	guildChannels, err = repo.GetGuildRepoChannels("guild2")
+	require.NoError(t, err)
+	assert.Empty(t, guildChannels)
+
+	guildID, err := repo.GetChannelGuild("channel1")
+	require.NoError(t, err)
+	assert.Equal(t, "guild1", guildID)
+
+	guildID, err = repo.GetChannelGuild("channel2")
+	require.NoError(t, err)
+	assert.Empty(t, guildID)
 }
 
 func TestGitHubRepository_Deduplication(t *testing.T) {
@@ -344,6 +394,43 @@ func TestGitHubRepository_LastChecked(t *testing.T) {
 	assert.WithinDuration(t, now, lastChecked, time.Second)
 }
 
+func TestGitHubRepository_Timezone(t *testing.T) {
+	client, mr := setupGitHubTestRedis(t)
+	defer mr.Close()
+	defer client.Close()
+
+	repo := NewRedisGitHubRepository(client)
+
+	testRepo := github.Repository{
+		ID:           "test-repo",
+		Owner:        "owner",
+		Name:         "name",
+		TargetBranch: "main",
+		AddedAt:      time.Now(),
+		Timezone:     "Europe/Berlin",
+	}
+	require.NoError(t, repo.RegisterRepository(testRepo))
+
+	retrieved, err := repo.GetRepository("test-repo")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", retrieved.Timezone)
+
+	// Change timezone
+	require.NoError(t, repo.SetTimezone("test-repo", "America/Sao_Paulo"))
+	retrieved, err = repo.GetRepository("test-repo")
+	require.NoError(t, err)
+	assert.Equal(t, "America/Sao_Paulo", retrieved.Timezone)
+
+	// Empty resets to local time
+	require.NoError(t, repo.SetTimezone("test-repo", ""))
+	retrieved, err = repo.GetRepository("test-repo")
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.Timezone)
+
+	// Unknown repository
+	assert.Error(t, repo.SetTimezone("missing", "Europe/Berlin"))
+}
+
 func TestGitHubRepository_ManyToManyAssociations(t *testing.T) {
 	client, mr := setupGitHubTestRedis(t)
 	defer mr.Close()
@@ -360,13 +447,13 @@ func TestGitHubRepository_ManyToManyAssociations(t *testing.T) {
 	require.NoError(t, err)
 
 	// Associate multiple repos with channel1
-	err = repo.AddRepoChannel("repo1", "channel1")
+	err = repo.AddRepoChannel("guild1", "repo1", "channel1", 0)
 	require.NoError(t, err)
-	err = repo.AddRepoChannel("repo2", "channel1")
+	err = repo.AddRepoChannel("guild1", "repo2", "channel1", 0)
 	require.NoError(t, err)
 
 	// Associate repo1 with multiple channels
-	err = repo.AddRepoChannel("repo1", "channel2")
+	err = repo.AddRepoChannel("guild1", "repo1", "channel2", 0)
 	require.NoError(t, err)
 
 	// Verify many-to-many relationships
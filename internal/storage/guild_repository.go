@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	frozenGuildsKey     = "news:frozen_guilds" // SET of guild IDs frozen by the bot owners
+	guildSettingsPrefix = "news:guilds:%s:"    // news:guilds:{guildID}:* (language, prompts, quotas, channels)
+)
+
+// GuildRepository stores the state the bot owners manage for whole guilds
+type GuildRepository interface {
+	// SetGuildFrozen freezes or unfreezes a guild; frozen guilds can't use commands or receive posts
+	SetGuildFrozen(guildID string, frozen bool) error
+	// IsGuildFrozen reports whether a guild is frozen
+	IsGuildFrozen(guildID string) (bool, error)
+	// GetFrozenGuilds returns the frozen guild IDs
+	GetFrozenGuilds() ([]string, error)
+	// DeleteGuildSettings removes every setting stored for a guild and returns how many keys were deleted
+	DeleteGuildSettings(guildID string) (int, error)
+}
+
+// RedisGuildRepository implements GuildRepository using Redis
+type RedisGuildRepository struct {
+	client *redis.Client
+}
+
+// NewRedisGuildRepository creates a new Redis-based guild repository
+func NewRedisGuildRepository(client *redis.Client) *RedisGuildRepository {
+	return &RedisGuildRepository{client: client}
+}
+
+// SetGuildFrozen freezes or unfreezes a guild
+func (r *RedisGuildRepository) SetGuildFrozen(guildID string, frozen bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var err error
+	if frozen {
+		err = r.client.SAdd(ctx, frozenGuildsKey, guildID).Err()
+	} else {
+		err = r.client.SRem(ctx, frozenGuildsKey, guildID).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update frozen guilds: %w", err)
+	}
+	return nil
+}
+
+// IsGuildFrozen reports whether a guild is frozen
+func (r *RedisGuildRepository) IsGuildFrozen(guildID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	frozen, err := r.client.SIsMember(ctx, frozenGuildsKey, guildID).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check frozen guild: %w", err)
+	}
+	return frozen, nil
+}
+
+// GetFrozenGuilds returns the frozen guild IDs
+func (r *RedisGuildRepository) GetFrozenGuilds() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	guildIDs, err := r.client.SMembers(ctx, frozenGuildsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frozen guilds: %w", err)
+	}
+	return guildIDs, nil
+}
+
+// DeleteGuildSettings removes every key under news:guilds:{guildID}: and returns how many
+// keys were deleted
+func (r *RedisGuildRepository) DeleteGuildSettings(guildID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var keys []string
+	iter := r.client.Scan(ctx, 0, fmt.Sprintf(guildSettingsPrefix, guildID)+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list guild settings: %w", err)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	deleted, err := r.client.Del(ctx, keys...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete guild settings: %w", err)
+	}
+	return int(deleted), nil
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisGuildRepository_Frozen(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisGuildRepository(client)
+
+	frozen, err := repo.IsGuildFrozen("guild-1")
+	require.NoError(t, err)
+	assert.False(t, frozen)
+
+	require.NoError(t, repo.SetGuildFrozen("guild-1", true))
+	require.NoError(t, repo.SetGuildFrozen("guild-2", true))
+
+	frozen, err = repo.IsGuildFrozen("guild-1")
+	require.NoError(t, err)
+	assert.True(t, frozen)
+
+	require.NoError(t, repo.SetGuildFrozen("guild-2", false))
+	guildIDs, err := repo.GetFrozenGuilds()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"guild-1"}, guildIDs)
+}
+
+func TestRedisGuildRepository_DeleteGuildSettings(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisGuildRepository(client)
+
+	channelRepo, err := NewRedisChannelRepository(client, 0)
+	require.NoError(t, err)
+	require.NoError(t, channelRepo.SetGuildLanguage("guild-1", "pt-BR"))
+	require.NoError(t, channelRepo.SetGuildLanguage("guild-2", "es"))
+	require.NoError(t, NewRedisPromptRepository(client).SetPromptTemplate(PromptScopeGuild, "guild-1", ai.PromptArticle, "{{.Content}}"))
+	require.NoError(t, NewRedisQuotaRepository(client, Quotas{}).SetGuildQuota("guild-1", QuotaFeeds, 3))
+
+	deleted, err := repo.DeleteGuildSettings("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+
+	language, err := channelRepo.GetGuildLanguage("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, "en", language, "back to the default")
+
+	// Other guilds keep their settings
+	language, err = channelRepo.GetGuildLanguage("guild-2")
+	require.NoError(t, err)
+	assert.Equal(t, "es", language)
+
+	deleted, err = repo.DeleteGuildSettings("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+}
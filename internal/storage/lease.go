@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const leasePrefix = "locks:" // locks:{name} (owner token, expires after the lease TTL)
+
+var (
+	// ErrLeaseHeld is returned when another instance holds the lease
+	ErrLeaseHeld = errors.New("lease is held by another instance")
+	// ErrLeaseLost is returned when the lease expired or was taken over before it could be renewed
+	ErrLeaseLost = errors.New("lease was lost")
+)
+
+var (
+	// renewLeaseScript extends the lease only if it is still owned by the caller
+	renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	// releaseLeaseScript deletes the lease only if it is still owned by the caller
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// LeaseManager hands out Redis-backed leases so that several bot instances
+// sharing one Redis never process the same feed or repository at once
+type LeaseManager struct {
+	client     *redis.Client
+	instanceID string
+}
+
+// NewLeaseManager creates a lease manager. instanceID identifies this bot
+// instance in lease values (e.g. the hostname) and only serves debugging.
+func NewLeaseManager(client *redis.Client, instanceID string) *LeaseManager {
+	return &LeaseManager{
+		client:     client,
+		instanceID: instanceID,
+	}
+}
+
+// Lease is a time-limited exclusive claim on a name
+type Lease struct {
+	client *redis.Client
+	key    string
+	token  string
+	ttl    time.Duration
+
+	mu          sync.Mutex
+	lastRenewed time.Time
+	stop        chan struct{}
+	lost        chan struct{}
+	stopOnce    sync.Once
+	lostOnce    sync.Once
+}
+
+// TryAcquire claims the lease with SET NX PX. It returns ErrLeaseHeld if another
+// instance currently holds it.
+func (m *LeaseManager) TryAcquire(name string, ttl time.Duration) (*Lease, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	token, err := newLeaseToken(m.instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := leasePrefix + name
+	acquired, err := m.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+	if !acquired {
+		return nil, ErrLeaseHeld
+	}
+
+	return &Lease{
+		client:      m.client,
+		key:         key,
+		token:       token,
+		ttl:         ttl,
+		lastRenewed: time.Now(),
+		stop:        make(chan struct{}),
+		lost:        make(chan struct{}),
+	}, nil
+}
+
+// Renew extends the lease by its TTL. It returns ErrLeaseLost if the lease
+// expired or is now owned by another instance.
+func (l *Lease) Renew() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	renewed, err := renewLeaseScript.Run(ctx, l.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to renew lease %s: %w", l.key, err)
+	}
+	if renewed == 0 {
+		l.markLost()
+		return ErrLeaseLost
+	}
+
+	l.mu.Lock()
+	l.lastRenewed = time.Now()
+	l.mu.Unlock()
+	return nil
+}
+
+// KeepAlive renews the lease in the background every third of its TTL until
+// Release is called. If the lease cannot be renewed before it expires, Lost is closed.
+func (l *Lease) KeepAlive() {
+	go func() {
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				err := l.Renew()
+				if err == nil {
+					continue
+				}
+				if errors.Is(err, ErrLeaseLost) {
+					log.Printf("[LEASE] WARNING: Lease %s was lost", l.key)
+					return
+				}
+
+				// Transient errors are retried until the lease would have expired
+				l.mu.Lock()
+				expired := time.Since(l.lastRenewed) >= l.ttl
+				l.mu.Unlock()
+				log.Printf("[LEASE] WARNING: %v", err)
+				if expired {
+					log.Printf("[LEASE] WARNING: Lease %s expired before it could be renewed", l.key)
+					l.markLost()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Lost is closed once the lease is known to be lost
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Release stops renewing the lease and deletes it if it is still owned by this instance
+func (l *Lease) Release() error {
+	l.stopOnce.Do(func() { close(l.stop) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := releaseLeaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err(); err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", l.key, err)
+	}
+	return nil
+}
+
+// markLost closes the lost channel once
+func (l *Lease) markLost() {
+	l.lostOnce.Do(func() { close(l.lost) })
+}
+
+// newLeaseToken returns a value unique to one acquisition
+func newLeaseToken(instanceID string) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate lease token: %w", err)
+	}
+	return instanceID + ":" + hex.EncodeToString(buf), nil
+}
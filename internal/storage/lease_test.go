@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaseManager_TryAcquire(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	instanceA := NewLeaseManager(client, "instance-a")
+	instanceB := NewLeaseManager(client, "instance-b")
+
+	lease, err := instanceA.TryAcquire("feed:godot", 30*time.Second)
+	require.NoError(t, err)
+	assert.True(t, mr.Exists("locks:feed:godot"))
+
+	// The other instance can't take it while it is held
+	_, err = instanceB.TryAcquire("feed:godot", 30*time.Second)
+	assert.ErrorIs(t, err, ErrLeaseHeld)
+
+	// Other names are independent
+	other, err := instanceB.TryAcquire("feed:other", 30*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, other.Release())
+
+	// Once released, the other instance can acquire it
+	require.NoError(t, lease.Release())
+	assert.False(t, mr.Exists("locks:feed:godot"))
+
+	lease, err = instanceB.TryAcquire("feed:godot", 30*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, lease.Release())
+}
+
+func TestLease_ExpiresWithoutRenewal(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	instanceA := NewLeaseManager(client, "instance-a")
+	instanceB := NewLeaseManager(client, "instance-b")
+
+	stale, err := instanceA.TryAcquire("scheduler:rss", 10*time.Second)
+	require.NoError(t, err)
+
+	mr.FastForward(11 * time.Second)
+
+	// The lease expired, so another instance takes over
+	current, err := instanceB.TryAcquire("scheduler:rss", 10*time.Second)
+	require.NoError(t, err)
+
+	// The stale owner can neither renew nor release the new owner's lease
+	assert.ErrorIs(t, stale.Renew(), ErrLeaseLost)
+	select {
+	case <-stale.Lost():
+	default:
+		t.Fatal("expected stale lease to be marked as lost")
+	}
+
+	require.NoError(t, stale.Release())
+	assert.True(t, mr.Exists("locks:scheduler:rss"))
+
+	require.NoError(t, current.Release())
+	assert.False(t, mr.Exists("locks:scheduler:rss"))
+}
+
+func TestLease_Renew(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	manager := NewLeaseManager(client, "instance-a")
+
+	lease, err := manager.TryAcquire("repo:godot", 10*time.Second)
+	require.NoError(t, err)
+
+	mr.FastForward(8 * time.Second)
+	require.NoError(t, lease.Renew())
+
+	// Renewal restarted the TTL
+	mr.FastForward(8 * time.Second)
+	assert.True(t, mr.Exists("locks:repo:godot"))
+
+	require.NoError(t, lease.Release())
+}
+
+func TestLease_KeepAlive(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	manager := NewLeaseManager(client, "instance-a")
+
+	lease, err := manager.TryAcquire("feed:godot", 300*time.Millisecond)
+	require.NoError(t, err)
+	lease.KeepAlive()
+
+	// Renewals run every 100ms, so the lease outlives its TTL
+	time.Sleep(450 * time.Millisecond)
+	mr.FastForward(200 * time.Millisecond)
+	assert.True(t, mr.Exists("locks:feed:godot"))
+
+	require.NoError(t, lease.Release())
+	assert.False(t, mr.Exists("locks:feed:godot"))
+}
@@ -0,0 +1,134 @@
+// Package migrations keeps the layout of the bot's Redis keys up to date.
+//
+// The schema version of a database is stored in news:schema_version. On startup the
+// migrations newer than that version run in order, and the version is recorded after each
+// one. Migrations are idempotent, so a migration interrupted halfway (or run by two instances
+// starting together) can safely run again. In dry-run mode nothing is written; each migration
+// only reports what it would change.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	schemaVersionKey = "news:schema_version" // STRING, version of the last applied migration
+	migrationTimeout = 5 * time.Minute       // Time each migration may take (backfills may call Discord once per channel)
+)
+
+// Migration changes the Redis key layout from the previous schema version to Version
+type Migration struct {
+	Version     int
+	Description string
+	// Apply makes the changes, or only describes them when dryRun is set, and returns one
+	// description per change. It must be idempotent.
+	Apply func(ctx context.Context, client *redis.Client, dryRun bool) ([]string, error)
+}
+
+// Report describes what a migration changed, or would change in dry-run mode
+type Report struct {
+	Version     int
+	Description string
+	Changes     []string
+}
+
+// Runner applies the pending migrations of a database
+type Runner struct {
+	client     *redis.Client
+	migrations []Migration
+}
+
+// NewRunner creates a runner for migrations numbered 1, 2, 3... in order
+func NewRunner(client *redis.Client, migrations []Migration) (*Runner, error) {
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration %q has version %d, expected %d", migration.Description, migration.Version, i+1)
+		}
+		if migration.Apply == nil {
+			return nil, fmt.Errorf("migration %d has no Apply function", migration.Version)
+		}
+	}
+	return &Runner{client: client, migrations: migrations}, nil
+}
+
+// LatestVersion returns the schema version the migrations bring a database to
+func (r *Runner) LatestVersion() int {
+	return len(r.migrations)
+}
+
+// CurrentVersion returns the schema version of the database, 0 when none was recorded
+func (r *Runner) CurrentVersion() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, schemaVersionKey).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", value, err)
+	}
+	return version, nil
+}
+
+// Run applies the migrations newer than the database's schema version, in order, and returns
+// what each one changed. With dryRun nothing is written, including the schema version; later
+// migrations then report changes against the unmigrated data.
+func (r *Runner) Run(dryRun bool) ([]Report, error) {
+	current, err := r.CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	if current > r.LatestVersion() {
+		return nil, fmt.Errorf("schema version %d is newer than this build supports (%d)", current, r.LatestVersion())
+	}
+	if current == r.LatestVersion() {
+		log.Printf("[MIGRATIONS] Schema is up to date (version %d)", current)
+		return nil, nil
+	}
+
+	var reports []Report
+	for _, migration := range r.migrations[current:] {
+		report, err := r.apply(migration, dryRun)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// apply runs one migration and records its version unless dryRun is set
+func (r *Runner) apply(migration Migration, dryRun bool) (Report, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+
+	report := Report{Version: migration.Version, Description: migration.Description}
+	changes, err := migration.Apply(ctx, r.client, dryRun)
+	if err != nil {
+		return report, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+	}
+	report.Changes = changes
+
+	if dryRun {
+		log.Printf("[MIGRATIONS] Dry run: migration %d (%s) would make %d change(s)", migration.Version, migration.Description, len(changes))
+		return report, nil
+	}
+
+	if err := r.client.Set(ctx, schemaVersionKey, migration.Version, 0).Err(); err != nil {
+		return report, fmt.Errorf("failed to record schema version %d: %w", migration.Version, err)
+	}
+	log.Printf("[MIGRATIONS] Applied migration %d (%s): %d change(s)", migration.Version, migration.Description, len(changes))
+	return report, nil
+}
@@ -0,0 +1,302 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestRedis creates a miniredis server and redis client for testing
+func setupTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+// recordingMigration returns a migration that records its runs and writes a key
+func recordingMigration(version int, runs *[]string) Migration {
+	return Migration{
+		Version:     version,
+		Description: fmt.Sprintf("step %d", version),
+		Apply: func(ctx context.Context, client *redis.Client, dryRun bool) ([]string, error) {
+			*runs = append(*runs, fmt.Sprintf("%d dry=%v", version, dryRun))
+			key := fmt.Sprintf("step:%d", version)
+			if !dryRun {
+				if err := client.Set(ctx, key, "done", 0).Err(); err != nil {
+					return nil, err
+				}
+			}
+			return []string{"set " + key}, nil
+		},
+	}
+}
+
+func TestNewRunner_ValidatesOrder(t *testing.T) {
+	_, client := setupTestRedis(t)
+	var runs []string
+
+	_, err := NewRunner(client, []Migration{recordingMigration(1, &runs), recordingMigration(3, &runs)})
+	assert.Error(t, err)
+
+	_, err = NewRunner(client, []Migration{{Version: 1, Description: "no apply"}})
+	assert.Error(t, err)
+
+	_, err = NewRunner(client, All(nil))
+	assert.NoError(t, err)
+}
+
+func TestRunner_Run(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	var runs []string
+
+	runner, err := NewRunner(client, []Migration{recordingMigration(1, &runs), recordingMigration(2, &runs)})
+	require.NoError(t, err)
+	assert.Equal(t, 2, runner.LatestVersion())
+
+	version, err := runner.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+
+	// Dry run reports the changes without writing anything
+	reports, err := runner.Run(true)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, Report{Version: 1, Description: "step 1", Changes: []string{"set step:1"}}, reports[0])
+	assert.False(t, mr.Exists("step:1"))
+	assert.False(t, mr.Exists(schemaVersionKey))
+
+	// A real run applies the migrations in order and records the version
+	reports, err = runner.Run(false)
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+	assert.True(t, mr.Exists("step:2"))
+	version, err = runner.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	// Applied migrations don't run again
+	reports, err = runner.Run(false)
+	require.NoError(t, err)
+	assert.Empty(t, reports)
+	assert.Equal(t, []string{"1 dry=true", "2 dry=true", "1 dry=false", "2 dry=false"}, runs)
+}
+
+func TestRunner_Run_StartsAfterCurrentVersion(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	var runs []string
+	require.NoError(t, mr.Set(schemaVersionKey, "1"))
+
+	runner, err := NewRunner(client, []Migration{recordingMigration(1, &runs), recordingMigration(2, &runs)})
+	require.NoError(t, err)
+
+	_, err = runner.Run(false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2 dry=false"}, runs)
+}
+
+func TestRunner_Run_Failures(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	var runs []string
+	failing := Migration{
+		Version:     2,
+		Description: "failing step",
+		Apply: func(ctx context.Context, client *redis.Client, dryRun bool) ([]string, error) {
+			return nil, fmt.Errorf("boom")
+		},
+	}
+
+	runner, err := NewRunner(client, []Migration{recordingMigration(1, &runs), failing, recordingMigration(3, &runs)})
+	require.NoError(t, err)
+
+	// Migrations stop at the failure; the version records the last one applied
+	_, err = runner.Run(false)
+	assert.ErrorContains(t, err, "migration 2 (failing step) failed: boom")
+	version, err := runner.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	assert.Equal(t, []string{"1 dry=false"}, runs)
+
+	// Databases written by a newer build are refused
+	require.NoError(t, mr.Set(schemaVersionKey, "7"))
+	_, err = runner.Run(false)
+	assert.ErrorContains(t, err, "newer than this build")
+}
+
+func TestMigrateLegacyHistory(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	ctx := context.Background()
+
+	// Nothing to migrate
+	changes, err := migrateLegacyHistory(ctx, client, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	require.NoError(t, mr.Set("news:last_guid", "guid-3"))
+	_, err = mr.Lpush("news:pending_queue", "guid-1")
+	require.NoError(t, err)
+	_, err = mr.Lpush("news:pending_queue", "guid-2")
+	require.NoError(t, err)
+	_, err = mr.Push("news:history::godot-official:pending", "guid-2")
+	require.NoError(t, err)
+
+	changes, err = migrateLegacyHistory(ctx, client, true)
+	require.NoError(t, err)
+	assert.Len(t, changes, 4)
+	assert.True(t, mr.Exists("news:last_guid"), "dry run changes nothing")
+
+	for run := 0; run < 2; run++ {
+		_, err = migrateLegacyHistory(ctx, client, false)
+		require.NoError(t, err)
+
+		last, err := mr.Get("news:history::godot-official:last")
+		require.NoError(t, err)
+		assert.Equal(t, "guid-3", last)
+		pending, err := mr.List("news:history::godot-official:pending")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"guid-2", "guid-1"}, pending)
+		assert.False(t, mr.Exists("news:last_guid"))
+		assert.False(t, mr.Exists("news:pending_queue"))
+	}
+
+	// The feed's own last GUID wins over a legacy one
+	require.NoError(t, mr.Set("news:last_guid", "guid-0"))
+	_, err = migrateLegacyHistory(ctx, client, false)
+	require.NoError(t, err)
+	last, err := mr.Get("news:history::godot-official:last")
+	require.NoError(t, err)
+	assert.Equal(t, "guid-3", last)
+}
+
+func TestIndexFeedChannels(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	ctx := context.Background()
+
+	// Subscriptions as written before the index existed
+	mr.SAdd("news:channels", "ch1", "ch2")
+	mr.SAdd("news:channels:ch1:feeds", "godot-official", "other-feed")
+	mr.SAdd("news:channels:ch2:feeds", "godot-official")
+	mr.SAdd("news:feeds:godot-official:channels", "ch2")
+
+	changes, err := indexFeedChannels(ctx, client, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"add 1 channel(s) to news:feeds:godot-official:channels",
+		"add 1 channel(s) to news:feeds:other-feed:channels",
+	}, changes)
+	assert.False(t, mr.Exists("news:feeds:other-feed:channels"), "dry run changes nothing")
+
+	_, err = indexFeedChannels(ctx, client, false)
+	require.NoError(t, err)
+	members, err := mr.Members("news:feeds:godot-official:channels")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch1", "ch2"}, members)
+	members, err = mr.Members("news:feeds:other-feed:channels")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch1"}, members)
+
+	// Running again finds nothing to do
+	changes, err = indexFeedChannels(ctx, client, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestBackfillChannelGuilds(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	ctx := context.Background()
+
+	// ch1 and ch2 were subscribed before their guild was recorded; ch3 was deleted since
+	mr.SAdd("news:channels", "ch1", "ch2", "ch3", "ch4")
+	require.NoError(t, mr.Set("news:channels:ch4:guild", "guild-2"))
+	mr.SAdd("github:channels:ch1:repos", "engine")
+	mr.SAdd("github:channels:ch5:repos", "engine")
+
+	guilds := map[string]string{"ch1": "guild-1", "ch2": "guild-1", "ch5": "guild-2"}
+	var resolved []string
+	resolve := func(ctx context.Context, channelID string) (string, error) {
+		resolved = append(resolved, channelID)
+		return guilds[channelID], nil
+	}
+
+	_, err := backfillChannelGuilds(nil)(ctx, client, true)
+	assert.Error(t, err, "the backfill needs a resolver")
+
+	changes, err := backfillChannelGuilds(resolve)(ctx, client, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"set news:channels:ch1:guild to guild-1",
+		"set news:channels:ch2:guild to guild-1",
+		"set github:channels:ch1:guild to guild-1",
+		"set github:channels:ch5:guild to guild-2",
+	}, changes)
+	assert.ElementsMatch(t, []string{"ch1", "ch2", "ch3", "ch5"}, resolved, "each channel is resolved once")
+	assert.False(t, mr.Exists("news:channels:ch1:guild"), "dry run changes nothing")
+
+	_, err = backfillChannelGuilds(resolve)(ctx, client, false)
+	require.NoError(t, err)
+	members, err := mr.Members("news:guilds:guild-1:channels")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch1", "ch2"}, members)
+	members, err = mr.Members("github:guilds:guild-2:channels")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch5"}, members)
+	assert.False(t, mr.Exists("news:channels:ch3:guild"))
+	guild, err := mr.Get("news:channels:ch4:guild")
+	require.NoError(t, err)
+	assert.Equal(t, "guild-2", guild)
+
+	// Running again only asks about the channels Discord didn't know
+	resolved = nil
+	changes, err = backfillChannelGuilds(resolve)(ctx, client, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, []string{"ch3"}, resolved)
+
+	// Resolver failures fail the migration, so it runs again on the next start
+	failing := func(ctx context.Context, channelID string) (string, error) {
+		return "", fmt.Errorf("discord unavailable")
+	}
+	_, err = backfillChannelGuilds(failing)(ctx, client, false)
+	assert.ErrorContains(t, err, "discord unavailable")
+}
+
+func TestIndexGuildRegistrations(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	ctx := context.Background()
+
+	// Feeds and repositories registered before the per-guild sets existed
+	mr.HSet("news:feeds:private", "url", "https://example.com/a.xml", "owner_guild", "guild-1")
+	mr.HSet("news:feeds:catalog", "url", "https://example.com/b.xml")
+	mr.SAdd("news:feeds:private:channels", "ch1")
+	mr.HSet("github:repos:engine", "owner", "godotengine", "owner_guild", "guild-2")
+	mr.HSet("github:repos:docs", "owner", "godotengine", "owner_guild", "guild-2")
+	require.NoError(t, mr.Set("github:repos:engine:last_checked", "2024-01-01T00:00:00Z"))
+	mr.SAdd("github:guilds:guild-2:repos", "docs")
+
+	changes, err := indexGuildRegistrations(ctx, client, true)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"add private to news:guilds:guild-1:feeds",
+		"add engine to github:guilds:guild-2:repos",
+	}, changes)
+	assert.False(t, mr.Exists("news:guilds:guild-1:feeds"), "dry run changes nothing")
+
+	_, err = indexGuildRegistrations(ctx, client, false)
+	require.NoError(t, err)
+	members, err := mr.Members("news:guilds:guild-1:feeds")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"private"}, members)
+	members, err = mr.Members("github:guilds:guild-2:repos")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs", "engine"}, members)
+
+	// Running again changes nothing
+	changes, err = indexGuildRegistrations(ctx, client, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,332 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Migrations write the keys as they were laid out at their version, so they don't use the
+// storage package's constants, which may change later.
+
+// legacyFeedID is the feed the single-feed keys of the first releases belong to
+const legacyFeedID = "godot-official"
+
+// maxPendingItems is the length of per-feed pending queues
+const maxPendingItems = 5
+
+// ChannelGuildResolver returns the guild of a Discord channel, or "" when the channel no
+// longer exists or the bot can't access it. Redis doesn't know the guild of channels
+// subscribed before it was recorded, so migrations that need it ask Discord.
+type ChannelGuildResolver func(ctx context.Context, channelID string) (string, error)
+
+// All returns the bot's migrations, oldest first. resolveGuild looks up the guilds of
+// channels; migrations that need it fail without one.
+func All(resolveGuild ChannelGuildResolver) []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "move the single-feed history keys to the default feed",
+			Apply:       migrateLegacyHistory,
+		},
+		{
+			Version:     2,
+			Description: "index the subscribed channels of each feed",
+			Apply:       indexFeedChannels,
+		},
+		{
+			Version:     3,
+			Description: "record the guild of each subscribed channel",
+			Apply:       backfillChannelGuilds(resolveGuild),
+		},
+		{
+			Version:     4,
+			Description: "index the feeds and repositories registered by each guild",
+			Apply:       indexGuildRegistrations,
+		},
+	}
+}
+
+// migrateLegacyHistory moves news:last_guid and news:pending_queue, written when the bot
+// followed a single feed, to the history keys of the default feed
+func migrateLegacyHistory(ctx context.Context, client *redis.Client, dryRun bool) ([]string, error) {
+	const (
+		legacyLastGUIDKey = "news:last_guid"
+		legacyPendingKey  = "news:pending_queue"
+	)
+	lastKey := fmt.Sprintf("news:history::%s:last", legacyFeedID)
+	pendingKey := fmt.Sprintf("news:history::%s:pending", legacyFeedID)
+
+	var changes []string
+	pipe := client.TxPipeline()
+
+	// The feed's own last GUID is newer than the legacy one when both exist
+	legacyLast, err := client.Get(ctx, legacyLastGUIDKey).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get %s: %w", legacyLastGUIDKey, err)
+	}
+	if err == nil {
+		exists, err := client.Exists(ctx, lastKey).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", lastKey, err)
+		}
+		if exists == 0 {
+			changes = append(changes, fmt.Sprintf("copy %s to %s", legacyLastGUIDKey, lastKey))
+			pipe.Set(ctx, lastKey, legacyLast, 0)
+		}
+		changes = append(changes, fmt.Sprintf("delete %s", legacyLastGUIDKey))
+		pipe.Del(ctx, legacyLastGUIDKey)
+	}
+
+	// Both queues are newest first; legacy GUIDs are older and go to the end
+	legacyPending, err := client.LRange(ctx, legacyPendingKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", legacyPendingKey, err)
+	}
+	if len(legacyPending) > 0 {
+		pending, err := client.LRange(ctx, pendingKey, 0, -1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", pendingKey, err)
+		}
+		queued := make(map[string]bool, len(pending))
+		for _, guid := range pending {
+			queued[guid] = true
+		}
+
+		var missing []interface{}
+		for _, guid := range legacyPending {
+			if !queued[guid] {
+				queued[guid] = true
+				missing = append(missing, guid)
+			}
+		}
+		if len(missing) > 0 {
+			changes = append(changes, fmt.Sprintf("move %d GUID(s) from %s to %s", len(missing), legacyPendingKey, pendingKey))
+			pipe.RPush(ctx, pendingKey, missing...)
+			pipe.LTrim(ctx, pendingKey, 0, maxPendingItems-1)
+		}
+		changes = append(changes, fmt.Sprintf("delete %s", legacyPendingKey))
+		pipe.Del(ctx, legacyPendingKey)
+	}
+
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to move legacy history: %w", err)
+	}
+	return changes, nil
+}
+
+// indexFeedChannels builds the news:feeds:{identifier}:channels sets from the channels' feed
+// sets, for subscriptions made before the index existed
+func indexFeedChannels(ctx context.Context, client *redis.Client, dryRun bool) ([]string, error) {
+	channels, err := client.SMembers(ctx, "news:channels").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channels: %w", err)
+	}
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	feedCmds := make([]*redis.StringSliceCmd, len(channels))
+	pipe := client.Pipeline()
+	for i, channelID := range channels {
+		feedCmds[i] = pipe.SMembers(ctx, fmt.Sprintf("news:channels:%s:feeds", channelID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get channel feeds: %w", err)
+	}
+
+	// Look up which subscriptions are missing from the index
+	type subscription struct{ feedID, channelID string }
+	var subscriptions []subscription
+	var indexedCmds []*redis.BoolCmd
+	pipe = client.Pipeline()
+	for i, channelID := range channels {
+		for _, feedID := range feedCmds[i].Val() {
+			subscriptions = append(subscriptions, subscription{feedID: feedID, channelID: channelID})
+			indexedCmds = append(indexedCmds, pipe.SIsMember(ctx, fmt.Sprintf("news:feeds:%s:channels", feedID), channelID))
+		}
+	}
+	if len(subscriptions) == 0 {
+		return nil, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to check feed channels: %w", err)
+	}
+
+	missing := make(map[string][]interface{}) // feedID -> channel IDs
+	var feedIDs []string
+	for i, sub := range subscriptions {
+		if indexedCmds[i].Val() {
+			continue
+		}
+		if _, ok := missing[sub.feedID]; !ok {
+			feedIDs = append(feedIDs, sub.feedID)
+		}
+		missing[sub.feedID] = append(missing[sub.feedID], sub.channelID)
+	}
+
+	var changes []string
+	tx := client.TxPipeline()
+	for _, feedID := range feedIDs {
+		key := fmt.Sprintf("news:feeds:%s:channels", feedID)
+		changes = append(changes, fmt.Sprintf("add %d channel(s) to %s", len(missing[feedID]), key))
+		tx.SAdd(ctx, key, missing[feedID]...)
+	}
+
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	if _, err := tx.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to index feed channels: %w", err)
+	}
+	return changes, nil
+}
+
+// backfillChannelGuilds records the guild of the channels subscribed to feeds or repositories
+// before their guild was recorded, so per-guild quotas and purges see them. Channels Discord
+// no longer knows are left as they are.
+func backfillChannelGuilds(resolveGuild ChannelGuildResolver) func(ctx context.Context, client *redis.Client, dryRun bool) ([]string, error) {
+	return func(ctx context.Context, client *redis.Client, dryRun bool) ([]string, error) {
+		if resolveGuild == nil {
+			return nil, fmt.Errorf("no channel guild resolver configured")
+		}
+
+		feedChannels, err := client.SMembers(ctx, "news:channels").Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get channels: %w", err)
+		}
+		repoChannels, err := subscribedRepoChannels(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		// The key layouts of feed and repository subscriptions
+		layouts := []struct {
+			channels      []string
+			guildKey      string // channel -> guild
+			guildChannels string // guild -> channels
+		}{
+			{feedChannels, "news:channels:%s:guild", "news:guilds:%s:channels"},
+			{repoChannels, "github:channels:%s:guild", "github:guilds:%s:channels"},
+		}
+
+		guilds := make(map[string]string) // channel -> guild, resolved once per channel
+		var changes []string
+		tx := client.TxPipeline()
+		for _, layout := range layouts {
+			for _, channelID := range layout.channels {
+				guildKey := fmt.Sprintf(layout.guildKey, channelID)
+				exists, err := client.Exists(ctx, guildKey).Result()
+				if err != nil {
+					return nil, fmt.Errorf("failed to check %s: %w", guildKey, err)
+				}
+				if exists > 0 {
+					continue
+				}
+
+				guildID, resolved := guilds[channelID]
+				if !resolved {
+					guildID, err = resolveGuild(ctx, channelID)
+					if err != nil {
+						return nil, fmt.Errorf("failed to resolve the guild of channel %s: %w", channelID, err)
+					}
+					guilds[channelID] = guildID
+				}
+				if guildID == "" {
+					log.Printf("[MIGRATIONS] Channel %s no longer exists, leaving it without a guild", channelID)
+					continue
+				}
+
+				changes = append(changes, fmt.Sprintf("set %s to %s", guildKey, guildID))
+				tx.Set(ctx, guildKey, guildID, 0)
+				tx.SAdd(ctx, fmt.Sprintf(layout.guildChannels, guildID), channelID)
+			}
+		}
+
+		if dryRun || len(changes) == 0 {
+			return changes, nil
+		}
+		if _, err := tx.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to record channel guilds: %w", err)
+		}
+		return changes, nil
+	}
+}
+
+// indexGuildRegistrations builds the news:guilds:{guildID}:feeds and github:guilds:{guildID}:repos
+// sets from the owner of each feed and repository, for the ones registered before the sets existed
+func indexGuildRegistrations(ctx context.Context, client *redis.Client, dryRun bool) ([]string, error) {
+	// The key layouts of feeds and repositories; their sub-keys share the prefix
+	layouts := []struct {
+		prefix      string // identifier -> details (HASH)
+		guildSetKey string // guild -> identifiers
+	}{
+		{"news:feeds:", "news:guilds:%s:feeds"},
+		{"github:repos:", "github:guilds:%s:repos"},
+	}
+
+	var changes []string
+	tx := client.TxPipeline()
+	for _, layout := range layouts {
+		var ids []string
+		iter := client.Scan(ctx, 0, layout.prefix+"*", 100).Iterator()
+		for iter.Next(ctx) {
+			id := strings.TrimPrefix(iter.Val(), layout.prefix)
+			if !strings.Contains(id, ":") {
+				ids = append(ids, id)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list %s keys: %w", layout.prefix, err)
+		}
+
+		for _, id := range ids {
+			guildID, err := client.HGet(ctx, layout.prefix+id, "owner_guild").Result()
+			if err == redis.Nil {
+				continue // catalog entry
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the owner of %s%s: %w", layout.prefix, id, err)
+			}
+
+			key := fmt.Sprintf(layout.guildSetKey, guildID)
+			indexed, err := client.SIsMember(ctx, key, id).Result()
+			if err != nil {
+				return nil, fmt.Errorf("failed to check %s: %w", key, err)
+			}
+			if indexed {
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("add %s to %s", id, key))
+			tx.SAdd(ctx, key, id)
+		}
+	}
+
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	if _, err := tx.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to index guild registrations: %w", err)
+	}
+	return changes, nil
+}
+
+// subscribedRepoChannels returns the channels subscribed to any repository
+func subscribedRepoChannels(ctx context.Context, client *redis.Client) ([]string, error) {
+	var channels []string
+	iter := client.Scan(ctx, 0, "github:channels:*:repos", 100).Iterator()
+	for iter.Next(ctx) {
+		channelID := strings.TrimSuffix(strings.TrimPrefix(iter.Val(), "github:channels:"), ":repos")
+		channels = append(channels, channelID)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list repository channels: %w", err)
+	}
+	return channels, nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	feedPromptsKey  = "news:feeds:%s:prompts"  // news:feeds:{identifier}:prompts (HASH kind -> template)
+	guildPromptsKey = "news:guilds:%s:prompts" // news:guilds:{guildID}:prompts (HASH kind -> template)
+)
+
+// PromptScope is what a custom prompt template applies to
+type PromptScope string
+
+// Prompt scopes; a feed's template takes precedence over the template of a guild
+const (
+	PromptScopeFeed  PromptScope = "feed"
+	PromptScopeGuild PromptScope = "guild"
+)
+
+// PromptRepository stores custom prompt templates that override the configured ones for a
+// feed or a guild
+type PromptRepository interface {
+	// SetPromptTemplate stores the template of a kind for a feed or guild
+	SetPromptTemplate(scope PromptScope, id string, kind ai.PromptKind, text string) error
+	// GetPromptTemplate returns the template of a kind for a feed or guild, or "" when there is none
+	GetPromptTemplate(scope PromptScope, id string, kind ai.PromptKind) (string, error)
+	// DeletePromptTemplate removes the template of a kind for a feed or guild
+	DeletePromptTemplate(scope PromptScope, id string, kind ai.PromptKind) error
+}
+
+// RedisPromptRepository implements PromptRepository using Redis
+type RedisPromptRepository struct {
+	client *redis.Client
+}
+
+// NewRedisPromptRepository creates a new Redis-based prompt template repository
+func NewRedisPromptRepository(client *redis.Client) *RedisPromptRepository {
+	return &RedisPromptRepository{client: client}
+}
+
+// promptsKey returns the key holding the templates of a feed or guild
+func promptsKey(scope PromptScope, id string) (string, error) {
+	switch scope {
+	case PromptScopeFeed:
+		return fmt.Sprintf(feedPromptsKey, id), nil
+	case PromptScopeGuild:
+		return fmt.Sprintf(guildPromptsKey, id), nil
+	default:
+		return "", fmt.Errorf("unknown prompt scope %q", scope)
+	}
+}
+
+// SetPromptTemplate stores the template of a kind for a feed or guild
+func (r *RedisPromptRepository) SetPromptTemplate(scope PromptScope, id string, kind ai.PromptKind, text string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	key, err := promptsKey(scope, id)
+	if err != nil {
+		return err
+	}
+	if err := r.client.HSet(ctx, key, string(kind), text).Err(); err != nil {
+		return fmt.Errorf("failed to set %s prompt template: %w", kind, err)
+	}
+	return nil
+}
+
+// GetPromptTemplate returns the template of a kind for a feed or guild, or "" when there is none
+func (r *RedisPromptRepository) GetPromptTemplate(scope PromptScope, id string, kind ai.PromptKind) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	key, err := promptsKey(scope, id)
+	if err != nil {
+		return "", err
+	}
+	text, err := r.client.HGet(ctx, key, string(kind)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s prompt template: %w", kind, err)
+	}
+	return text, nil
+}
+
+// DeletePromptTemplate removes the template of a kind for a feed or guild
+func (r *RedisPromptRepository) DeletePromptTemplate(scope PromptScope, id string, kind ai.PromptKind) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	key, err := promptsKey(scope, id)
+	if err != nil {
+		return err
+	}
+	if err := r.client.HDel(ctx, key, string(kind)).Err(); err != nil {
+		return fmt.Errorf("failed to delete %s prompt template: %w", kind, err)
+	}
+	return nil
+}
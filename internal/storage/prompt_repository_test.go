@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisPromptRepository(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisPromptRepository(client)
+
+	text, err := repo.GetPromptTemplate(PromptScopeFeed, "godot", ai.PromptArticle)
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	require.NoError(t, repo.SetPromptTemplate(PromptScopeFeed, "godot", ai.PromptArticle, "TL;DR: {{.Content}}"))
+	require.NoError(t, repo.SetPromptTemplate(PromptScopeGuild, "guild-1", ai.PromptPRBatch, "Changes in {{.Repo}}"))
+
+	text, err = repo.GetPromptTemplate(PromptScopeFeed, "godot", ai.PromptArticle)
+	require.NoError(t, err)
+	assert.Equal(t, "TL;DR: {{.Content}}", text)
+
+	// Scopes, identifiers and kinds are stored separately
+	for _, key := range []struct {
+		scope PromptScope
+		id    string
+		kind  ai.PromptKind
+	}{
+		{PromptScopeGuild, "godot", ai.PromptArticle},
+		{PromptScopeFeed, "gdquest", ai.PromptArticle},
+		{PromptScopeFeed, "godot", ai.PromptPRBatch},
+	} {
+		text, err := repo.GetPromptTemplate(key.scope, key.id, key.kind)
+		require.NoError(t, err)
+		assert.Empty(t, text, "%v", key)
+	}
+
+	require.NoError(t, repo.DeletePromptTemplate(PromptScopeGuild, "guild-1", ai.PromptPRBatch))
+	text, err = repo.GetPromptTemplate(PromptScopeGuild, "guild-1", ai.PromptPRBatch)
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	_, err = repo.GetPromptTemplate("channel", "1", ai.PromptArticle)
+	assert.Error(t, err)
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const guildQuotasKey = "news:guilds:%s:quotas" // news:guilds:{guildID}:quotas (HASH resource -> limit)
+
+// QuotaResource is something a guild has a quota of
+type QuotaResource string
+
+// Quota resources
+const (
+	QuotaChannels QuotaResource = "channels" // Channels subscribed to feeds or repositories
+	QuotaFeeds    QuotaResource = "feeds"    // Feeds registered by the guild (catalog feeds don't count)
+	QuotaRepos    QuotaResource = "repos"    // Repositories registered by the guild (catalog repositories don't count)
+)
+
+// QuotaResources returns every quota resource
+func QuotaResources() []QuotaResource {
+	return []QuotaResource{QuotaChannels, QuotaFeeds, QuotaRepos}
+}
+
+// ParseQuotaResource validates a quota resource name
+func ParseQuotaResource(name string) (QuotaResource, error) {
+	for _, resource := range QuotaResources() {
+		if string(resource) == name {
+			return resource, nil
+		}
+	}
+	return "", fmt.Errorf("unknown quota resource %q (expected channels, feeds or repos)", name)
+}
+
+// Quotas holds a limit, or a usage, per quota resource; a limit of 0 means unlimited
+type Quotas struct {
+	Channels int
+	Feeds    int
+	Repos    int
+}
+
+// Get returns the value of a resource
+func (q Quotas) Get(resource QuotaResource) int {
+	switch resource {
+	case QuotaChannels:
+		return q.Channels
+	case QuotaFeeds:
+		return q.Feeds
+	case QuotaRepos:
+		return q.Repos
+	default:
+		return 0
+	}
+}
+
+// set sets the value of a resource
+func (q *Quotas) set(resource QuotaResource, value int) {
+	switch resource {
+	case QuotaChannels:
+		q.Channels = value
+	case QuotaFeeds:
+		q.Feeds = value
+	case QuotaRepos:
+		q.Repos = value
+	}
+}
+
+// QuotaExceededError is returned when an addition would take a guild over its quota
+type QuotaExceededError struct {
+	Resource QuotaResource
+	Used     int
+	Limit    int
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("guild quota of %s reached (%d/%d)", e.Resource, e.Used, e.Limit)
+}
+
+// checkGuildChannelQuota watches the guild's channel sets and fails with a QuotaExceededError
+// when subscribing the channel would take the guild over limit channels (0 = unlimited).
+// Channels subscribed to both feeds and repositories count once, and channels already
+// subscribed to anything are counted already.
+func checkGuildChannelQuota(ctx context.Context, tx *redis.Tx, guildID, channelID string, limit int) error {
+	if guildID == "" || limit <= 0 {
+		return nil
+	}
+
+	feedChannelsKey := fmt.Sprintf(guildChannelsKey, guildID)
+	repoChannelsKey := fmt.Sprintf(guildChannelsPrefix, guildID)
+	if err := tx.Watch(ctx, feedChannelsKey, repoChannelsKey).Err(); err != nil {
+		return fmt.Errorf("failed to watch guild channels: %w", err)
+	}
+
+	channels, err := tx.SUnion(ctx, feedChannelsKey, repoChannelsKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get guild channels: %w", err)
+	}
+	for _, subscribed := range channels {
+		if subscribed == channelID {
+			return nil
+		}
+	}
+	if len(channels) >= limit {
+		return &QuotaExceededError{Resource: QuotaChannels, Used: len(channels), Limit: limit}
+	}
+	return nil
+}
+
+// QuotaRepository stores the quotas of each guild: a default for every guild, overridden per
+// guild by the bot owners
+type QuotaRepository interface {
+	// GetGuildQuotas returns the quotas of a guild, its overrides applied to the defaults
+	GetGuildQuotas(guildID string) (Quotas, error)
+	// GetQuotaOverrides returns the quotas overridden for a guild
+	GetQuotaOverrides(guildID string) (map[QuotaResource]int, error)
+	// SetGuildQuota overrides the quota of a resource for a guild (0 = unlimited)
+	SetGuildQuota(guildID string, resource QuotaResource, limit int) error
+	// ResetGuildQuota goes back to the default quota of a resource for a guild
+	ResetGuildQuota(guildID string, resource QuotaResource) error
+	// DefaultQuotas returns the quotas of guilds without overrides
+	DefaultQuotas() Quotas
+}
+
+// RedisQuotaRepository implements QuotaRepository using Redis
+type RedisQuotaRepository struct {
+	client   *redis.Client
+	defaults Quotas
+}
+
+// NewRedisQuotaRepository creates a new Redis-based quota repository with the default quotas
+// of every guild
+func NewRedisQuotaRepository(client *redis.Client, defaults Quotas) *RedisQuotaRepository {
+	return &RedisQuotaRepository{client: client, defaults: defaults}
+}
+
+// DefaultQuotas returns the quotas of guilds without overrides
+func (r *RedisQuotaRepository) DefaultQuotas() Quotas {
+	return r.defaults
+}
+
+// GetGuildQuotas returns the quotas of a guild, its overrides applied to the defaults
+func (r *RedisQuotaRepository) GetGuildQuotas(guildID string) (Quotas, error) {
+	overrides, err := r.GetQuotaOverrides(guildID)
+	if err != nil {
+		return Quotas{}, err
+	}
+
+	quotas := r.defaults
+	for resource, limit := range overrides {
+		quotas.set(resource, limit)
+	}
+	return quotas, nil
+}
+
+// GetQuotaOverrides returns the quotas overridden for a guild
+func (r *RedisQuotaRepository) GetQuotaOverrides(guildID string) (map[QuotaResource]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	values, err := r.client.HGetAll(ctx, fmt.Sprintf(guildQuotasKey, guildID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild quotas: %w", err)
+	}
+
+	overrides := make(map[QuotaResource]int, len(values))
+	for name, value := range values {
+		resource, err := ParseQuotaResource(name)
+		if err != nil {
+			continue // Field of a resource that no longer exists
+		}
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s quota %q: %w", resource, value, err)
+		}
+		overrides[resource] = limit
+	}
+	return overrides, nil
+}
+
+// SetGuildQuota overrides the quota of a resource for a guild (0 = unlimited)
+func (r *RedisQuotaRepository) SetGuildQuota(guildID string, resource QuotaResource, limit int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if limit < 0 {
+		return fmt.Errorf("invalid %s quota %d: must be 0 (unlimited) or more", resource, limit)
+	}
+	if err := r.client.HSet(ctx, fmt.Sprintf(guildQuotasKey, guildID), string(resource), limit).Err(); err != nil {
+		return fmt.Errorf("failed to set %s quota: %w", resource, err)
+	}
+	return nil
+}
+
+// ResetGuildQuota goes back to the default quota of a resource for a guild
+func (r *RedisQuotaRepository) ResetGuildQuota(guildID string, resource QuotaResource) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := r.client.HDel(ctx, fmt.Sprintf(guildQuotasKey, guildID), string(resource)).Err(); err != nil {
+		return fmt.Errorf("failed to reset %s quota: %w", resource, err)
+	}
+	return nil
+}
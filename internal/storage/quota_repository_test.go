@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisQuotaRepository(t *testing.T) {
+	_, client := setupTestRedis(t)
+	defaults := Quotas{Channels: 5, Feeds: 10, Repos: 3}
+	repo := NewRedisQuotaRepository(client, defaults)
+
+	quotas, err := repo.GetGuildQuotas("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, defaults, quotas)
+
+	require.NoError(t, repo.SetGuildQuota("guild-1", QuotaChannels, 20))
+	require.NoError(t, repo.SetGuildQuota("guild-1", QuotaRepos, 0))
+
+	quotas, err = repo.GetGuildQuotas("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, Quotas{Channels: 20, Feeds: 10, Repos: 0}, quotas)
+	assert.Equal(t, 20, quotas.Get(QuotaChannels))
+
+	overrides, err := repo.GetQuotaOverrides("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[QuotaResource]int{QuotaChannels: 20, QuotaRepos: 0}, overrides)
+
+	// Overrides only apply to their guild
+	quotas, err = repo.GetGuildQuotas("guild-2")
+	require.NoError(t, err)
+	assert.Equal(t, defaults, quotas)
+
+	require.NoError(t, repo.ResetGuildQuota("guild-1", QuotaChannels))
+	quotas, err = repo.GetGuildQuotas("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, Quotas{Channels: 5, Feeds: 10, Repos: 0}, quotas)
+
+	assert.Error(t, repo.SetGuildQuota("guild-1", QuotaFeeds, -1))
+}
+
+func TestParseQuotaResource(t *testing.T) {
+	for _, resource := range QuotaResources() {
+		parsed, err := ParseQuotaResource(string(resource))
+		require.NoError(t, err)
+		assert.Equal(t, resource, parsed)
+	}
+
+	_, err := ParseQuotaResource("members")
+	assert.Error(t, err)
+}
+
+func TestGuildChannelQuota(t *testing.T) {
+	_, client := setupTestRedis(t)
+	channelRepo, err := NewRedisChannelRepository(client, 0)
+	require.NoError(t, err)
+	githubRepo := NewRedisGitHubRepository(client)
+
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch1", "godot", 2))
+	require.NoError(t, githubRepo.AddRepoChannel("guild-1", "engine", "ch2", 2))
+
+	// Channels subscribed to feeds and to repositories share the quota
+	var quotaErr *QuotaExceededError
+	err = channelRepo.AddChannel("guild-1", "ch3", "godot", 2)
+	require.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, QuotaExceededError{Resource: QuotaChannels, Used: 2, Limit: 2}, *quotaErr)
+	assert.ErrorAs(t, githubRepo.AddRepoChannel("guild-1", "engine", "ch3", 2), &quotaErr)
+
+	// Channels already subscribed to anything are counted already
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch2", "godot", 2))
+	require.NoError(t, githubRepo.AddRepoChannel("guild-1", "engine", "ch1", 2))
+
+	// Other guilds and unlimited quotas aren't affected
+	require.NoError(t, channelRepo.AddChannel("guild-2", "ch4", "godot", 1))
+	require.NoError(t, channelRepo.AddChannel("guild-1", "ch3", "godot", 0))
+}
+
+func TestGuildChannelQuota_ConcurrentSubscriptions(t *testing.T) {
+	_, client := setupTestRedis(t)
+	channelRepo, err := NewRedisChannelRepository(client, 0)
+	require.NoError(t, err)
+	githubRepo := NewRedisGitHubRepository(client)
+
+	// Every subscription races for the last free channel of the guild
+	const subscriptions = 10
+	errs := make([]error, subscriptions)
+	var wg sync.WaitGroup
+	for i := 0; i < subscriptions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			channelID := fmt.Sprintf("ch%d", i)
+			if i%2 == 0 {
+				errs[i] = channelRepo.AddChannel("guild-1", channelID, "godot", 1)
+			} else {
+				errs[i] = githubRepo.AddRepoChannel("guild-1", "engine", channelID, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+
+	feedChannels, err := channelRepo.GetGuildChannels("guild-1")
+	require.NoError(t, err)
+	repoChannels, err := githubRepo.GetGuildRepoChannels("guild-1")
+	require.NoError(t, err)
+	assert.Len(t, append(feedChannels, repoChannels...), 1)
+}
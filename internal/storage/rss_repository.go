@@ -2,10 +2,15 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/GustavoLR548/godot-news-bot/internal/filter"
+	"github.com/GustavoLR548/godot-news-bot/internal/schedule"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -13,20 +18,31 @@ const (
 	// Redis key prefixes
 	channelsKey     = "news:channels"
 	historyPrefix   = "news:history:"
-	lastGUIDKey     = "news:last_guid"
 	maxLimitKey     = "news:config:max_channels"
-	pendingQueueKey = "news:pending_queue"
 	feedsPrefix     = "news:feeds:"           // news:feeds:{identifier}
 	feedScheduleKey = "news:feeds:%s:schedule" // news:feeds:{identifier}:schedule
+	feedHTTPKey     = "news:feeds:%s:http"     // news:feeds:{identifier}:http (ETag/Last-Modified)
+	feedFiltersKey  = "news:feeds:%s:filters"  // news:feeds:{identifier}:filters
+	feedLastRunKey  = "news:feeds:%s:last_run" // news:feeds:{identifier}:last_run (unix timestamp)
+	feedChannelsKey = "news:feeds:%s:channels" // news:feeds:{identifier}:channels (subscribed channels)
 	channelFeedsKey = "news:channels:%s:feeds" // news:channels:{channelID}:feeds
-	maxPendingItems = 5
+	channelStyleKey = "news:channels:%s:style" // news:channels:{channelID}:style (summary style)
+	channelGuildKey = "news:channels:%s:guild" // news:channels:{channelID}:guild (guild of the channel)
+	// news:channels:{channelID}:filters:{identifier}
+	channelFeedFiltersKey = "news:channels:%s:filters:%s"
+	guildChannelsKey      = "news:guilds:%s:channels" // news:guilds:{guildID}:channels (subscribed channels)
+	guildFeedsKey         = "news:guilds:%s:feeds"    // news:guilds:{guildID}:feeds (feeds registered by the guild)
+	maxPendingItems       = 5
+	maxFilterRules        = 25
+	maxTxRetries          = 5 // attempts of a WATCH transaction whose keys keep changing
 	defaultTimeout  = 5 * time.Second
 )
 
 // ChannelRepository defines the interface for managing Discord channels
 type ChannelRepository interface {
-	// AddChannel adds a new channel with feed association if limit not exceeded
-	AddChannel(channelID string, feedID string) error
+	// AddChannel adds a channel of a guild with feed association if neither the global limit nor
+	// the guild's channel quota (maxGuildChannels, 0 = unlimited) is exceeded
+	AddChannel(guildID, channelID, feedID string, maxGuildChannels int) error
 	// RemoveChannel removes a channel and its feed association
 	RemoveChannel(channelID string, feedID string) error
 	// GetAllChannels returns all registered channel IDs (across all feeds)
@@ -35,6 +51,12 @@ type ChannelRepository interface {
 	GetChannelFeeds(channelID string) ([]string, error)
 	// GetChannelCount returns the current number of unique channels
 	GetChannelCount() (int, error)
+	// GetGuildChannelCount returns the number of unique channels of a guild
+	GetGuildChannelCount(guildID string) (int, error)
+	// GetGuildChannels returns the channels of a guild subscribed to any feed
+	GetGuildChannels(guildID string) ([]string, error)
+	// GetChannelGuild returns the guild of a channel subscribed to any feed, or "" when unknown
+	GetChannelGuild(channelID string) (string, error)
 	// HasChannel checks if a channel is registered for any feed
 	HasChannel(channelID string) (bool, error)
 	// GetFeedChannels returns all channels subscribed to a specific feed
@@ -43,7 +65,10 @@ type ChannelRepository interface {
 	SetChannelLanguage(channelID, languageCode string) error
 	GetChannelLanguage(channelID string) (string, error)
 	SetGuildLanguage(guildID, languageCode string) error
-	GetGuildLanguage(guildID string) (string, error)}
+	GetGuildLanguage(guildID string) (string, error)
+	// Summary style preferences ("" = standard)
+	SetChannelStyle(channelID, style string) error
+	GetChannelStyle(channelID string) (string, error)}
 
 // RSSFeed represents an RSS feed configuration
 type RSSFeed struct {
@@ -52,25 +77,65 @@ type RSSFeed struct {
 	Title       string
 	Description string
 	AddedAt     time.Time
-	Schedule    []string // Array of times in "HH:MM" format
+	Schedule    []string // Schedule entries: "HH:MM", "every <duration>" (both optionally restricted to weekdays) or cron
+	Timezone    string   // IANA timezone the schedule is evaluated in (empty = bot's local time)
+	SkipScrape  bool     // Never scrape article pages, only use the content provided by the feed
+	Extractive  bool     // Summarize articles offline with the extractive summarizer instead of the AI
+	// OwnerGuildID is the guild that registered the feed; catalog feeds (empty) are visible to
+	// every guild and managed by the bot owners
+	OwnerGuildID string
+}
+
+// IsCatalog reports whether the feed is a public catalog entry, managed by the bot owners
+func (f RSSFeed) IsCatalog() bool {
+	return f.OwnerGuildID == ""
+}
+
+// VisibleTo reports whether channels of the guild can see and subscribe to the feed
+func (f RSSFeed) VisibleTo(guildID string) bool {
+	return f.IsCatalog() || f.OwnerGuildID == guildID
 }
 
 // RSSFeedRepository defines the interface for managing RSS feeds
 type RSSFeedRepository interface {
 	// RegisterFeed adds a new feed with the given identifier and URL
 	RegisterFeed(feed RSSFeed) error
-	// UnregisterFeed removes a feed by identifier
+	// UnregisterFeed removes a feed by identifier and unsubscribes its channels
 	UnregisterFeed(feedID string) error
 	// GetFeed returns feed details by identifier
 	GetFeed(feedID string) (*RSSFeed, error)
 	// GetAllFeeds returns all registered feeds
 	GetAllFeeds() ([]RSSFeed, error)
+	// GetGuildFeedCount returns the number of feeds registered by a guild (catalog feeds excluded)
+	GetGuildFeedCount(guildID string) (int, error)
 	// HasFeed checks if a feed exists
 	HasFeed(feedID string) (bool, error)
 	// SetSchedule sets check times for a feed (e.g., ["09:00", "13:00", "18:00"])
 	SetSchedule(feedID string, times []string) error
 	// GetSchedule returns scheduled check times for a feed
 	GetSchedule(feedID string) ([]string, error)
+	// SetTimezone sets the IANA timezone the feed's schedule is evaluated in (empty = bot's local time)
+	SetTimezone(feedID, timezone string) error
+	// SetSkipScrape sets whether article pages of the feed are never scraped
+	SetSkipScrape(feedID string, skip bool) error
+	// SetExtractive sets whether articles of the feed are summarized offline instead of by the AI
+	SetExtractive(feedID string, extractive bool) error
+	// GetLastRun returns when the feed was last checked successfully (zero if never)
+	GetLastRun(feedID string) (time.Time, error)
+	// SetLastRun records when the feed was last checked successfully
+	SetLastRun(feedID string, t time.Time) error
+	// GetHTTPValidators returns the ETag and Last-Modified values from the feed's last fetch
+	GetHTTPValidators(feedID string) (etag, lastModified string, err error)
+	// SetHTTPValidators stores the ETag and Last-Modified values from the feed's last fetch
+	SetHTTPValidators(feedID, etag, lastModified string) error
+	// AddFilter attaches a filter rule to a feed, or to a channel's subscription when channelID is set
+	AddFilter(feedID, channelID string, rule filter.Rule) error
+	// RemoveFilter removes the filter rule at the given 1-based position
+	RemoveFilter(feedID, channelID string, position int) error
+	// GetFilters returns the filter rules of a feed, or of a channel's subscription when channelID is set
+	GetFilters(feedID, channelID string) ([]filter.Rule, error)
+	// ClearFilters removes all filter rules of a feed, or of a channel's subscription when channelID is set
+	ClearFilters(feedID, channelID string) error
 }
 
 // RSSHistoryRepository defines the interface for tracking posted articles per feed
@@ -89,15 +154,18 @@ type RSSHistoryRepository interface {
 	RemoveFromPending(feedID, guid string) error
 	// IsPending checks if a GUID is in the pending queue for a specific feed
 	IsPending(feedID, guid string) (bool, error)
+	// ClearPending empties the pending queue of a specific feed and returns how many GUIDs it held
+	ClearPending(feedID string) (int, error)
 }
 
 // RedisChannelRepository implements ChannelRepository using Redis
 type RedisChannelRepository struct {
 	client   *redis.Client
-	maxLimit int
+	maxLimit int // Global channel cap across all guilds (0 = no cap)
 }
 
-// NewRedisChannelRepository creates a new Redis-based channel repository
+// NewRedisChannelRepository creates a new Redis-based channel repository; maxLimit caps the
+// channels of all guilds together, 0 disables the cap
 func NewRedisChannelRepository(client *redis.Client, maxLimit int) (*RedisChannelRepository, error) {
 	repo := &RedisChannelRepository{
 		client:   client,
@@ -115,52 +183,67 @@ func NewRedisChannelRepository(client *redis.Client, maxLimit int) (*RedisChanne
 	return repo, nil
 }
 
-// AddChannel adds a new channel with feed association if limit not exceeded
-func (r *RedisChannelRepository) AddChannel(channelID string, feedID string) error {
+// AddChannel adds a channel of a guild with feed association if neither the global limit nor
+// the guild's channel quota (maxGuildChannels, 0 = unlimited) is exceeded. A quota overrun
+// returns a *QuotaExceededError.
+func (r *RedisChannelRepository) AddChannel(guildID, channelID, feedID string, maxGuildChannels int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
 	channelFeedsKeyFormatted := fmt.Sprintf(channelFeedsKey, channelID)
 
-	// Check if channel-feed pair already exists
-	exists, err := r.client.SIsMember(ctx, channelFeedsKeyFormatted, feedID).Result()
-	if err != nil {
-		return fmt.Errorf("failed to check channel-feed existence: %w", err)
-	}
-	if exists {
-		return fmt.Errorf("channel %s already subscribed to feed %s", channelID, feedID)
-	}
+	// Check the limits and subscribe in one transaction, retried when another subscription
+	// changes the channels in between
+	return watchTx(ctx, r.client, func(tx *redis.Tx) error {
+		// Check if channel-feed pair already exists
+		exists, err := tx.SIsMember(ctx, channelFeedsKeyFormatted, feedID).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check channel-feed existence: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("channel %s already subscribed to feed %s", channelID, feedID)
+		}
 
-	// Get unique channel count
-	count, err := r.GetChannelCount()
-	if err != nil {
-		return fmt.Errorf("failed to get channel count: %w", err)
-	}
+		// Check if adding new channel (not just new feed to existing channel)
+		hasChannel, err := tx.SIsMember(ctx, channelsKey, channelID).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check channel: %w", err)
+		}
 
-	// Check if adding new channel (not just new feed to existing channel)
-	hasChannel, err := r.HasChannel(channelID)
-	if err != nil {
-		return fmt.Errorf("failed to check channel: %w", err)
-	}
+		if !hasChannel && r.maxLimit > 0 {
+			count, err := tx.SCard(ctx, channelsKey).Result()
+			if err != nil {
+				return fmt.Errorf("failed to get channel count: %w", err)
+			}
+			if int(count) >= r.maxLimit {
+				return fmt.Errorf("channel limit reached (%d/%d)", count, r.maxLimit)
+			}
+		}
 
-	if !hasChannel && count >= r.maxLimit {
-		return fmt.Errorf("channel limit reached (%d/%d)", count, r.maxLimit)
-	}
+		if err := checkGuildChannelQuota(ctx, tx, guildID, channelID, maxGuildChannels); err != nil {
+			return err
+		}
 
-	// Use pipeline for atomic operations
-	pipe := r.client.Pipeline()
-	
-	// Add channel to global set
-	pipe.SAdd(ctx, channelsKey, channelID)
-	
-	// Add feed to channel's feed set
-	pipe.SAdd(ctx, channelFeedsKeyFormatted, feedID)
-	
-	if _, err := pipe.Exec(ctx); err != nil {
-		return fmt.Errorf("failed to add channel: %w", err)
-	}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			// Add channel to global set
+			pipe.SAdd(ctx, channelsKey, channelID)
 
-	return nil
+			// Add feed to channel's feed set, and channel to the feed's channel set
+			pipe.SAdd(ctx, channelFeedsKeyFormatted, feedID)
+			pipe.SAdd(ctx, fmt.Sprintf(feedChannelsKey, feedID), channelID)
+
+			// Record the channel's guild, which per-guild quotas count against
+			if guildID != "" {
+				pipe.Set(ctx, fmt.Sprintf(channelGuildKey, channelID), guildID, 0)
+				pipe.SAdd(ctx, fmt.Sprintf(guildChannelsKey, guildID), channelID)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add channel: %w", err)
+		}
+		return nil
+	}, channelsKey, channelFeedsKeyFormatted)
 }
 
 // RemoveChannel removes a channel's association with a specific feed
@@ -170,38 +253,85 @@ func (r *RedisChannelRepository) RemoveChannel(channelID string, feedID string)
 
 	channelFeedsKeyFormatted := fmt.Sprintf(channelFeedsKey, channelID)
 
-	// Check if channel-feed pair exists
-	exists, err := r.client.SIsMember(ctx, channelFeedsKeyFormatted, feedID).Result()
-	if err != nil {
-		return fmt.Errorf("failed to check channel-feed existence: %w", err)
-	}
-	if !exists {
-		return fmt.Errorf("channel %s not subscribed to feed %s", channelID, feedID)
+	return watchTx(ctx, r.client, func(tx *redis.Tx) error {
+		// Check if channel-feed pair exists
+		exists, err := tx.SIsMember(ctx, channelFeedsKeyFormatted, feedID).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check channel-feed existence: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("channel %s not subscribed to feed %s", channelID, feedID)
+		}
+
+		// Check if this is the channel's last feed
+		lastFeed, guildID, err := lastChannelFeed(ctx, tx, channelID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			// Remove the subscription from both sides along with its filters
+			pipe.SRem(ctx, channelFeedsKeyFormatted, feedID)
+			pipe.SRem(ctx, fmt.Sprintf(feedChannelsKey, feedID), channelID)
+			pipe.Del(ctx, fmt.Sprintf(channelFeedFiltersKey, channelID, feedID))
+
+			// If no more feeds, remove channel from global set and cleanup
+			if lastFeed {
+				removeChannel(ctx, pipe, channelID, guildID)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove feed from channel: %w", err)
+		}
+		return nil
+	}, channelFeedsKeyFormatted)
+}
+
+// watchTx runs fn as a WATCH/MULTI/EXEC transaction on keys, retrying when a watched key
+// changes before the transaction executes
+func watchTx(ctx context.Context, client *redis.Client, fn func(tx *redis.Tx) error, keys ...string) error {
+	var err error
+	for attempt := 0; attempt < maxTxRetries; attempt++ {
+		err = client.Watch(ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
 	}
+	return fmt.Errorf("transaction kept conflicting after %d attempts: %w", maxTxRetries, err)
+}
 
-	// Remove feed from channel's feed set
-	if err := r.client.SRem(ctx, channelFeedsKeyFormatted, feedID).Err(); err != nil {
-		return fmt.Errorf("failed to remove feed from channel: %w", err)
+// lastChannelFeed watches a channel's feed set and reports whether it holds a single feed,
+// along with the channel's guild
+func lastChannelFeed(ctx context.Context, tx *redis.Tx, channelID string) (bool, string, error) {
+	channelFeedsKeyFormatted := fmt.Sprintf(channelFeedsKey, channelID)
+	if err := tx.Watch(ctx, channelFeedsKeyFormatted).Err(); err != nil {
+		return false, "", fmt.Errorf("failed to watch channel feeds: %w", err)
 	}
 
-	// Check if channel has any remaining feeds
-	feedCount, err := r.client.SCard(ctx, channelFeedsKeyFormatted).Result()
+	feedCount, err := tx.SCard(ctx, channelFeedsKeyFormatted).Result()
 	if err != nil {
-		return fmt.Errorf("failed to check remaining feeds: %w", err)
+		return false, "", fmt.Errorf("failed to check remaining feeds: %w", err)
+	}
+	if feedCount > 1 {
+		return false, "", nil
 	}
 
-	// If no more feeds, remove channel from global set and cleanup
-	if feedCount == 0 {
-		pipe := r.client.Pipeline()
-		pipe.SRem(ctx, channelsKey, channelID)
-		pipe.Del(ctx, channelFeedsKeyFormatted)
-		
-		if _, err := pipe.Exec(ctx); err != nil {
-			return fmt.Errorf("failed to cleanup channel: %w", err)
-		}
+	guildID, err := tx.Get(ctx, fmt.Sprintf(channelGuildKey, channelID)).Result()
+	if err != nil && err != redis.Nil {
+		return false, "", fmt.Errorf("failed to get channel guild: %w", err)
 	}
+	return true, guildID, nil
+}
 
-	return nil
+// removeChannel queues the removal of a channel left without feeds
+func removeChannel(ctx context.Context, pipe redis.Pipeliner, channelID, guildID string) {
+	pipe.SRem(ctx, channelsKey, channelID)
+	pipe.Del(ctx, fmt.Sprintf(channelFeedsKey, channelID))
+	pipe.Del(ctx, fmt.Sprintf(channelGuildKey, channelID))
+	if guildID != "" {
+		pipe.SRem(ctx, fmt.Sprintf(guildChannelsKey, guildID), channelID)
+	}
 }
 
 // GetAllChannels returns all registered channel IDs
@@ -230,6 +360,48 @@ func (r *RedisChannelRepository) GetChannelCount() (int, error) {
 	return int(count), nil
 }
 
+// GetGuildChannelCount returns the number of unique channels of a guild
+func (r *RedisChannelRepository) GetGuildChannelCount(guildID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	count, err := r.client.SCard(ctx, fmt.Sprintf(guildChannelsKey, guildID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get guild channel count: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// GetGuildChannels returns the channels of a guild subscribed to any feed
+func (r *RedisChannelRepository) GetGuildChannels(guildID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	channels, err := r.client.SMembers(ctx, fmt.Sprintf(guildChannelsKey, guildID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild channels: %w", err)
+	}
+
+	return channels, nil
+}
+
+// GetChannelGuild returns the guild of a channel subscribed to any feed, or "" when unknown
+func (r *RedisChannelRepository) GetChannelGuild(channelID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	guildID, err := r.client.Get(ctx, fmt.Sprintf(channelGuildKey, channelID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get channel guild: %w", err)
+	}
+
+	return guildID, nil
+}
+
 // HasChannel checks if a channel is already registered
 func (r *RedisChannelRepository) HasChannel(channelID string) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
@@ -259,29 +431,15 @@ func (r *RedisChannelRepository) GetChannelFeeds(channelID string) ([]string, er
 
 // GetFeedChannels returns all channels subscribed to a specific feed
 func (r *RedisChannelRepository) GetFeedChannels(feedID string) ([]string, error) {
-	// Get all channels
-	channels, err := r.GetAllChannels()
-	if err != nil {
-		return nil, err
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
 
-	// Filter channels that have this feed
-	var feedChannels []string
-	for _, channelID := range channels {
-		feeds, err := r.GetChannelFeeds(channelID)
-		if err != nil {
-			continue
-		}
-		
-		for _, fid := range feeds {
-			if fid == feedID {
-				feedChannels = append(feedChannels, channelID)
-				break
-			}
-		}
+	channels, err := r.client.SMembers(ctx, fmt.Sprintf(feedChannelsKey, feedID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed channels: %w", err)
 	}
 
-	return feedChannels, nil
+	return channels, nil
 }
 
 // Language preference methods
@@ -290,6 +448,10 @@ func (r *RedisChannelRepository) SetChannelLanguage(channelID, languageCode stri
 	defer cancel()
 	
 	key := fmt.Sprintf("news:channels:%s:language", channelID)
+	if languageCode == "" {
+		log.Printf("[CHANNEL-REPO] Clearing language of channel %s", channelID)
+		return r.client.Del(ctx, key).Err()
+	}
 	log.Printf("[CHANNEL-REPO] Setting language for channel %s: %s", channelID, languageCode)
 	return r.client.Set(ctx, key, languageCode, 0).Err()
 }
@@ -313,6 +475,35 @@ func (r *RedisChannelRepository) GetChannelLanguage(channelID string) (string, e
 	return result, nil
 }
 
+// SetChannelStyle sets the summary style of a channel; an empty style goes back to the standard one
+func (r *RedisChannelRepository) SetChannelStyle(channelID, style string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	key := fmt.Sprintf(channelStyleKey, channelID)
+	if style == "" {
+		log.Printf("[CHANNEL-REPO] Clearing summary style of channel %s", channelID)
+		return r.client.Del(ctx, key).Err()
+	}
+	log.Printf("[CHANNEL-REPO] Setting summary style for channel %s: %s", channelID, style)
+	return r.client.Set(ctx, key, style, 0).Err()
+}
+
+// GetChannelStyle returns the summary style of a channel, or "" when it uses the standard one
+func (r *RedisChannelRepository) GetChannelStyle(channelID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	result, err := r.client.Get(ctx, fmt.Sprintf(channelStyleKey, channelID)).Result()
+	if err == redis.Nil {
+		return "", nil // No style set
+	}
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
 func (r *RedisChannelRepository) SetGuildLanguage(guildID, languageCode string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
@@ -484,6 +675,23 @@ func (r *RedisRSSHistoryRepository) IsPending(feedID, guid string) (bool, error)
 	return false, nil
 }
 
+// ClearPending empties the pending queue of a specific feed and returns how many GUIDs it held
+func (r *RedisRSSHistoryRepository) ClearPending(feedID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	pendingKey := fmt.Sprintf("%s:%s:pending", historyPrefix, feedID)
+
+	pipe := r.client.TxPipeline()
+	count := pipe.LLen(ctx, pendingKey)
+	pipe.Del(ctx, pendingKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to clear pending queue: %w", err)
+	}
+
+	return int(count.Val()), nil
+}
+
 // RedisRSSFeedRepository implements RSSFeedRepository using Redis
 type RedisRSSFeedRepository struct {
 	client *redis.Client
@@ -523,8 +731,26 @@ func (r *RedisRSSFeedRepository) RegisterFeed(feed RSSFeed) error {
 		"description": feed.Description,
 		"added_at":    feed.AddedAt.Unix(),
 	}
+	if feed.Timezone != "" {
+		feedData["timezone"] = feed.Timezone
+	}
+	if feed.SkipScrape {
+		feedData["skip_scrape"] = "1"
+	}
+	if feed.Extractive {
+		feedData["extractive"] = "1"
+	}
+	if feed.OwnerGuildID != "" {
+		feedData["owner_guild"] = feed.OwnerGuildID
+	}
 
-	if err := r.client.HSet(ctx, feedKey, feedData).Err(); err != nil {
+	// Store the feed along with its guild's index, which the feed quota counts
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, feedKey, feedData)
+	if feed.OwnerGuildID != "" {
+		pipe.SAdd(ctx, fmt.Sprintf(guildFeedsKey, feed.OwnerGuildID), feed.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		log.Printf("[FEED-REPO] ERROR: Failed to store feed: %v", err)
 		return fmt.Errorf("failed to register feed: %w", err)
 	}
@@ -534,6 +760,9 @@ func (r *RedisRSSFeedRepository) RegisterFeed(feed RSSFeed) error {
 		if err := r.SetSchedule(feed.ID, feed.Schedule); err != nil {
 			// Cleanup feed if schedule fails
 			r.client.Del(ctx, feedKey)
+			if feed.OwnerGuildID != "" {
+				r.client.SRem(ctx, fmt.Sprintf(guildFeedsKey, feed.OwnerGuildID), feed.ID)
+			}
 			log.Printf("[FEED-REPO] ERROR: Failed to set schedule: %v", err)
 			return fmt.Errorf("failed to set schedule: %w", err)
 		}
@@ -543,33 +772,84 @@ func (r *RedisRSSFeedRepository) RegisterFeed(feed RSSFeed) error {
 	return nil
 }
 
-// UnregisterFeed removes a feed by identifier
+// UnregisterFeed removes a feed by identifier and unsubscribes its channels
 func (r *RedisRSSFeedRepository) UnregisterFeed(feedID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
 	feedKey := feedsPrefix + feedID
 	scheduleKey := fmt.Sprintf(feedScheduleKey, feedID)
+	httpKey := fmt.Sprintf(feedHTTPKey, feedID)
+	filtersKey := fmt.Sprintf(feedFiltersKey, feedID)
+	lastRunKey := fmt.Sprintf(feedLastRunKey, feedID)
+	promptsKey := fmt.Sprintf(feedPromptsKey, feedID)
 
-	// Check if feed exists
-	exists, err := r.client.Exists(ctx, feedKey).Result()
-	if err != nil {
-		return fmt.Errorf("failed to check feed existence: %w", err)
-	}
-	if exists == 0 {
-		return fmt.Errorf("feed %s not found", feedID)
-	}
+	channelsKeyFormatted := fmt.Sprintf(feedChannelsKey, feedID)
 
-	// Delete feed and schedule
-	pipe := r.client.Pipeline()
-	pipe.Del(ctx, feedKey)
-	pipe.Del(ctx, scheduleKey)
-	
-	if _, err := pipe.Exec(ctx); err != nil {
-		return fmt.Errorf("failed to unregister feed: %w", err)
-	}
+	return watchTx(ctx, r.client, func(tx *redis.Tx) error {
+		// Check if feed exists
+		exists, err := tx.Exists(ctx, feedKey).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check feed existence: %w", err)
+		}
+		if exists == 0 {
+			return fmt.Errorf("feed %s not found", feedID)
+		}
+		ownerGuildID, err := tx.HGet(ctx, feedKey, "owner_guild").Result()
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("failed to get feed owner: %w", err)
+		}
 
-	return nil
+		// Find the subscribed channels and which of them lose their last feed
+		channels, err := tx.SMembers(ctx, channelsKeyFormatted).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get feed channels: %w", err)
+		}
+		emptied := make(map[string]string) // channelID -> guildID
+		for _, channelID := range channels {
+			lastFeed, guildID, err := lastChannelFeed(ctx, tx, channelID)
+			if err != nil {
+				return err
+			}
+			if lastFeed {
+				emptied[channelID] = guildID
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			// Delete feed, schedule, HTTP validators, filters, last run, prompt templates and channel index
+			pipe.Del(ctx, feedKey, scheduleKey, httpKey, filtersKey, lastRunKey, promptsKey, channelsKeyFormatted)
+			if ownerGuildID != "" {
+				pipe.SRem(ctx, fmt.Sprintf(guildFeedsKey, ownerGuildID), feedID)
+			}
+
+			// Unsubscribe its channels
+			for _, channelID := range channels {
+				pipe.SRem(ctx, fmt.Sprintf(channelFeedsKey, channelID), feedID)
+				pipe.Del(ctx, fmt.Sprintf(channelFeedFiltersKey, channelID, feedID))
+				if guildID, ok := emptied[channelID]; ok {
+					removeChannel(ctx, pipe, channelID, guildID)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to unregister feed: %w", err)
+		}
+		return nil
+	}, feedKey, channelsKeyFormatted)
+}
+
+// GetGuildFeedCount returns the number of feeds registered by a guild (catalog feeds excluded)
+func (r *RedisRSSFeedRepository) GetGuildFeedCount(guildID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	count, err := r.client.SCard(ctx, fmt.Sprintf(guildFeedsKey, guildID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count guild feeds: %w", err)
+	}
+	return int(count), nil
 }
 
 // GetFeed returns feed details by identifier
@@ -610,6 +890,10 @@ func (r *RedisRSSFeedRepository) GetFeed(feedID string) (*RSSFeed, error) {
 		Description: feedData["description"],
 		AddedAt:     time.Unix(addedAtUnix, 0),
 		Schedule:    schedule,
+		Timezone:    feedData["timezone"],
+		SkipScrape:  feedData["skip_scrape"] == "1",
+		Extractive:  feedData["extractive"] == "1",
+		OwnerGuildID: feedData["owner_guild"],
 	}
 
 	return feed, nil
@@ -633,9 +917,9 @@ func (r *RedisRSSFeedRepository) GetAllFeeds() ([]RSSFeed, error) {
 			return nil, fmt.Errorf("failed to scan feeds: %w", err)
 		}
 
-		// Filter out schedule keys
+		// Filter out per-feed sub-keys (schedule, HTTP validators, ...)
 		for _, key := range keys {
-			if !contains(key, ":schedule") {
+			if !strings.Contains(key[len(feedsPrefix):], ":") {
 				feedKeys = append(feedKeys, key)
 			}
 		}
@@ -687,10 +971,10 @@ func (r *RedisRSSFeedRepository) SetSchedule(feedID string, times []string) erro
 
 	scheduleKey := fmt.Sprintf(feedScheduleKey, feedID)
 
-	// Validate time format (HH:MM)
+	// Validate schedule entries (HH:MM, "every <duration>" or cron)
 	for _, t := range times {
-		if !isValidTime(t) {
-			return fmt.Errorf("invalid time format: %s (expected HH:MM)", t)
+		if !isValidScheduleSpec(t) {
+			return fmt.Errorf("invalid time format: %s (expected HH:MM, \"every <duration>\" or a cron expression)", t)
 		}
 	}
 
@@ -729,30 +1013,268 @@ func (r *RedisRSSFeedRepository) GetSchedule(feedID string) ([]string, error) {
 	return times, nil
 }
 
-// Helper functions
+// SetTimezone sets the IANA timezone the feed's schedule is evaluated in
+func (r *RedisRSSFeedRepository) SetTimezone(feedID, timezone string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	feedKey := feedsPrefix + feedID
+
+	exists, err := r.client.Exists(ctx, feedKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check feed existence: %w", err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("feed %s not found", feedID)
+	}
+
+	if timezone == "" {
+		err = r.client.HDel(ctx, feedKey, "timezone").Err()
+	} else {
+		err = r.client.HSet(ctx, feedKey, "timezone", timezone).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set timezone: %w", err)
+	}
+
+	return nil
+}
+
+// SetSkipScrape sets whether article pages of the feed are never scraped
+func (r *RedisRSSFeedRepository) SetSkipScrape(feedID string, skip bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	feedKey := feedsPrefix + feedID
+
+	exists, err := r.client.Exists(ctx, feedKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check feed existence: %w", err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("feed %s not found", feedID)
+	}
+
+	if skip {
+		err = r.client.HSet(ctx, feedKey, "skip_scrape", "1").Err()
+	} else {
+		err = r.client.HDel(ctx, feedKey, "skip_scrape").Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set skip scrape: %w", err)
+	}
+
+	return nil
+}
+
+// SetExtractive sets whether articles of the feed are summarized offline instead of by the AI
+func (r *RedisRSSFeedRepository) SetExtractive(feedID string, extractive bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	feedKey := feedsPrefix + feedID
+
+	exists, err := r.client.Exists(ctx, feedKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check feed existence: %w", err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("feed %s not found", feedID)
+	}
+
+	if extractive {
+		err = r.client.HSet(ctx, feedKey, "extractive", "1").Err()
+	} else {
+		err = r.client.HDel(ctx, feedKey, "extractive").Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set extractive summaries: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastRun returns when the feed was last checked successfully (zero if never)
+func (r *RedisRSSFeedRepository) GetLastRun(feedID string) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	lastRunKey := fmt.Sprintf(feedLastRunKey, feedID)
+	timestamp, err := r.client.Get(ctx, lastRunKey).Int64()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last run: %w", err)
+	}
+
+	return time.Unix(timestamp, 0), nil
+}
+
+// SetLastRun records when the feed was last checked successfully
+func (r *RedisRSSFeedRepository) SetLastRun(feedID string, t time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	lastRunKey := fmt.Sprintf(feedLastRunKey, feedID)
+	if err := r.client.Set(ctx, lastRunKey, t.Unix(), 0).Err(); err != nil {
+		return fmt.Errorf("failed to set last run: %w", err)
+	}
 
-// contains checks if a string contains a substring
-func contains(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+	return nil
+}
+
+// GetHTTPValidators returns the ETag and Last-Modified values from the feed's last fetch
+func (r *RedisRSSFeedRepository) GetHTTPValidators(feedID string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	httpKey := fmt.Sprintf(feedHTTPKey, feedID)
+	values, err := r.client.HMGet(ctx, httpKey, "etag", "last_modified").Result()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get HTTP validators: %w", err)
+	}
+
+	etag, _ := values[0].(string)
+	lastModified, _ := values[1].(string)
+	return etag, lastModified, nil
+}
+
+// SetHTTPValidators stores the ETag and Last-Modified values from the feed's last fetch.
+// Passing two empty values clears the stored validators.
+func (r *RedisRSSFeedRepository) SetHTTPValidators(feedID, etag, lastModified string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	httpKey := fmt.Sprintf(feedHTTPKey, feedID)
+
+	if etag == "" && lastModified == "" {
+		if err := r.client.Del(ctx, httpKey).Err(); err != nil {
+			return fmt.Errorf("failed to clear HTTP validators: %w", err)
 		}
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"etag":          etag,
+		"last_modified": lastModified,
+	}
+	if err := r.client.HSet(ctx, httpKey, data).Err(); err != nil {
+		return fmt.Errorf("failed to set HTTP validators: %w", err)
+	}
+
+	return nil
+}
+
+// filterListKey returns the Redis key holding the filter rules of a feed or of a channel subscription
+func filterListKey(feedID, channelID string) string {
+	if channelID == "" {
+		return fmt.Sprintf(feedFiltersKey, feedID)
 	}
-	return false
+	return fmt.Sprintf(channelFeedFiltersKey, channelID, feedID)
 }
 
-// isValidTime checks if a time string is in HH:MM format
-func isValidTime(t string) bool {
-	if len(t) != 5 || t[2] != ':' {
-		return false
+// AddFilter attaches a filter rule to a feed, or to a channel's subscription when channelID is set
+func (r *RedisRSSFeedRepository) AddFilter(feedID, channelID string, rule filter.Rule) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := rule.Validate(); err != nil {
+		return err
 	}
-	
-	var hour, minute int
-	_, err := fmt.Sscanf(t, "%02d:%02d", &hour, &minute)
+
+	key := filterListKey(feedID, channelID)
+
+	count, err := r.client.LLen(ctx, key).Result()
 	if err != nil {
-		return false
+		return fmt.Errorf("failed to count filters: %w", err)
 	}
-	
-	return hour >= 0 && hour < 24 && minute >= 0 && minute < 60
+	if count >= maxFilterRules {
+		return fmt.Errorf("filter limit reached (%d/%d)", count, maxFilterRules)
+	}
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter: %w", err)
+	}
+
+	if err := r.client.RPush(ctx, key, data).Err(); err != nil {
+		return fmt.Errorf("failed to add filter: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveFilter removes the filter rule at the given 1-based position
+func (r *RedisRSSFeedRepository) RemoveFilter(feedID, channelID string, position int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if position < 1 {
+		return fmt.Errorf("filter #%d not found", position)
+	}
+
+	key := filterListKey(feedID, channelID)
+
+	value, err := r.client.LIndex(ctx, key, int64(position-1)).Result()
+	if err == redis.Nil {
+		return fmt.Errorf("filter #%d not found", position)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get filter: %w", err)
+	}
+
+	if err := r.client.LRem(ctx, key, 1, value).Err(); err != nil {
+		return fmt.Errorf("failed to remove filter: %w", err)
+	}
+
+	return nil
+}
+
+// GetFilters returns the filter rules of a feed, or of a channel's subscription when channelID is set
+func (r *RedisRSSFeedRepository) GetFilters(feedID, channelID string) ([]filter.Rule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	values, err := r.client.LRange(ctx, filterListKey(feedID, channelID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get filters: %w", err)
+	}
+
+	rules := make([]filter.Rule, 0, len(values))
+	for _, value := range values {
+		var rule filter.Rule
+		if err := json.Unmarshal([]byte(value), &rule); err != nil {
+			log.Printf("[FEED-REPO] WARNING: Skipping malformed filter for feed %s: %v", feedID, err)
+			continue
+		}
+		if err := rule.Compile(); err != nil {
+			log.Printf("[FEED-REPO] WARNING: Skipping invalid filter for feed %s: %v", feedID, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// ClearFilters removes all filter rules of a feed, or of a channel's subscription when channelID is set
+func (r *RedisRSSFeedRepository) ClearFilters(feedID, channelID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := r.client.Del(ctx, filterListKey(feedID, channelID)).Err(); err != nil {
+		return fmt.Errorf("failed to clear filters: %w", err)
+	}
+
+	return nil
+}
+
+// Helper functions
+
+// isValidScheduleSpec checks if a schedule entry can be parsed
+func isValidScheduleSpec(spec string) bool {
+	_, err := schedule.Parse(spec)
+	return err == nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/GustavoLR548/godot-news-bot/internal/filter"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -72,6 +73,14 @@ func TestRedisChannelRepository_AddChannel(t *testing.T) {
 			expectError:   true,
 			errorContains: "limit reached",
 		},
+		{
+			name:         "no global limit when max limit is 0",
+			maxLimit:     0,
+			existingChs:  map[string][]string{"111": {"feed1"}, "222": {"feed2"}, "333": {"feed3"}},
+			channelToAdd: "444",
+			feedToAdd:    "feed4",
+			expectError:  false,
+		},
 		{
 			name: "allow adding feed to existing channel at limit",
 			maxLimit:     3,
@@ -92,13 +101,13 @@ func TestRedisChannelRepository_AddChannel(t *testing.T) {
 			// Seed existing channels with feeds
 			for chID, feedIDs := range tt.existingChs {
 				for _, feedID := range feedIDs {
-					err := repo.AddChannel(chID, feedID)
+					err := repo.AddChannel("guild1", chID, feedID, 0)
 					require.NoError(t, err)
 				}
 			}
 
 			// Test add operation
-			err = repo.AddChannel(tt.channelToAdd, tt.feedToAdd)
+			err = repo.AddChannel("guild1", tt.channelToAdd, tt.feedToAdd, 0)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -164,7 +173,7 @@ func TestRedisChannelRepository_RemoveChannel(t *testing.T) {
 			// Seed channels
 			for chID, feedIDs := range tt.existingChs {
 				for _, feedID := range feedIDs {
-					err := repo.AddChannel(chID, feedID)
+					err := repo.AddChannel("guild1", chID, feedID, 0)
 					require.NoError(t, err)
 				}
 			}
@@ -198,6 +207,53 @@ func TestRedisChannelRepository_RemoveChannel(t *testing.T) {
 	}
 }
 
+// TestRedisChannelRepository_GuildChannelCount tests counting the channels of each guild
+func TestRedisChannelRepository_GuildChannelCount(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo, err := NewRedisChannelRepository(client, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.AddChannel("guild1", "ch1", "feed1", 0))
+	require.NoError(t, repo.AddChannel("guild1", "ch1", "feed2", 0))
+	require.NoError(t, repo.AddChannel("guild1", "ch2", "feed1", 0))
+	require.NoError(t, repo.AddChannel("guild2", "ch3", "feed1", 0))
+
+	count, err := repo.GetGuildChannelCount("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "a channel counts once, whatever its number of feeds")
+
+	count, err = repo.GetGuildChannelCount("guild2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// A channel only stops counting once its last feed is removed
+	require.NoError(t, repo.RemoveChannel("ch1", "feed1"))
+	count, err = repo.GetGuildChannelCount("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, repo.RemoveChannel("ch1", "feed2"))
+	count, err = repo.GetGuildChannelCount("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = repo.GetGuildChannelCount("unknown")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	channels, err := repo.GetGuildChannels("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch2"}, channels)
+
+	guildID, err := repo.GetChannelGuild("ch2")
+	require.NoError(t, err)
+	assert.Equal(t, "guild1", guildID)
+
+	guildID, err = repo.GetChannelGuild("ch1")
+	require.NoError(t, err)
+	assert.Empty(t, guildID, "the guild is forgotten with the channel's last feed")
+}
+
 // TestRedisChannelRepository_GetChannelFeeds tests retrieving feeds for a channel
 func TestRedisChannelRepository_GetChannelFeeds(t *testing.T) {
 	_, client := setupTestRedis(t)
@@ -205,11 +261,11 @@ func TestRedisChannelRepository_GetChannelFeeds(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add channel with multiple feeds
-	err = repo.AddChannel("ch1", "feed1")
+	err = repo.AddChannel("guild1", "ch1", "feed1", 0)
 	require.NoError(t, err)
-	err = repo.AddChannel("ch1", "feed2")
+	err = repo.AddChannel("guild1", "ch1", "feed2", 0)
 	require.NoError(t, err)
-	err = repo.AddChannel("ch1", "feed3")
+	err = repo.AddChannel("guild1", "ch1", "feed3", 0)
 	require.NoError(t, err)
 
 	// Get feeds
@@ -233,11 +289,11 @@ func TestRedisChannelRepository_GetFeedChannels(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add multiple channels to same feed
-	err = repo.AddChannel("ch1", "godot-official")
+	err = repo.AddChannel("guild1", "ch1", "godot-official", 0)
 	require.NoError(t, err)
-	err = repo.AddChannel("ch2", "godot-official")
+	err = repo.AddChannel("guild1", "ch2", "godot-official", 0)
 	require.NoError(t, err)
-	err = repo.AddChannel("ch3", "other-feed")
+	err = repo.AddChannel("guild1", "ch3", "other-feed", 0)
 	require.NoError(t, err)
 
 	// Get channels for feed
@@ -251,6 +307,12 @@ func TestRedisChannelRepository_GetFeedChannels(t *testing.T) {
 	channels, err = repo.GetFeedChannels("nonexistent")
 	require.NoError(t, err)
 	assert.Len(t, channels, 0)
+
+	// Unsubscribing updates the index
+	require.NoError(t, repo.RemoveChannel("ch1", "godot-official"))
+	channels, err = repo.GetFeedChannels("godot-official")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch2"}, channels)
 }
 
 // TestRedisRSSFeedRepository_RegisterFeed tests feed registration
@@ -316,6 +378,39 @@ func TestRedisRSSFeedRepository_RegisterFeed(t *testing.T) {
 	}
 }
 
+// TestRedisRSSFeedRepository_OwnerGuild tests guild-owned and catalog feeds
+func TestRedisRSSFeedRepository_OwnerGuild(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisRSSFeedRepository(client)
+
+	require.NoError(t, repo.RegisterFeed(RSSFeed{ID: "catalog", URL: "https://example.com/a.xml", AddedAt: time.Now()}))
+	require.NoError(t, repo.RegisterFeed(RSSFeed{ID: "private", URL: "https://example.com/b.xml", AddedAt: time.Now(), OwnerGuildID: "guild-1"}))
+
+	catalog, err := repo.GetFeed("catalog")
+	require.NoError(t, err)
+	assert.True(t, catalog.IsCatalog())
+	assert.True(t, catalog.VisibleTo("guild-1"))
+	assert.True(t, catalog.VisibleTo("guild-2"))
+
+	private, err := repo.GetFeed("private")
+	require.NoError(t, err)
+	assert.Equal(t, "guild-1", private.OwnerGuildID)
+	assert.False(t, private.IsCatalog())
+	assert.True(t, private.VisibleTo("guild-1"))
+	assert.False(t, private.VisibleTo("guild-2"))
+	assert.False(t, private.VisibleTo(""))
+
+	// Only the guild's own feeds count against its quota
+	count, err := repo.GetGuildFeedCount("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	require.NoError(t, repo.UnregisterFeed("private"))
+	count, err = repo.GetGuildFeedCount("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
 // TestRedisRSSFeedRepository_DuplicateFeed tests duplicate feed registration
 func TestRedisRSSFeedRepository_DuplicateFeed(t *testing.T) {
 	_, client := setupTestRedis(t)
@@ -370,6 +465,39 @@ func TestRedisRSSFeedRepository_UnregisterFeed(t *testing.T) {
 	assert.Len(t, schedule, 0)
 }
 
+// TestRedisRSSFeedRepository_UnregisterFeed_UnsubscribesChannels tests that removing a feed
+// removes its subscriptions and the channels left without feeds
+func TestRedisRSSFeedRepository_UnregisterFeed_UnsubscribesChannels(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	repo := NewRedisRSSFeedRepository(client)
+	channelRepo, err := NewRedisChannelRepository(client, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RegisterFeed(RSSFeed{ID: "team-blog", URL: "https://example.com/rss"}))
+	require.NoError(t, channelRepo.AddChannel("guild1", "ch1", "team-blog", 0))
+	require.NoError(t, channelRepo.AddChannel("guild1", "ch2", "team-blog", 0))
+	require.NoError(t, channelRepo.AddChannel("guild1", "ch2", "godot-official", 0))
+	require.NoError(t, repo.AddFilter("team-blog", "ch1", filter.Rule{Action: filter.ActionInclude, Type: filter.TypeKeyword, Value: "release"}))
+
+	require.NoError(t, repo.UnregisterFeed("team-blog"))
+
+	channels, err := channelRepo.GetFeedChannels("team-blog")
+	require.NoError(t, err)
+	assert.Empty(t, channels)
+	assert.False(t, mr.Exists("news:channels:ch1:filters:team-blog"))
+
+	// ch1 had no other feed and is gone; ch2 keeps its other subscription
+	allChannels, err := channelRepo.GetAllChannels()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch2"}, allChannels)
+	feeds, err := channelRepo.GetChannelFeeds("ch2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"godot-official"}, feeds)
+	count, err := channelRepo.GetGuildChannelCount("guild1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
 // TestRedisFeedRepository_GetAllFeeds tests retrieving all feeds
 func TestRedisRSSFeedRepository_GetAllFeeds(t *testing.T) {
 	_, client := setupTestRedis(t)
@@ -513,8 +641,236 @@ func TestRedisRSSFeedRepository_UpdateSchedule(t *testing.T) {
 	assert.Equal(t, newSchedule, schedule)
 }
 
-// TestIsValidTime tests time validation helper
-func TestIsValidTime(t *testing.T) {
+// TestRedisRSSFeedRepository_HTTPValidators tests ETag/Last-Modified storage
+func TestRedisRSSFeedRepository_Timezone(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisRSSFeedRepository(client)
+
+	feed := RSSFeed{ID: "feed1", URL: "http://example.com/rss", Title: "Feed 1", AddedAt: time.Now(), Timezone: "America/Sao_Paulo"}
+	require.NoError(t, repo.RegisterFeed(feed))
+
+	retrieved, err := repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.Equal(t, "America/Sao_Paulo", retrieved.Timezone)
+
+	// Change timezone
+	require.NoError(t, repo.SetTimezone("feed1", "Europe/Berlin"))
+	retrieved, err = repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", retrieved.Timezone)
+
+	// Empty resets to local time
+	require.NoError(t, repo.SetTimezone("feed1", ""))
+	retrieved, err = repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.Timezone)
+
+	// Unknown feed
+	assert.Error(t, repo.SetTimezone("missing", "Europe/Berlin"))
+}
+
+func TestRedisRSSFeedRepository_SkipScrape(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisRSSFeedRepository(client)
+
+	feed := RSSFeed{ID: "feed1", URL: "http://example.com/rss", Title: "Feed 1", AddedAt: time.Now()}
+	require.NoError(t, repo.RegisterFeed(feed))
+
+	retrieved, err := repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.False(t, retrieved.SkipScrape)
+
+	require.NoError(t, repo.SetSkipScrape("feed1", true))
+	retrieved, err = repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.True(t, retrieved.SkipScrape)
+
+	require.NoError(t, repo.SetSkipScrape("feed1", false))
+	retrieved, err = repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.False(t, retrieved.SkipScrape)
+
+	// Registered with scraping disabled
+	feed2 := RSSFeed{ID: "feed2", URL: "http://example.com/rss2", Title: "Feed 2", AddedAt: time.Now(), SkipScrape: true}
+	require.NoError(t, repo.RegisterFeed(feed2))
+	retrieved, err = repo.GetFeed("feed2")
+	require.NoError(t, err)
+	assert.True(t, retrieved.SkipScrape)
+
+	// Unknown feed
+	assert.Error(t, repo.SetSkipScrape("missing", true))
+}
+
+func TestRedisRSSFeedRepository_Extractive(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisRSSFeedRepository(client)
+
+	feed := RSSFeed{ID: "feed1", URL: "http://example.com/rss", Title: "Feed 1", AddedAt: time.Now()}
+	require.NoError(t, repo.RegisterFeed(feed))
+
+	retrieved, err := repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.False(t, retrieved.Extractive)
+
+	require.NoError(t, repo.SetExtractive("feed1", true))
+	retrieved, err = repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.True(t, retrieved.Extractive)
+
+	require.NoError(t, repo.SetExtractive("feed1", false))
+	retrieved, err = repo.GetFeed("feed1")
+	require.NoError(t, err)
+	assert.False(t, retrieved.Extractive)
+
+	// Unknown feed
+	assert.Error(t, repo.SetExtractive("missing", true))
+}
+
+func TestRedisRSSFeedRepository_LastRun(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisRSSFeedRepository(client)
+
+	feed := RSSFeed{ID: "feed1", URL: "http://example.com/rss", Title: "Feed 1", AddedAt: time.Now()}
+	require.NoError(t, repo.RegisterFeed(feed))
+
+	// Never run
+	lastRun, err := repo.GetLastRun("feed1")
+	require.NoError(t, err)
+	assert.True(t, lastRun.IsZero())
+
+	now := time.Now()
+	require.NoError(t, repo.SetLastRun("feed1", now))
+	lastRun, err = repo.GetLastRun("feed1")
+	require.NoError(t, err)
+	assert.WithinDuration(t, now, lastRun, time.Second)
+
+	// The last run key must not show up as a feed and is removed with the feed
+	allFeeds, err := repo.GetAllFeeds()
+	require.NoError(t, err)
+	assert.Len(t, allFeeds, 1)
+
+	require.NoError(t, repo.UnregisterFeed("feed1"))
+	lastRun, err = repo.GetLastRun("feed1")
+	require.NoError(t, err)
+	assert.True(t, lastRun.IsZero())
+}
+
+func TestRedisRSSFeedRepository_HTTPValidators(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisRSSFeedRepository(client)
+
+	feed := RSSFeed{ID: "feed1", URL: "http://example.com/rss", Title: "Feed 1", AddedAt: time.Now()}
+	require.NoError(t, repo.RegisterFeed(feed))
+
+	// Nothing stored yet
+	etag, lastModified, err := repo.GetHTTPValidators("feed1")
+	require.NoError(t, err)
+	assert.Empty(t, etag)
+	assert.Empty(t, lastModified)
+
+	// Store validators
+	require.NoError(t, repo.SetHTTPValidators("feed1", `"abc123"`, "Mon, 01 Jan 2024 12:00:00 GMT"))
+	etag, lastModified, err = repo.GetHTTPValidators("feed1")
+	require.NoError(t, err)
+	assert.Equal(t, `"abc123"`, etag)
+	assert.Equal(t, "Mon, 01 Jan 2024 12:00:00 GMT", lastModified)
+
+	// The validators key must not show up as a feed
+	allFeeds, err := repo.GetAllFeeds()
+	require.NoError(t, err)
+	assert.Len(t, allFeeds, 1)
+
+	// Clearing removes both values
+	require.NoError(t, repo.SetHTTPValidators("feed1", "", ""))
+	etag, lastModified, err = repo.GetHTTPValidators("feed1")
+	require.NoError(t, err)
+	assert.Empty(t, etag)
+	assert.Empty(t, lastModified)
+
+	// Unregistering the feed removes its validators
+	require.NoError(t, repo.SetHTTPValidators("feed1", `"abc123"`, ""))
+	require.NoError(t, repo.UnregisterFeed("feed1"))
+	etag, _, err = repo.GetHTTPValidators("feed1")
+	require.NoError(t, err)
+	assert.Empty(t, etag)
+}
+
+// TestRedisRSSFeedRepository_Filters tests feed and subscription filter management
+func TestRedisRSSFeedRepository_Filters(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisRSSFeedRepository(client)
+
+	require.NoError(t, repo.RegisterFeed(RSSFeed{ID: "feed1", URL: "http://example.com/rss", AddedAt: time.Now()}))
+
+	releases := filter.Rule{Action: filter.ActionInclude, Type: filter.TypeCategory, Value: "Release"}
+	noBeta := filter.Rule{Action: filter.ActionExclude, Type: filter.TypeKeyword, Value: "beta"}
+
+	// Feed-level rules
+	require.NoError(t, repo.AddFilter("feed1", "", releases))
+	require.NoError(t, repo.AddFilter("feed1", "", noBeta))
+
+	// Subscription-level rules are kept separately
+	require.NoError(t, repo.AddFilter("feed1", "channel1", noBeta))
+
+	rules, err := repo.GetFilters("feed1", "")
+	require.NoError(t, err)
+	assert.Equal(t, []filter.Rule{releases, noBeta}, rules)
+
+	rules, err = repo.GetFilters("feed1", "channel1")
+	require.NoError(t, err)
+	assert.Equal(t, []filter.Rule{noBeta}, rules)
+
+	// Invalid rules are rejected
+	err = repo.AddFilter("feed1", "", filter.Rule{Action: filter.ActionExclude, Type: filter.TypeRegex, Value: "("})
+	assert.Error(t, err)
+
+	// Remove by position
+	require.NoError(t, repo.RemoveFilter("feed1", "", 1))
+	rules, err = repo.GetFilters("feed1", "")
+	require.NoError(t, err)
+	assert.Equal(t, []filter.Rule{noBeta}, rules)
+
+	assert.Error(t, repo.RemoveFilter("feed1", "", 5))
+	assert.Error(t, repo.RemoveFilter("feed1", "", 0))
+
+	// Filter keys must not show up as feeds
+	allFeeds, err := repo.GetAllFeeds()
+	require.NoError(t, err)
+	assert.Len(t, allFeeds, 1)
+
+	// Regex rules come back compiled
+	require.NoError(t, repo.AddFilter("feed1", "channel2", filter.Rule{Action: filter.ActionInclude, Type: filter.TypeRegex, Value: `4\.\d`}))
+	rules, err = repo.GetFilters("feed1", "channel2")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.True(t, rules[0].Matches(filter.Item{Title: "Godot 4.3"}))
+
+	// Clear
+	require.NoError(t, repo.ClearFilters("feed1", "channel1"))
+	rules, err = repo.GetFilters("feed1", "channel1")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+// TestRedisChannelRepository_RemoveChannel_ClearsFilters tests that unsubscribing drops subscription filters
+func TestRedisChannelRepository_RemoveChannel_ClearsFilters(t *testing.T) {
+	_, client := setupTestRedis(t)
+	channelRepo, err := NewRedisChannelRepository(client, 5)
+	require.NoError(t, err)
+	feedRepo := NewRedisRSSFeedRepository(client)
+
+	require.NoError(t, channelRepo.AddChannel("guild1", "channel1", "feed1", 0))
+	require.NoError(t, feedRepo.AddFilter("feed1", "channel1", filter.Rule{Action: filter.ActionExclude, Type: filter.TypeKeyword, Value: "beta"}))
+
+	require.NoError(t, channelRepo.RemoveChannel("channel1", "feed1"))
+
+	rules, err := feedRepo.GetFilters("feed1", "channel1")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+// TestIsValidScheduleSpec tests schedule validation helper
+func TestIsValidScheduleSpec(t *testing.T) {
 	tests := []struct {
 		time  string
 		valid bool
@@ -530,11 +886,15 @@ func TestIsValidTime(t *testing.T) {
 		{"1200", false},   // Missing colon
 		{"12:00:00", false}, // Too many parts
 		{"abc", false},    // Invalid format
+		{"every 2h", true},
+		{"09:00 mon-fri", true},
+		{"*/30 9-18 * * 1-5", true},
+		{"every 30s", false}, // Below one minute
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.time, func(t *testing.T) {
-			result := isValidTime(tt.time)
+			result := isValidScheduleSpec(tt.time)
 			assert.Equal(t, tt.valid, result)
 		})
 	}
@@ -661,6 +1021,33 @@ func TestRedisRSSHistoryRepository_EmptyState(t *testing.T) {
 	assert.Equal(t, "new-guid", lastGUID)
 }
 
+// TestRedisRSSHistoryRepository_ClearPending tests emptying a feed's pending queue
+func TestRedisRSSHistoryRepository_ClearPending(t *testing.T) {
+	_, client := setupTestRedis(t)
+	repo := NewRedisRSSHistoryRepository(client)
+
+	require.NoError(t, repo.AddToPending("feed1", "guid-1"))
+	require.NoError(t, repo.AddToPending("feed1", "guid-2"))
+	require.NoError(t, repo.AddToPending("feed2", "guid-3"))
+
+	cleared, err := repo.ClearPending("feed1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cleared)
+
+	pending, err := repo.GetPending("feed1")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// Other feeds keep their queue
+	pending, err = repo.GetPending("feed2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"guid-3"}, pending)
+
+	cleared, err = repo.ClearPending("feed1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, cleared)
+}
+
 // TestRedisChannelRepository_GuildLanguage tests guild-level language settings
 func TestRedisChannelRepository_GuildLanguage(t *testing.T) {
 	tests := []struct {
@@ -800,6 +1187,21 @@ func TestRedisChannelRepository_GetChannelLanguage_EmptyValue(t *testing.T) {
 	assert.Equal(t, "", language, "Should return empty string when no channel override is set")
 }
 
+// TestRedisChannelRepository_ClearChannelLanguage tests that clearing a channel language removes the key
+func TestRedisChannelRepository_ClearChannelLanguage(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	repo, err := NewRedisChannelRepository(client, 5)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.SetChannelLanguage("channel-1", "fr"))
+	require.NoError(t, repo.SetChannelLanguage("channel-1", ""))
+
+	language, err := repo.GetChannelLanguage("channel-1")
+	require.NoError(t, err)
+	assert.Equal(t, "", language)
+	assert.False(t, mr.Exists("news:channels:channel-1:language"))
+}
+
 // TestRedisChannelRepository_LanguageUpdate tests updating existing language
 func TestRedisChannelRepository_LanguageUpdate(t *testing.T) {
 	_, client := setupTestRedis(t)
@@ -827,6 +1229,34 @@ func TestRedisChannelRepository_LanguageUpdate(t *testing.T) {
 	assert.Equal(t, "ja", lang)
 }
 
+// TestRedisChannelRepository_ChannelStyle tests per-channel summary styles
+func TestRedisChannelRepository_ChannelStyle(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	repo, err := NewRedisChannelRepository(client, 5)
+	require.NoError(t, err)
+
+	style, err := repo.GetChannelStyle("channel-1")
+	require.NoError(t, err)
+	assert.Equal(t, "", style, "Channel with no style should return empty")
+
+	require.NoError(t, repo.SetChannelStyle("channel-1", "tldr"))
+	require.NoError(t, repo.SetChannelStyle("channel-2", "bullet"))
+
+	style, err = repo.GetChannelStyle("channel-1")
+	require.NoError(t, err)
+	assert.Equal(t, "tldr", style)
+	style, err = repo.GetChannelStyle("channel-2")
+	require.NoError(t, err)
+	assert.Equal(t, "bullet", style)
+
+	// Clearing the style removes the key
+	require.NoError(t, repo.SetChannelStyle("channel-1", ""))
+	style, err = repo.GetChannelStyle("channel-1")
+	require.NoError(t, err)
+	assert.Equal(t, "", style)
+	assert.False(t, mr.Exists("news:channels:channel-1:style"))
+}
+
 // TestRedisChannelRepository_LanguageHierarchy tests the full language detection hierarchy
 func TestRedisChannelRepository_LanguageHierarchy(t *testing.T) {
 	_, client := setupTestRedis(t)
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	summaryCacheKey = "summaries:%s:%s:%s:%s:%s" // summaries:{feedID}:{promptVersion}:{style}:{language}:{guid} (JSON SummaryResponse)
+	summaryCacheTTL = 7 * 24 * time.Hour
+)
+
+// SummaryCache stores generated article summaries so re-posts, retries and newly
+// subscribed channels don't call the AI again
+type SummaryCache interface {
+	// GetSummary returns the cached summary of an article in a language and style, or nil when there is none
+	GetSummary(feedID, guid, language, style, promptVersion string) (*ai.SummaryResponse, error)
+	// SaveSummary caches the summary of an article in a language and style
+	SaveSummary(feedID, guid, language, style, promptVersion string, summary *ai.SummaryResponse) error
+}
+
+// RedisSummaryCache implements SummaryCache using Redis
+type RedisSummaryCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisSummaryCache creates a new Redis-based summary cache; summaries expire after 7 days
+func NewRedisSummaryCache(client *redis.Client) *RedisSummaryCache {
+	return &RedisSummaryCache{
+		client: client,
+		ttl:    summaryCacheTTL,
+	}
+}
+
+// GetSummary returns the cached summary of an article in a language and style, or nil when there is none
+func (c *RedisSummaryCache) GetSummary(feedID, guid, language, style, promptVersion string) (*ai.SummaryResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, fmt.Sprintf(summaryCacheKey, feedID, promptVersion, style, language, guid)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached summary: %w", err)
+	}
+
+	var summary ai.SummaryResponse
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse cached summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// SaveSummary caches the summary of an article in a language and style
+func (c *RedisSummaryCache) SaveSummary(feedID, guid, language, style, promptVersion string, summary *ai.SummaryResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to serialize summary: %w", err)
+	}
+
+	if err := c.client.Set(ctx, fmt.Sprintf(summaryCacheKey, feedID, promptVersion, style, language, guid), data, c.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache summary: %w", err)
+	}
+	return nil
+}
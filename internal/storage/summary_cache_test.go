@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GustavoLR548/godot-news-bot/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisSummaryCache(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	cache := NewRedisSummaryCache(client)
+
+	summary, err := cache.GetSummary("godot", "guid-1", "en", "standard", "1")
+	require.NoError(t, err)
+	assert.Nil(t, summary)
+
+	require.NoError(t, cache.SaveSummary("godot", "guid-1", "en", "standard", "1", &ai.SummaryResponse{
+		TranslatedTitle: "Godot 4.4 released",
+		Summary:         "Godot 4.4 brings typed dictionaries.",
+	}))
+
+	summary, err = cache.GetSummary("godot", "guid-1", "en", "standard", "1")
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+	assert.Equal(t, "Godot 4.4 released", summary.TranslatedTitle)
+	assert.Equal(t, "Godot 4.4 brings typed dictionaries.", summary.Summary)
+
+	// Other languages, styles, prompt versions, articles and feeds are cached separately
+	for _, key := range [][5]string{
+		{"godot", "guid-1", "pt-BR", "standard", "1"},
+		{"godot", "guid-1", "en", "tldr", "1"},
+		{"godot", "guid-1", "en", "standard", "2"},
+		{"godot", "guid-2", "en", "standard", "1"},
+		{"gdquest", "guid-1", "en", "standard", "1"},
+	} {
+		summary, err := cache.GetSummary(key[0], key[1], key[2], key[3], key[4])
+		require.NoError(t, err)
+		assert.Nil(t, summary, "%v", key)
+	}
+
+	// Summaries expire
+	mr.FastForward(summaryCacheTTL + time.Hour)
+	summary, err = cache.GetSummary("godot", "guid-1", "en", "standard", "1")
+	require.NoError(t, err)
+	assert.Nil(t, summary)
+}